@@ -18,6 +18,8 @@ func (c cmd) String() string {
 func TestCorrectCli(t *testing.T) {
 	cases := []cmd{
 		{"gosible", "play", "playbook.yml", "-i", "inventory.txt"},
+		{"gosible", "play", "playbook.yml", "-i", "inventory.txt", "--limit", "webservers:!db[0]"},
+		{"gosible", "play", "playbook.yml", "-i", "inventory.txt", "-l", "@playbook.retry"},
 	}
 	mockNewPlaybookCommand()
 	defer fixNewPlaybookCommand()
@@ -36,6 +38,21 @@ func TestWrongCli(t *testing.T) {
 		{"gosible", "play", "playbook.yml"},
 		{"gosible", "play", "playbook.yml", "i"},
 		{"gosible", "play", "i", "inventory.txt"},
+		{"gosible", "agent", "gc"},
+		{"gosible", "agent", "gc", "extra", "-i", "inventory.txt"},
+		{"gosible", "vault", "create"},
+		{"gosible", "vault", "view"},
+		{"gosible", "vault", "rekey"},
+		{"gosible", "vault", "encrypt", "--unknown-flag"},
+		{"gosible", "inventory", "--list"},
+		{"gosible", "inventory", "-i", "inventory.txt"},
+		{"gosible", "inventory", "-i", "inventory.txt", "--list", "--graph"},
+		{"gosible", "inventory", "-i", "inventory.txt", "--list", "webservers"},
+		{"gosible", "inventory", "-i", "inventory.txt", "--list", "--vars"},
+		{"gosible", "inventory", "-i", "inventory.txt", "--graph", "a", "b"},
+		{"gosible", "adhoc", "-m", "ping", "-i", "inventory.txt"},
+		{"gosible", "adhoc", "all", "-m", "ping"},
+		{"gosible", "adhoc", "all", "web", "-i", "inventory.txt"},
 	}
 	mockNewPlaybookCommand()
 	defer fixNewPlaybookCommand()
@@ -4,10 +4,18 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/command/adhoc"
+	"github.com/scylladb/gosible/command/agent"
+	"github.com/scylladb/gosible/command/inventory"
 	"github.com/scylladb/gosible/command/playbook"
+	"github.com/scylladb/gosible/command/vault"
 )
 
 var newPlaybookCommand = playbook.NewCommand
+var newAdhocCommand = adhoc.NewCommand
+var newAgentCommand = agent.NewCommand
+var newInventoryCommand = inventory.NewCommand
+var newVaultCommand = vault.NewCommand
 
 func NewCommand(app *command.App) *cobra.Command {
 	cmd := &cobra.Command{
@@ -17,6 +25,10 @@ func NewCommand(app *command.App) *cobra.Command {
 	}
 
 	cmd.AddCommand(newPlaybookCommand(app))
+	cmd.AddCommand(newAdhocCommand(app))
+	cmd.AddCommand(newAgentCommand(app))
+	cmd.AddCommand(newInventoryCommand(app))
+	cmd.AddCommand(newVaultCommand(app))
 
 	app.Register(cmd)
 
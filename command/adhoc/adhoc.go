@@ -0,0 +1,228 @@
+package adhoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/connection"
+	"github.com/scylladb/gosible/executor"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/parsing/vault"
+	"github.com/scylladb/gosible/playbook"
+	playbookTypes "github.com/scylladb/gosible/playbook/types"
+	defaultPlugins "github.com/scylladb/gosible/plugins/default"
+	"github.com/scylladb/gosible/utils/display"
+	varsPkg "github.com/scylladb/gosible/vars"
+	"github.com/spf13/cobra"
+	"strings"
+	"sync"
+)
+
+func NewCommand(app *command.App) *cobra.Command {
+	c := &adhocCmd{App: app}
+	cmd := &cobra.Command{
+		Use:   "adhoc <pattern>",
+		Short: "Run a single module on the hosts matching the pattern",
+		Long: "Run a single task on the hosts matching the pattern, like the ansible command. " +
+			"The result on each host is printed on one line.",
+		Example: "gosible adhoc all -i inventory.txt -m ping\n" +
+			"  gosible adhoc webservers -i inventory.txt -a 'uptime'\n" +
+			"  gosible adhoc db -i inventory.txt -b -m service -a 'name=scylla-server state=restarted'",
+		Args: cobra.ExactArgs(1),
+		RunE: c.run,
+	}
+
+	c.register(cmd)
+	return cmd
+}
+
+type adhocCmd struct {
+	*command.App
+
+	inventorySources []string // -i, --inventory
+	limit            string   // -l, --limit
+	extraVars        []string // -e, --extra-vars
+	moduleName       string   // -m, --module-name
+	moduleArgs       string   // -a, --args
+	vaultOptions     command.VaultOptions
+	becomeOptions    command.BecomeOptions
+
+	lock   sync.Mutex
+	failed []string
+}
+
+func (c *adhocCmd) register(cmd *cobra.Command) {
+	settings := config.Manager().Settings
+	cmd.Flags().StringArrayVarP(&c.inventorySources, "inventory", "i", nil, "specify inventory host path or comma separated host list, may be given many times")
+	cobra.CheckErr(cmd.MarkFlagFilename("inventory", "txt"))
+	cobra.CheckErr(cmd.MarkFlagRequired("inventory"))
+	cmd.Flags().StringVarP(&c.limit, "limit", "l", "", "further limit selected hosts to an additional pattern, prepend with @ to read it from a file")
+	cmd.Flags().StringVarP(&c.moduleName, "module-name", "m", settings.DEFAULT_MODULE_NAME, "name of the module to execute")
+	cmd.Flags().StringVarP(&c.moduleArgs, "args", "a", settings.DEFAULT_MODULE_ARGS, "the module arguments as key=value pairs, or the free-form arguments of modules like command and shell")
+
+	c.becomeOptions.Register(cmd)
+	c.vaultOptions.Register(cmd)
+
+	cmd.Flags().StringSliceVarP(&c.extraVars, "extra-vars", "e", nil, "set additional variables as key=value or YAML/JSON, if filename prepend with @")
+}
+
+func (c *adhocCmd) run(_ *cobra.Command, args []string) error {
+	if err := config.Manager().TryLoadConfigFile(""); err != nil {
+		display.Fatal(display.ErrorOptions{}, "could not load config file: %s", err)
+	}
+
+	defaultPlugins.Register()
+	play, err := c.buildPlay(args[0], command.NewModuleRegistry())
+	if err != nil {
+		return err
+	}
+
+	vaultSecrets, err := c.vaultOptions.Secrets()
+	if err != nil {
+		return fmt.Errorf("error loading vault secrets: %w", err)
+	}
+	vault.SetSecrets(vaultSecrets)
+
+	inventoryData, err := inventory.Parse(c.inventorySources...)
+	if err != nil {
+		return fmt.Errorf("error parsing inventory: %w", err)
+	}
+	if err = inventoryData.Limit(c.limit); err != nil {
+		return fmt.Errorf("error applying limit: %w", err)
+	}
+	varsManager := varsPkg.MakeManager(inventoryData)
+	if err = varsManager.SetExtraVars(c.extraVars); err != nil {
+		return fmt.Errorf("error setting extra vars: %w", err)
+	}
+
+	pass, err := c.becomeOptions.Passwords()
+	if err != nil {
+		return fmt.Errorf("error collecting passwords: %w", err)
+	}
+
+	if err = executor.ExecutePlay(play, inventoryData, varsManager, pass, c.printResult); err != nil {
+		return err
+	}
+	if len(c.failed) > 0 {
+		return fmt.Errorf("%d host(s) failed: %s", len(c.failed), strings.Join(c.failed, ", "))
+	}
+	return nil
+}
+
+// buildPlay returns the play running the module with the arguments on the hosts matching the pattern.
+func (c *adhocCmd) buildPlay(pattern string, mods *modules.ModuleRegistry) (*playbookTypes.Play, error) {
+	task := &playbookTypes.Task{
+		Name:     c.moduleName,
+		Keywords: map[string]interface{}{c.moduleName: c.moduleArgs},
+	}
+	if c.becomeOptions.Become {
+		task.Keywords["become"] = true
+		task.Keywords["become_method"] = c.becomeOptions.BecomeMethod
+		if c.becomeOptions.BecomeUser != "" {
+			task.Keywords["become_user"] = c.becomeOptions.BecomeUser
+		}
+	}
+
+	action, args, delegateTo, err := playbook.ResolveModuleArgs(task, mods)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't resolve module %s: %w", c.moduleName, err)
+	}
+	if playbook.IsFreeformAction(action) && len(args) == 0 {
+		return nil, fmt.Errorf("no argument passed to %s module", action)
+	}
+	task.Action = &playbookTypes.Action{
+		Name:       action,
+		Args:       args,
+		DelegateTo: delegateTo,
+	}
+
+	// Like in Ansible, facts are not gathered for ad-hoc tasks, the setup module can be run to gather them.
+	gatherFacts := false
+	return &playbookTypes.Play{
+		Name:         "Ad-hoc",
+		HostsPattern: pattern,
+		Tasks:        []*playbookTypes.Task{task},
+		StrategyKey:  "linear",
+		GatherFacts:  &gatherFacts,
+	}, nil
+}
+
+// printResult prints the result on the host on one line, like the oneline callback of Ansible.
+func (c *adhocCmd) printResult(host *inventory.Host, result *modules.Return, err error) {
+	line, color := c.formatResult(host.Name, result, err)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err != nil || result.Failed {
+		c.failed = append(c.failed, host.Name)
+	}
+	display.Display(display.Options{Color: color}, "%s", line)
+}
+
+func (c *adhocCmd) formatResult(host string, result *modules.Return, err error) (line, color string) {
+	settings := config.Manager().Settings
+	if err != nil {
+		if connection.IsUnreachable(err) {
+			return fmt.Sprintf("%s | UNREACHABLE! => %s", host, oneLine(err.Error())), settings.COLOR_UNREACHABLE
+		}
+		return fmt.Sprintf("%s | FAILED! => %s", host, oneLine(err.Error())), settings.COLOR_ERROR
+	}
+
+	state, color := "SUCCESS", settings.COLOR_OK
+	switch {
+	case result.Failed:
+		state, color = "FAILED!", settings.COLOR_ERROR
+	case result.Skipped:
+		state, color = "SKIPPED", settings.COLOR_SKIP
+	case result.Changed:
+		state, color = "CHANGED", settings.COLOR_CHANGED
+	}
+
+	if playbook.IsFreeformAction(c.moduleName) {
+		line = fmt.Sprintf("%s | %s | rc=%d | (stdout) %s", host, state, result.Rc, oneLine(string(result.Stdout)))
+		if result.Failed {
+			line += " (stderr) " + oneLine(string(result.Stderr))
+		}
+		return line, color
+	}
+	fields, jsonErr := json.Marshal(resultFields(result))
+	if jsonErr != nil {
+		fields = []byte(oneLine(jsonErr.Error()))
+	}
+	return fmt.Sprintf("%s | %s => %s", host, state, fields), color
+}
+
+// resultFields returns the fields of the result worth showing: the common ones which are set and the ones specific
+// to the module.
+func resultFields(result *modules.Return) map[string]interface{} {
+	fields := map[string]interface{}{"changed": result.Changed}
+	if result.Failed {
+		fields["failed"] = true
+	}
+	if result.Msg != "" {
+		fields["msg"] = result.Msg
+	}
+	if result.Rc != 0 {
+		fields["rc"] = result.Rc
+	}
+	if len(result.Stdout) > 0 {
+		fields["stdout"] = string(result.Stdout)
+	}
+	if len(result.Stderr) > 0 {
+		fields["stderr"] = string(result.Stderr)
+	}
+	if specific, ok := result.ModuleSpecificReturn.(map[string]interface{}); ok {
+		for k, v := range specific {
+			fields[k] = v
+		}
+	} else if result.ModuleSpecificReturn != nil {
+		fields["result"] = result.ModuleSpecificReturn
+	}
+	return fields
+}
+
+func oneLine(s string) string {
+	return strings.ReplaceAll(strings.TrimRight(s, "\r\n"), "\n", "\\n")
+}
@@ -0,0 +1,104 @@
+package adhoc
+
+import (
+	"errors"
+	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/modules"
+	defaultModules "github.com/scylladb/gosible/modules/default"
+	"github.com/scylladb/gosible/utils/types"
+	"reflect"
+	"testing"
+)
+
+func TestBuildPlay(t *testing.T) {
+	mods := modules.NewRegistry()
+	defaultModules.Register(mods)
+
+	testCases := []struct {
+		module   string
+		args     string
+		become   command.BecomeOptions
+		expected types.Vars
+		keywords map[string]interface{}
+		valid    bool
+	}{
+		{module: "command", args: "uptime", expected: types.Vars{"_raw_params": "uptime"}, valid: true},
+		{module: "command", args: "ls -l chdir=/tmp", expected: types.Vars{"_raw_params": "ls -l", "chdir": "/tmp"}, valid: true},
+		{module: "ping", args: "data=hello", expected: types.Vars{"data": "hello"}, valid: true},
+		{module: "ping", expected: types.Vars{}, valid: true},
+		{
+			module:   "ping",
+			become:   command.BecomeOptions{Become: true, BecomeMethod: "sudo", BecomeUser: "scylla"},
+			expected: types.Vars{},
+			keywords: map[string]interface{}{"ping": "", "become": true, "become_method": "sudo", "become_user": "scylla"},
+			valid:    true,
+		},
+		{module: "command"},
+		{module: "unknown", args: "a=b"},
+	}
+
+	for _, tc := range testCases {
+		c := &adhocCmd{moduleName: tc.module, moduleArgs: tc.args, becomeOptions: tc.become}
+		play, err := c.buildPlay("webservers", mods)
+		if (err == nil) != tc.valid {
+			t.Error("on", tc.module, tc.args, "expected valid", tc.valid, "got", err)
+			continue
+		}
+		if !tc.valid {
+			continue
+		}
+		if play.HostsPattern != "webservers" || len(play.Tasks) != 1 {
+			t.Error("on", tc.module, tc.args, "unexpected play", play)
+			continue
+		}
+		task := play.Tasks[0]
+		if task.Action.Name != tc.module || !reflect.DeepEqual(task.Action.Args, tc.expected) {
+			t.Error("on", tc.module, tc.args, "expected", tc.module, tc.expected, "got", task.Action.Name, task.Action.Args)
+		}
+		if tc.keywords != nil && !reflect.DeepEqual(task.Keywords, tc.keywords) {
+			t.Error("on", tc.module, tc.args, "expected keywords", tc.keywords, "got", task.Keywords)
+		}
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	testCases := []struct {
+		module   string
+		result   *modules.Return
+		err      error
+		expected string
+	}{
+		{
+			module:   "command",
+			result:   &modules.Return{Changed: true, Stdout: []byte("line1\nline2\n")},
+			expected: `web1 | CHANGED | rc=0 | (stdout) line1\nline2`,
+		},
+		{
+			module:   "shell",
+			result:   &modules.Return{Failed: true, Rc: 2, Stderr: []byte("no such file\n")},
+			expected: `web1 | FAILED! | rc=2 | (stdout)  (stderr) no such file`,
+		},
+		{
+			module:   "ping",
+			result:   &modules.Return{ModuleSpecificReturn: map[string]interface{}{"Ping": "pong"}},
+			expected: `web1 | SUCCESS => {"Ping":"pong","changed":false}`,
+		},
+		{
+			module:   "service",
+			result:   &modules.Return{Failed: true, Msg: "no service"},
+			expected: `web1 | FAILED! => {"changed":false,"failed":true,"msg":"no service"}`,
+		},
+		{
+			module:   "ping",
+			err:      errors.New("module failed\nbadly"),
+			expected: `web1 | FAILED! => module failed\nbadly`,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := &adhocCmd{moduleName: tc.module}
+		if line, _ := c.formatResult("web1", tc.result, tc.err); line != tc.expected {
+			t.Error("on", tc.module, "expected", tc.expected, "got", line)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/connection/factory"
+	"github.com/scylladb/gosible/inventory"
+	defaultPlugins "github.com/scylladb/gosible/plugins/default"
+	"github.com/scylladb/gosible/remote"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/maps"
+	"github.com/scylladb/gosible/utils/parallel"
+	"github.com/scylladb/gosible/utils/shell"
+	varsPkg "github.com/scylladb/gosible/vars"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+func NewCommand(app *command.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Manage the remote executor installed on hosts",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(NewGcCommand(app))
+	return cmd
+}
+
+func NewGcCommand(app *command.App) *cobra.Command {
+	c := &gcCmd{App: app}
+
+	cmd := &cobra.Command{
+		Use:     "gc",
+		Short:   "Remove stale remote executor binaries and Python runtimes cached on hosts",
+		Example: "gosible agent gc -i inventory.txt",
+		Args:    cobra.NoArgs,
+		RunE:    c.run,
+	}
+
+	c.register(cmd)
+	return cmd
+}
+
+type gcCmd struct {
+	*command.App
+
+	inventorySources []string // -i, --inventory
+	all              bool     // --all
+	dryRun           bool     // --dry-run
+}
+
+func (c *gcCmd) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&c.inventorySources, "inventory", "i", nil, "specify inventory host path or comma separated host list, may be given many times")
+	cobra.CheckErr(cmd.MarkFlagFilename("inventory", "txt"))
+	cobra.CheckErr(cmd.MarkFlagRequired("inventory"))
+
+	cmd.Flags().BoolVar(&c.all, "all", false, "remove also the binaries and the Python runtime of the current gosible build")
+	cmd.Flags().BoolVar(&c.dryRun, "dry-run", false, "only list the cache directories which would be removed")
+}
+
+func (c *gcCmd) run(_ *cobra.Command, _ []string) error {
+	if err := config.Manager().TryLoadConfigFile(""); err != nil {
+		display.Fatal(display.ErrorOptions{}, "could not load config file: %s", err)
+	}
+	defaultPlugins.Register()
+
+	inventoryData, err := inventory.Parse(c.inventorySources...)
+	if err != nil {
+		display.Error(display.ErrorOptions{}, "error parsing inventory: %v", err)
+		return err
+	}
+	varsManager := varsPkg.MakeManager(inventoryData)
+
+	errs := parallel.ForAll(maps.Values(inventoryData.Hosts), func(host *inventory.Host) error {
+		return c.collect(host, varsManager)
+	})
+	if errs.IsError() {
+		return fmt.Errorf("garbage collection failed, %w", errs.Combine())
+	}
+	return nil
+}
+
+func (c *gcCmd) collect(host *inventory.Host, varsManager *varsPkg.Manager) error {
+	vars, err := varsManager.GetVars(nil, host, nil)
+	if err != nil {
+		return err
+	}
+	sh, err := shell.Get(vars)
+	if err != nil {
+		return err
+	}
+	conn, err := factory.CreateConnection(vars, sh)
+	if err != nil {
+		return fmt.Errorf("host %s: %w", host.Name, err)
+	}
+	defer conn.Close()
+
+	removed, err := remote.GarbageCollect(conn, c.all, c.dryRun)
+	if err != nil {
+		return fmt.Errorf("host %s: %w", host.Name, err)
+	}
+	verb := "removed"
+	if c.dryRun {
+		verb = "would remove"
+	}
+	if len(removed) == 0 {
+		display.Display(display.Options{}, "%s: nothing to remove", host.Name)
+	} else {
+		display.Display(display.Options{}, "%s: %s %s", host.Name, verb, strings.Join(removed, ", "))
+	}
+	return nil
+}
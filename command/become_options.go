@@ -0,0 +1,110 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	pathUtils "github.com/scylladb/gosible/utils/path"
+	"github.com/scylladb/gosible/utils/secrets"
+	"github.com/scylladb/gosible/utils/types"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"os"
+	"strings"
+)
+
+// BecomeOptions are the flags of privilege escalation and of the passwords used to connect to hosts and to become.
+type BecomeOptions struct {
+	AskBecomePassword      bool   // -K, --ask-become-pass
+	BecomePasswordFile     string // --become-password-file, --become-pass-file
+	AskConnectionPassword  bool   // -k, --ask-pass
+	ConnectionPasswordFile string // --connection-password-file, --conn-pass-file
+	Become                 bool   // --become, -b
+	BecomeMethod           string // --become-method,
+	BecomeUser             string // --become-user
+}
+
+func (o *BecomeOptions) Register(cmd *cobra.Command) {
+	o.addBecomeOptions(cmd)
+	o.addConnectionPasswordPrompt(cmd)
+}
+
+func (o *BecomeOptions) addBecomeOptions(cmd *cobra.Command) {
+	settings := config.Manager().Settings
+	cmd.Flags().BoolVarP(&o.Become, "become", "b", settings.DEFAULT_BECOME, "run operations with become (does not imply password prompting)")
+	cmd.Flags().StringVar(&o.BecomeMethod, "become-method", settings.DEFAULT_BECOME_METHOD, fmt.Sprintf("privilege escalation method to use (default=%s)\n, use `ansible-doc -t become -l` to list valid choices.", settings.DEFAULT_BECOME_METHOD))
+	cmd.Flags().StringVar(&o.BecomeUser, "become-user", "", fmt.Sprintf("run operations as this user (default=%s)", settings.DEFAULT_BECOME_USER))
+	o.addBecomePromptOptions(cmd)
+}
+
+func (o *BecomeOptions) addBecomePromptOptions(cmd *cobra.Command) {
+	defaultAsk := config.Manager().Settings.DEFAULT_BECOME_ASK_PASS
+	passwordFile := config.Manager().Settings.BECOME_PASSWORD_FILE
+	cmd.Flags().BoolVarP(&o.AskBecomePassword, "ask-become-pass", "K", defaultAsk, "ask for privilege escalation password")
+	cmd.Flags().StringVar(&o.BecomePasswordFile, "become-password-file", passwordFile, "become password file")
+	cmd.Flags().StringVar(&o.BecomePasswordFile, "become-pass-file", passwordFile, "become password file")
+	cobra.CheckErr(cmd.Flags().MarkHidden("become-pass-file"))
+}
+
+func (o *BecomeOptions) addConnectionPasswordPrompt(cmd *cobra.Command) {
+	defaultAsk := config.Manager().Settings.DEFAULT_ASK_PASS
+	passwordFile := config.Manager().Settings.CONNECTION_PASSWORD_FILE
+	cmd.Flags().BoolVarP(&o.AskConnectionPassword, "ask-pass", "k", defaultAsk, "ask for connection password")
+	cmd.Flags().StringVar(&o.ConnectionPasswordFile, "connection-password-file", passwordFile, "connection password file")
+	cmd.Flags().StringVar(&o.ConnectionPasswordFile, "conn-pass-file", passwordFile, "connection password file")
+	cobra.CheckErr(cmd.Flags().MarkHidden("conn-pass-file"))
+}
+
+// checkPasswordOptions verifies the password options, which can be checked only after the flags are parsed.
+func (o *BecomeOptions) checkPasswordOptions() error {
+	if o.AskBecomePassword && o.BecomePasswordFile != "" {
+		return errors.New("become password file and asking for the become password shouldn't be set at the same time")
+	}
+	if o.AskConnectionPassword && o.ConnectionPasswordFile != "" {
+		return errors.New("connection password file and asking for the connection password shouldn't be set at the same time")
+	}
+	return nil
+}
+
+// Passwords asks for or reads the connection and become passwords selected by the options.
+func (o *BecomeOptions) Passwords() (pass types.Passwords, err error) {
+	if err = o.checkPasswordOptions(); err != nil {
+		return
+	}
+	settings := config.Manager().Settings
+	becomePromptMethod := "BECOME"
+	if !settings.AGNOSTIC_BECOME_PROMPT {
+		becomePromptMethod = strings.ToUpper(o.BecomeMethod)
+	}
+	becomePrompt := fmt.Sprintf("%s password: ", becomePromptMethod)
+	if o.AskConnectionPassword {
+		pass.Ssh, err = getSecret("SSH password: ")
+		becomePrompt = fmt.Sprintf("%s password[defaults to SSH password]: ", becomePromptMethod)
+	} else if o.ConnectionPasswordFile != "" {
+		pass.Ssh, err = secrets.ReadPasswordFile(passwordFilePath(o.ConnectionPasswordFile))
+	}
+	if err != nil {
+		return
+	}
+	if o.AskBecomePassword {
+		pass.Become, err = getSecret(becomePrompt)
+		if err == nil && len(pass.Become) == 0 {
+			pass.Become = pass.Ssh
+		}
+	} else if o.BecomePasswordFile != "" {
+		pass.Become, err = secrets.ReadPasswordFile(passwordFilePath(o.BecomePasswordFile))
+	}
+	return
+}
+
+func passwordFilePath(path string) string {
+	if path == "-" {
+		return path
+	}
+	return pathUtils.UnfrackPath(path, pathUtils.UnfrackOptions{})
+}
+
+func getSecret(s string) ([]byte, error) {
+	fmt.Println(s)
+	return terminal.ReadPassword(int(os.Stdin.Fd()))
+}
@@ -0,0 +1,453 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/parsing/vault"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/types"
+	varsPkg "github.com/scylladb/gosible/vars"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+func NewCommand(app *command.App) *cobra.Command {
+	c := &inventoryCmd{App: app}
+	cmd := &cobra.Command{
+		Use:   "inventory [group]",
+		Short: "Show the inventory and the vars of its hosts",
+		Long: "Show the inventory as gosible resolves it, like ansible-inventory. One of --list, --host or --graph " +
+			"must be given, --graph accepts the group to show, all by default.",
+		Example: "gosible inventory -i inventory.txt --list\n" +
+			"  gosible inventory -i inventory.txt --graph --vars webservers\n" +
+			"  gosible inventory -i inventory.txt --host web1 --show-sources",
+		Args: cobra.MaximumNArgs(1),
+		RunE: c.run,
+	}
+
+	c.register(cmd)
+	return cmd
+}
+
+type inventoryCmd struct {
+	*command.App
+
+	inventorySources []string // -i, --inventory
+	limit            string   // -l, --limit
+	playbookDir      string   // --playbook-dir
+	output           string   // --output
+	list             bool     // --list
+	host             string   // --host
+	graph            bool     // --graph
+	showVars         bool     // --vars
+	showSources      bool     // --show-sources
+	vaultOptions     command.VaultOptions
+
+	inventory   *inventory.Data
+	varsManager *varsPkg.Manager
+}
+
+func (c *inventoryCmd) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&c.inventorySources, "inventory", "i", nil, "specify inventory host path or comma separated host list, may be given many times")
+	cobra.CheckErr(cmd.MarkFlagFilename("inventory", "txt"))
+	cobra.CheckErr(cmd.MarkFlagRequired("inventory"))
+	cmd.Flags().StringVarP(&c.limit, "limit", "l", "", "further limit selected hosts to an additional pattern, prepend with @ to read it from a file")
+	cmd.Flags().StringVar(&c.playbookDir, "playbook-dir", "", "directory of the playbook, whose group_vars and host_vars are loaded")
+	cmd.Flags().StringVar(&c.output, "output", "", "write the output to the file instead of the standard output")
+
+	cmd.Flags().BoolVar(&c.list, "list", false, "output all hosts info")
+	cmd.Flags().StringVar(&c.host, "host", "", "output the vars of the host")
+	cmd.Flags().BoolVar(&c.graph, "graph", false, "create inventory graph, if supplying pattern it must be a valid group name")
+	cmd.Flags().BoolVar(&c.showVars, "vars", false, "add vars to the graph display")
+	cmd.Flags().BoolVar(&c.showSources, "show-sources", false, "show the source each var of the host comes from")
+
+	c.vaultOptions.Register(cmd)
+}
+
+func (c *inventoryCmd) validate(args []string) error {
+	actions := 0
+	for _, selected := range []bool{c.list, c.host != "", c.graph} {
+		if selected {
+			actions++
+		}
+	}
+	switch {
+	case actions == 0:
+		return errors.New("no action selected, at least one of --host, --graph or --list needs to be specified")
+	case actions > 1:
+		return errors.New("conflicting options used, only one of --host, --graph or --list can be used at the same time")
+	case len(args) > 0 && !c.graph:
+		return errors.New("a group can only be given with --graph")
+	case c.showVars && !c.graph:
+		return errors.New("the --vars option can only be used with --graph")
+	case c.showSources && c.host == "":
+		return errors.New("the --show-sources option can only be used with --host")
+	}
+	switch c.Format {
+	case "json", "yaml":
+	default:
+		if !c.graph {
+			return fmt.Errorf("unsupported output format %s, expected json or yaml", c.Format)
+		}
+	}
+	return nil
+}
+
+func (c *inventoryCmd) run(cmd *cobra.Command, args []string) error {
+	if err := c.validate(args); err != nil {
+		return err
+	}
+	if err := config.Manager().TryLoadConfigFile(""); err != nil {
+		display.Fatal(display.ErrorOptions{}, "could not load config file: %s", err)
+	}
+
+	vaultSecrets, err := c.vaultOptions.Secrets()
+	if err != nil {
+		return fmt.Errorf("error loading vault secrets: %w", err)
+	}
+	vault.SetSecrets(vaultSecrets)
+
+	if c.inventory, err = inventory.Parse(c.inventorySources...); err != nil {
+		return fmt.Errorf("error parsing inventory: %w", err)
+	}
+	if err = c.inventory.Limit(c.limit); err != nil {
+		return fmt.Errorf("error applying limit: %w", err)
+	}
+	c.varsManager = varsPkg.MakeManager(c.inventory)
+	if c.playbookDir != "" {
+		c.varsManager.SetPlaybookDir(c.playbookDir)
+	}
+
+	var out []byte
+	switch {
+	case c.list:
+		out, err = c.listInventory()
+	case c.host != "":
+		out, err = c.hostInventory()
+	default:
+		group := "all"
+		if len(args) > 0 {
+			group = args[0]
+		}
+		out, err = c.graphInventory(group)
+	}
+	if err != nil {
+		return err
+	}
+	return c.write(cmd.OutOrStdout(), out)
+}
+
+func (c *inventoryCmd) write(stdout io.Writer, out []byte) error {
+	if c.output == "" {
+		_, err := stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(c.output, out, 0644); err != nil {
+		return fmt.Errorf("unable to write to file %s: %w", c.output, err)
+	}
+	display.Display(display.Options{Stderr: true}, "Successfully written to %s", c.output)
+	return nil
+}
+
+// listInventory returns the groups and the hosts, like ansible-inventory --list. Groups are given with their hosts
+// and children, host vars are given in _meta.hostvars. In YAML, groups are nested in their parents and host vars are
+// given with the hosts.
+func (c *inventoryCmd) listInventory() ([]byte, error) {
+	available, err := c.availableHosts()
+	if err != nil {
+		return nil, err
+	}
+	all := c.inventory.Groups["all"]
+	if c.Format == "yaml" {
+		results, err := c.yamlGroup(all, available, make(map[string]bool), make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+		return c.format(results)
+	}
+
+	results := make(map[string]interface{})
+	c.jsonGroup(all, available, results)
+	hostVars := make(map[string]interface{})
+	for _, host := range c.inventory.Hosts {
+		if !available[host.Name] {
+			continue
+		}
+		vars, err := c.hostVars(host)
+		if err != nil {
+			return nil, err
+		}
+		if len(vars) > 0 {
+			hostVars[host.Name] = vars
+		}
+	}
+	results["_meta"] = map[string]interface{}{"hostvars": hostVars}
+	return c.format(results)
+}
+
+func (c *inventoryCmd) jsonGroup(group *inventory.Group, available map[string]bool, results map[string]interface{}) {
+	result := make(map[string]interface{})
+	if hosts := groupHosts(group, available); len(hosts) > 0 {
+		result["hosts"] = hosts
+	}
+	var children []string
+	for _, child := range sortedChildren(group) {
+		children = append(children, child.Name)
+		if _, seen := results[child.Name]; !seen {
+			c.jsonGroup(child, available, results)
+		}
+	}
+	if len(children) > 0 {
+		result["children"] = children
+	}
+	if len(result) > 0 {
+		results[group.Name] = result
+	}
+}
+
+func (c *inventoryCmd) yamlGroup(group *inventory.Group, available, seenGroups, seenHosts map[string]bool) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	children := make(map[string]interface{})
+	for _, child := range sortedChildren(group) {
+		if seenGroups[child.Name] {
+			children[child.Name] = map[string]interface{}{}
+			continue
+		}
+		seenGroups[child.Name] = true
+		formatted, err := c.yamlGroup(child, available, seenGroups, seenHosts)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range formatted {
+			children[name] = value
+		}
+	}
+	if len(children) > 0 {
+		result["children"] = children
+	}
+
+	hosts := make(map[string]interface{})
+	for _, name := range groupHosts(group, available) {
+		// Vars are given once, with the first group the host is in.
+		vars := types.Vars{}
+		if !seenHosts[name] {
+			seenHosts[name] = true
+			var err error
+			if vars, err = c.hostVars(c.inventory.Hosts[name]); err != nil {
+				return nil, err
+			}
+		}
+		hosts[name] = vars
+	}
+	if len(hosts) > 0 {
+		result["hosts"] = hosts
+	}
+	if len(result) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return map[string]interface{}{group.Name: result}, nil
+}
+
+// hostInventory returns the vars of the host, with their sources if requested.
+func (c *inventoryCmd) hostInventory() ([]byte, error) {
+	available, err := c.availableHosts()
+	if err != nil {
+		return nil, err
+	}
+	host, ok := c.inventory.Hosts[c.host]
+	if !ok || !available[c.host] {
+		return nil, fmt.Errorf("you must pass a single valid host to --host parameter, %s is not in the inventory", c.host)
+	}
+	vars, sources, err := c.hostVarsWithSources(host)
+	if err != nil {
+		return nil, err
+	}
+	if !c.showSources {
+		return c.format(vars)
+	}
+	withSources := make(map[string]interface{}, len(vars))
+	for name, value := range vars {
+		withSources[name] = map[string]interface{}{"value": value, "source": sources[name]}
+	}
+	return c.format(withSources)
+}
+
+// graphInventory returns the tree of the groups and hosts starting from the group, like ansible-inventory --graph.
+func (c *inventoryCmd) graphInventory(name string) ([]byte, error) {
+	group, ok := c.inventory.Groups[name]
+	if !ok {
+		return nil, fmt.Errorf("pattern must be valid group name when using --graph, %s is not a group", name)
+	}
+	available, err := c.availableHosts()
+	if err != nil {
+		return nil, err
+	}
+	lines, err := c.graphGroup(group, available, 0)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+func (c *inventoryCmd) graphGroup(group *inventory.Group, available map[string]bool, depth int) ([]string, error) {
+	lines := []string{graphName("@"+group.Name+":", depth)}
+	for _, child := range sortedChildren(group) {
+		childLines, err := c.graphGroup(child, available, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, childLines...)
+	}
+	hosts := groupHosts(group, available)
+	sort.Strings(hosts)
+	for _, name := range hosts {
+		lines = append(lines, graphName(name, depth+1))
+		if c.showVars {
+			vars, err := c.hostVars(c.inventory.Hosts[name])
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, graphVars(vars, depth+2)...)
+		}
+	}
+	if c.showVars {
+		vars, err := c.varsManager.GetGroupVars(group)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, graphVars(vars, depth+1)...)
+	}
+	return lines, nil
+}
+
+func graphName(name string, depth int) string {
+	if depth == 0 {
+		return name
+	}
+	return strings.Repeat("  |", depth) + "--" + name
+}
+
+func graphVars(vars types.Vars, depth int) []string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, graphName(fmt.Sprintf("{%s = %s}", name, formatValue(vars[name])), depth))
+	}
+	return lines
+}
+
+func formatValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if out, err := json.Marshal(toJSONValue(value)); err == nil {
+		return string(out)
+	}
+	return fmt.Sprint(value)
+}
+
+// hostVars returns the vars of the host, as seen by tasks, without the ones set by gosible itself.
+func (c *inventoryCmd) hostVars(host *inventory.Host) (types.Vars, error) {
+	vars, _, err := c.hostVarsWithSources(host)
+	return vars, err
+}
+
+func (c *inventoryCmd) hostVarsWithSources(host *inventory.Host) (types.Vars, map[string]string, error) {
+	vars, sources, err := c.varsManager.GetVarsWithSources(nil, host, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting vars of host %s: %w", host.Name, err)
+	}
+	for name, source := range sources {
+		if source == varsPkg.MagicVarsSource {
+			delete(vars, name)
+			delete(sources, name)
+		}
+	}
+	return vars, sources, nil
+}
+
+// availableHosts returns the names of the hosts allowed by the limit.
+func (c *inventoryCmd) availableHosts() (map[string]bool, error) {
+	hosts, err := c.inventory.MatchHosts("all")
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		available[host.Name] = true
+	}
+	return available, nil
+}
+
+func groupHosts(group *inventory.Group, available map[string]bool) []string {
+	if group.Name == "all" {
+		return nil
+	}
+	var hosts []string
+	for _, host := range group.Hosts {
+		if available[host.Name] {
+			hosts = append(hosts, host.Name)
+		}
+	}
+	return hosts
+}
+
+func sortedChildren(group *inventory.Group) []*inventory.Group {
+	children := append([]*inventory.Group{}, group.Children...)
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	return children
+}
+
+func (c *inventoryCmd) format(v interface{}) ([]byte, error) {
+	if c.Format == "yaml" {
+		return yaml.Marshal(v)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(toJSONValue(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toJSONValue converts the maps with interface{} keys, produced by the YAML parser, to maps with string keys.
+func toJSONValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[fmt.Sprint(k)] = toJSONValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[k] = toJSONValue(item)
+		}
+		return result
+	case types.Vars:
+		return toJSONValue(map[string]interface{}(value))
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = toJSONValue(item)
+		}
+		return result
+	}
+	return v
+}
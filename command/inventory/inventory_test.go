@@ -0,0 +1,148 @@
+package inventory
+
+import (
+	"encoding/json"
+	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/inventory"
+	varsPkg "github.com/scylladb/gosible/vars"
+	"reflect"
+	"testing"
+)
+
+const sources = "../../inventory/tests/assets/sources"
+
+// newTestCmd returns the command with the test inventory. The vars manager is a singleton,
+// so all the tests share the inventory.
+func newTestCmd(t *testing.T, format string) *inventoryCmd {
+	inv, err := inventory.Parse(sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &inventoryCmd{
+		App:         &command.App{Format: format},
+		inventory:   inv,
+		varsManager: varsPkg.MakeManager(inv),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		cmd   inventoryCmd
+		args  []string
+		valid bool
+	}{
+		{cmd: inventoryCmd{list: true}, valid: true},
+		{cmd: inventoryCmd{host: "web1", showSources: true}, valid: true},
+		{cmd: inventoryCmd{graph: true, showVars: true}, args: []string{"web"}, valid: true},
+		{cmd: inventoryCmd{}},
+		{cmd: inventoryCmd{list: true, graph: true}},
+		{cmd: inventoryCmd{list: true}, args: []string{"web"}},
+		{cmd: inventoryCmd{list: true, showVars: true}},
+		{cmd: inventoryCmd{graph: true, showSources: true}},
+	}
+
+	for _, tc := range testCases {
+		tc.cmd.App = &command.App{Format: "json"}
+		if err := tc.cmd.validate(tc.args); (err == nil) != tc.valid {
+			t.Error("on", tc.cmd, tc.args, "expected valid", tc.valid, "got", err)
+		}
+	}
+}
+
+func TestListInventory(t *testing.T) {
+	c := newTestCmd(t, "json")
+	out, err := c.listInventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list map[string]map[string]interface{}
+	if err = json.Unmarshal(out, &list); err != nil {
+		t.Fatal("on", string(out), err)
+	}
+
+	expected := map[string]map[string]interface{}{
+		"all": {"children": []interface{}{"dc", "ungrouped", "web"}},
+		"dc":  {"children": []interface{}{"db", "web"}},
+		"db":  {"hosts": []interface{}{"db1", "db2"}},
+		"web": {"hosts": []interface{}{"web1", "web2", "web3"}},
+	}
+	for group, value := range expected {
+		if !reflect.DeepEqual(list[group], value) {
+			t.Error("on", group, "expected", value, "got", list[group])
+		}
+	}
+	hostVars := list["_meta"]["hostvars"].(map[string]interface{})
+	db1 := map[string]interface{}{"ansible_port": 2222.0, "primary": true, "region": "eu", "role": "database", "ignored3": nil}
+	if !reflect.DeepEqual(hostVars["db1"], db1) {
+		t.Error("on db1 expected", db1, "got", hostVars["db1"])
+	}
+}
+
+func TestHostInventory(t *testing.T) {
+	c := newTestCmd(t, "json")
+	c.host, c.showSources = "web2", true
+	out, err := c.hostInventory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vars map[string]map[string]interface{}
+	if err = json.Unmarshal(out, &vars); err != nil {
+		t.Fatal("on", string(out), err)
+	}
+	expected := map[string]interface{}{"value": 8080.0, "source": "host vars for 'web2'"}
+	if !reflect.DeepEqual(vars["http_port"], expected) {
+		t.Error("expected", expected, "got", vars["http_port"])
+	}
+
+	c.host = "unknown"
+	if _, err = c.hostInventory(); err == nil {
+		t.Error("expected error for unknown host")
+	}
+}
+
+func TestGraphInventory(t *testing.T) {
+	c := newTestCmd(t, "json")
+	c.showVars = true
+	out, err := c.graphInventory("dc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `@dc:
+  |--@db:
+  |  |--db1
+  |  |  |--{ansible_port = 2222}
+  |  |  |--{ignored3 = null}
+  |  |  |--{primary = true}
+  |  |  |--{region = eu}
+  |  |  |--{role = database}
+  |  |--db2
+  |  |  |--{ignored3 = null}
+  |  |  |--{region = eu}
+  |  |  |--{role = database}
+  |  |--{role = database}
+  |--@web:
+  |  |--web1
+  |  |  |--{http_port = 80}
+  |  |  |--{ignored3 = null}
+  |  |  |--{region = eu}
+  |  |  |--{role = frontend}
+  |  |--web2
+  |  |  |--{http_port = 8080}
+  |  |  |--{ignored3 = null}
+  |  |  |--{region = eu}
+  |  |  |--{role = frontend}
+  |  |--web3
+  |  |  |--{ignored3 = null}
+  |  |  |--{region = eu}
+  |  |  |--{role = frontend}
+  |  |--{role = frontend}
+  |--{region = eu}
+`
+	if string(out) != expected {
+		t.Error("expected", expected, "got", string(out))
+	}
+
+	if _, err = c.graphInventory("unknown"); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
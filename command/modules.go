@@ -0,0 +1,41 @@
+package command
+
+import (
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/modules"
+	defaultModules "github.com/scylladb/gosible/modules/default"
+	"github.com/scylladb/gosible/template"
+	"github.com/scylladb/gosible/utils/display"
+	pathUtils "github.com/scylladb/gosible/utils/path"
+	"github.com/scylladb/gosible/utils/types"
+	"strings"
+)
+
+// NewModuleRegistry returns the registry of the modules run by the commands: the native and Python modules
+// and the modules of the installed Ansible collections.
+func NewModuleRegistry() *modules.ModuleRegistry {
+	mods := modules.NewRegistry()
+	defaultModules.Register(mods)
+	defaultModules.RegisterPython(mods)
+	if err := defaultModules.RegisterCollections(mods, collectionsPaths()); err != nil {
+		display.Warning(display.WarnOptions{}, "error loading collections: %s", err)
+	}
+	return mods
+}
+
+// collectionsPaths returns the paths of the installed Ansible collections, see COLLECTIONS_PATHS.
+func collectionsPaths() []string {
+	settings := config.Manager().Settings
+	templated, err := template.TemplateToString(settings.COLLECTIONS_PATHS, types.Vars{"ANSIBLE_HOME": settings.ANSIBLE_HOME}, nil)
+	if err != nil {
+		display.Warning(display.WarnOptions{}, "invalid COLLECTIONS_PATHS %q: %s", settings.COLLECTIONS_PATHS, err)
+		return nil
+	}
+	var paths []string
+	for _, p := range strings.Split(templated.(string), ":") {
+		if p != "" {
+			paths = append(paths, pathUtils.UnfrackPath(p, pathUtils.UnfrackOptions{}))
+		}
+	}
+	return paths
+}
@@ -0,0 +1,502 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/command"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/parsing/vault"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/secrets"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func NewCommand(app *command.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vault",
+		Short: "Encrypt and decrypt files and values with Ansible Vault",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(NewCreateCommand(app))
+	cmd.AddCommand(NewEncryptCommand(app))
+	cmd.AddCommand(NewDecryptCommand(app))
+	cmd.AddCommand(NewViewCommand(app))
+	cmd.AddCommand(NewRekeyCommand(app))
+	cmd.AddCommand(NewEncryptStringCommand(app))
+	return cmd
+}
+
+type vaultCmd struct {
+	*command.App
+
+	vaultOptions     command.VaultOptions
+	encryptVaultID   string // --encrypt-vault-id
+	secrets          []vault.Secret
+	encryptionSecret *vault.Secret
+}
+
+func (c *vaultCmd) register(cmd *cobra.Command, encrypts bool) {
+	c.vaultOptions.Register(cmd)
+	if encrypts {
+		defaultID := config.Manager().Settings.DEFAULT_VAULT_ENCRYPT_IDENTITY
+		cmd.Flags().StringVar(&c.encryptVaultID, "encrypt-vault-id", defaultID, "the vault ID used to encrypt, required if more than one vault ID is provided")
+	}
+}
+
+// loadSecrets loads the vault secrets, asking for a password if none were given.
+func (c *vaultCmd) loadSecrets(encrypts bool) (err error) {
+	if err = config.Manager().TryLoadConfigFile(""); err != nil {
+		display.Fatal(display.ErrorOptions{}, "could not load config file: %s", err)
+	}
+	if c.secrets, err = c.vaultOptions.Secrets(); err != nil {
+		return err
+	}
+	if len(c.secrets) == 0 {
+		var password []byte
+		if encrypts {
+			password, err = promptNewPassword()
+		} else {
+			password, err = promptPassword("Vault password: ")
+		}
+		if err != nil {
+			return err
+		}
+		c.secrets = []vault.Secret{{ID: vault.DefaultID, Password: password}}
+	}
+	if encrypts {
+		c.encryptionSecret, err = encryptionSecret(c.secrets, c.encryptVaultID)
+	}
+	return err
+}
+
+// encryptionSecret selects the secret used to encrypt. It must be explicit if more than one secret is given.
+func encryptionSecret(secrets []vault.Secret, id string) (*vault.Secret, error) {
+	if id == "" {
+		if len(secrets) > 1 {
+			return nil, errors.New("the vault ID used to encrypt must be specified with --encrypt-vault-id when more than one vault ID is provided")
+		}
+		return &secrets[0], nil
+	}
+	for i := range secrets {
+		if secrets[i].ID == id {
+			return &secrets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("the vault ID %s used to encrypt was not found among the provided vault IDs", id)
+}
+
+func promptPassword(prompt string) ([]byte, error) {
+	password, err := secrets.PromptPassword(prompt)
+	if err != nil {
+		return nil, err
+	}
+	if len(password) == 0 {
+		return nil, errors.New("the vault password can't be empty")
+	}
+	return password, nil
+}
+
+func promptNewPassword() ([]byte, error) {
+	password, err := promptPassword("New Vault password: ")
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := secrets.PromptPassword("Confirm New Vault password: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(password, confirm) {
+		return nil, errors.New("passwords do not match")
+	}
+	return password, nil
+}
+
+// readInput reads the file, or stdin if the path is "-".
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeOutput writes the file, or stdout if the path is "-". Files are replaced atomically and keep their mode,
+// new files are readable only by the owner.
+func writeOutput(path string, data []byte) error {
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// filesOrStdin returns the paths given as arguments, or "-" if there are none.
+func filesOrStdin(args []string) []string {
+	if len(args) == 0 {
+		return []string{"-"}
+	}
+	return args
+}
+
+func NewCreateCommand(app *command.App) *cobra.Command {
+	c := &vaultCmd{App: app}
+	cmd := &cobra.Command{
+		Use:     "create FILE",
+		Short:   "Create a new vault encrypted file with $EDITOR",
+		Example: "gosible vault create secrets.yml",
+		Args:    cobra.ExactArgs(1),
+		RunE:    c.runCreate,
+	}
+	c.register(cmd, true)
+	return cmd
+}
+
+func (c *vaultCmd) runCreate(_ *cobra.Command, args []string) error {
+	path := args[0]
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := c.loadSecrets(true); err != nil {
+		return err
+	}
+	plaintext, err := editPlaintext(nil)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := vault.Encrypt(plaintext, *c.encryptionSecret)
+	if err != nil {
+		return err
+	}
+	return writeOutput(path, ciphertext)
+}
+
+// editPlaintext opens the contents in $EDITOR and returns them after the editor exits.
+func editPlaintext(contents []byte) ([]byte, error) {
+	tmp, err := os.CreateTemp("", "gosible-vault-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(contents); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], tmp.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return os.ReadFile(tmp.Name())
+}
+
+type cryptCmd struct {
+	vaultCmd
+	output string // --output
+}
+
+func (c *cryptCmd) register(cmd *cobra.Command, encrypts bool) {
+	c.vaultCmd.register(cmd, encrypts)
+	cmd.Flags().StringVar(&c.output, "output", "", "output file name, '-' for stdout")
+}
+
+func NewEncryptCommand(app *command.App) *cobra.Command {
+	c := &cryptCmd{vaultCmd: vaultCmd{App: app}}
+	cmd := &cobra.Command{
+		Use:     "encrypt [FILE...]",
+		Short:   "Encrypt files in place, or stdin",
+		Example: "gosible vault encrypt --vault-id prod@prompt group_vars/all.yml",
+		RunE:    c.runEncrypt,
+	}
+	c.register(cmd, true)
+	return cmd
+}
+
+func (c *cryptCmd) runEncrypt(_ *cobra.Command, args []string) error {
+	if err := c.loadSecrets(true); err != nil {
+		return err
+	}
+	return c.transformFiles(args, func(data []byte) ([]byte, error) {
+		if vault.IsEncrypted(data) {
+			return nil, errors.New("input is already encrypted")
+		}
+		return vault.Encrypt(data, *c.encryptionSecret)
+	}, "Encryption successful")
+}
+
+func NewDecryptCommand(app *command.App) *cobra.Command {
+	c := &cryptCmd{vaultCmd: vaultCmd{App: app}}
+	cmd := &cobra.Command{
+		Use:     "decrypt [FILE...]",
+		Short:   "Decrypt vault encrypted files in place, or stdin",
+		Example: "gosible vault decrypt --vault-password-file ~/.vault_pass group_vars/all.yml",
+		RunE:    c.runDecrypt,
+	}
+	c.register(cmd, false)
+	return cmd
+}
+
+func (c *cryptCmd) runDecrypt(_ *cobra.Command, args []string) error {
+	if err := c.loadSecrets(false); err != nil {
+		return err
+	}
+	return c.transformFiles(args, func(data []byte) ([]byte, error) {
+		plaintext, _, err := vault.Decrypt(data, c.secrets)
+		return plaintext, err
+	}, "Decryption successful")
+}
+
+// transformFiles replaces each file with the result of fn, or writes it to --output.
+func (c *cryptCmd) transformFiles(args []string, fn func([]byte) ([]byte, error), success string) error {
+	paths := filesOrStdin(args)
+	if c.output != "" && len(paths) > 1 {
+		return errors.New("--output can be used only with a single input file")
+	}
+	for _, path := range paths {
+		data, err := readInput(path)
+		if err != nil {
+			return err
+		}
+		result, err := fn(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		output := path
+		if c.output != "" {
+			output = c.output
+		}
+		if err = writeOutput(output, result); err != nil {
+			return err
+		}
+	}
+	if c.output != "-" && paths[0] != "-" {
+		display.Display(display.Options{Stderr: true}, "%s", success)
+	}
+	return nil
+}
+
+func NewViewCommand(app *command.App) *cobra.Command {
+	c := &vaultCmd{App: app}
+	cmd := &cobra.Command{
+		Use:     "view FILE...",
+		Short:   "View the contents of vault encrypted files",
+		Example: "gosible vault view group_vars/all.yml",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    c.runView,
+	}
+	c.register(cmd, false)
+	return cmd
+}
+
+func (c *vaultCmd) runView(_ *cobra.Command, args []string) error {
+	if err := c.loadSecrets(false); err != nil {
+		return err
+	}
+	for _, path := range args {
+		data, err := readInput(path)
+		if err != nil {
+			return err
+		}
+		plaintext, _, err := vault.Decrypt(data, c.secrets)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if _, err = os.Stdout.Write(plaintext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rekeyCmd struct {
+	vaultCmd
+	newVaultID           string // --new-vault-id
+	newVaultPasswordFile string // --new-vault-password-file
+}
+
+func NewRekeyCommand(app *command.App) *cobra.Command {
+	c := &rekeyCmd{vaultCmd: vaultCmd{App: app}}
+	cmd := &cobra.Command{
+		Use:     "rekey FILE...",
+		Short:   "Re-encrypt vault encrypted files with a new password",
+		Example: "gosible vault rekey --vault-id old@prompt --new-vault-id new@prompt group_vars/all.yml",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    c.run,
+	}
+	c.register(cmd, false)
+	cmd.Flags().StringVar(&c.newVaultID, "new-vault-id", "", "the new vault identity to use for rekey, as [label@]source")
+	cmd.Flags().StringVar(&c.newVaultPasswordFile, "new-vault-password-file", "", "new vault password file for rekey")
+	return cmd
+}
+
+func (c *rekeyCmd) run(_ *cobra.Command, args []string) error {
+	if c.newVaultID != "" && c.newVaultPasswordFile != "" {
+		return errors.New("--new-vault-id and --new-vault-password-file shouldn't be set at the same time")
+	}
+	if err := c.loadSecrets(false); err != nil {
+		return err
+	}
+	newSecret, err := c.newSecret()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range args {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		plaintext, _, err := vault.Decrypt(data, c.secrets)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		ciphertext, err := vault.Encrypt(plaintext, newSecret)
+		if err != nil {
+			return err
+		}
+		if err = writeOutput(path, ciphertext); err != nil {
+			return err
+		}
+	}
+	display.Display(display.Options{Stderr: true}, "Rekey successful")
+	return nil
+}
+
+func (c *rekeyCmd) newSecret() (vault.Secret, error) {
+	newID := c.newVaultID
+	if newID == "" && c.newVaultPasswordFile != "" {
+		newID = vault.DefaultID + "@" + c.newVaultPasswordFile
+	}
+	if newID == "" {
+		password, err := promptNewPassword()
+		return vault.Secret{ID: vault.DefaultID, Password: password}, err
+	}
+	label, source := command.ParseVaultID(newID, vault.DefaultID)
+	if source == command.VaultPromptSource {
+		password, err := promptNewPassword()
+		return vault.Secret{ID: label, Password: password}, err
+	}
+	return command.LoadVaultSecret(label, source)
+}
+
+type encryptStringCmd struct {
+	vaultCmd
+	prompt    bool     // -p, --prompt
+	names     []string // -n, --name
+	stdinName string   // --stdin-name
+}
+
+func NewEncryptStringCommand(app *command.App) *cobra.Command {
+	c := &encryptStringCmd{vaultCmd: vaultCmd{App: app}}
+	cmd := &cobra.Command{
+		Use:     "encrypt_string [STRING...]",
+		Short:   "Encrypt strings as `!vault` tagged YAML values",
+		Example: "gosible vault encrypt_string --vault-id prod@prompt -n db_password 's3cr3t'",
+		RunE:    c.run,
+	}
+	c.register(cmd, true)
+	cmd.Flags().BoolVarP(&c.prompt, "prompt", "p", false, "prompt for the string to encrypt")
+	cmd.Flags().StringArrayVarP(&c.names, "name", "n", nil, "specify the variable name, one for each string")
+	cmd.Flags().StringVar(&c.stdinName, "stdin-name", "", "specify the variable name for stdin")
+	return cmd
+}
+
+func (c *encryptStringCmd) run(_ *cobra.Command, args []string) error {
+	if c.prompt && len(args) > 0 {
+		return errors.New("the strings to encrypt can't be given as arguments together with --prompt")
+	}
+	if err := c.loadSecrets(true); err != nil {
+		return err
+	}
+
+	var values, names []string
+	switch {
+	case c.prompt:
+		value, err := secrets.PromptPassword("String to encrypt (hidden): ")
+		if err != nil {
+			return err
+		}
+		values = []string{string(value)}
+		names = c.names
+	case len(args) == 0:
+		if c.stdinName == "" && len(c.names) > 0 {
+			c.stdinName = c.names[0]
+		}
+		value, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		values = []string{string(value)}
+		names = []string{c.stdinName}
+	default:
+		values = args
+		names = c.names
+	}
+
+	for i, value := range values {
+		if value == "" {
+			return errors.New("the plaintext provided can't be empty")
+		}
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		ciphertext, err := vault.Encrypt([]byte(value), *c.encryptionSecret)
+		if err != nil {
+			return err
+		}
+		fmt.Print(FormatEncryptedString(name, ciphertext))
+	}
+	return nil
+}
+
+// encryptedStringIndent is the indentation of the ciphertext lines of the `!vault` block scalar.
+const encryptedStringIndent = "          "
+
+// FormatEncryptedString formats the ciphertext as a `!vault` tagged YAML block scalar, named if name isn't empty.
+func FormatEncryptedString(name string, ciphertext []byte) string {
+	var b strings.Builder
+	if name != "" {
+		b.WriteString(name)
+		b.WriteString(": ")
+	}
+	b.WriteString("!vault |\n")
+	for _, line := range strings.Split(strings.TrimRight(string(ciphertext), "\n"), "\n") {
+		b.WriteString(encryptedStringIndent)
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
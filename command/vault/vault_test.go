@@ -0,0 +1,76 @@
+package vault
+
+import (
+	"github.com/scylladb/gosible/parsing/vault"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatEncryptedString(t *testing.T) {
+	secret := vault.Secret{ID: vault.DefaultID, Password: []byte("gosible")}
+	ciphertext, err := vault.Encrypt([]byte("s3cr3t"), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatted := FormatEncryptedString("db_password", ciphertext)
+
+	var parsed map[string]string
+	if err = yaml.Unmarshal([]byte(formatted), &parsed); err != nil {
+		t.Fatal("on", formatted, err)
+	}
+	plaintext, _, err := vault.Decrypt([]byte(parsed["db_password"]), []vault.Secret{secret})
+	if err != nil {
+		t.Fatal("on", formatted, err)
+	}
+	if string(plaintext) != "s3cr3t" {
+		t.Error("on", formatted, "expected", "s3cr3t", "got", string(plaintext))
+	}
+}
+
+func TestEncryptionSecret(t *testing.T) {
+	one := []vault.Secret{{ID: "dev", Password: []byte("a")}}
+	two := append(one, vault.Secret{ID: "prod", Password: []byte("b")})
+	testCases := []struct {
+		secrets  []vault.Secret
+		id       string
+		expected string
+	}{
+		{secrets: one, expected: "dev"},
+		{secrets: two, id: "prod", expected: "prod"},
+		{secrets: two, expected: ""},
+		{secrets: two, id: "test", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		secret, err := encryptionSecret(testCase.secrets, testCase.id)
+		if testCase.expected == "" {
+			if err == nil {
+				t.Error("on", testCase.id, "expected error")
+			}
+		} else if err != nil || secret.ID != testCase.expected {
+			t.Error("on", testCase.id, "expected", testCase.expected, "got", secret, err)
+		}
+	}
+}
+
+func TestWriteOutputKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vars.yml")
+	if err := os.WriteFile(path, []byte("old"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeOutput(path, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Error("on", path, "expected mode", os.FileMode(0640), "got", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Error("on", path, "expected", "new", "got", string(data))
+	}
+}
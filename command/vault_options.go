@@ -0,0 +1,110 @@
+package command
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/parsing/vault"
+	pathUtils "github.com/scylladb/gosible/utils/path"
+	"github.com/scylladb/gosible/utils/secrets"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// VaultPromptSource is the vault ID source asking for the password on the terminal.
+const VaultPromptSource = "prompt"
+
+// VaultOptions are the flags selecting the secrets used to decrypt vault data.
+type VaultOptions struct {
+	VaultIDs           []string // --vault-id
+	AskVaultPassword   bool     // -J, --ask-vault-password, --ask-vault-pass
+	VaultPasswordFiles []string // --vault-password-file, --vault-pass-file
+}
+
+func (o *VaultOptions) Register(cmd *cobra.Command) {
+	settings := config.Manager().Settings
+	var passwordFiles []string
+	if settings.DEFAULT_VAULT_PASSWORD_FILE != "" {
+		passwordFiles = []string{settings.DEFAULT_VAULT_PASSWORD_FILE}
+	}
+	cmd.Flags().StringArrayVar(&o.VaultIDs, "vault-id", nil, "the vault identity to use, as [label@]source where source is a password file or 'prompt'")
+	cmd.Flags().BoolVarP(&o.AskVaultPassword, "ask-vault-password", "J", settings.DEFAULT_ASK_VAULT_PASS, "ask for vault password")
+	cmd.Flags().BoolVar(&o.AskVaultPassword, "ask-vault-pass", settings.DEFAULT_ASK_VAULT_PASS, "ask for vault password")
+	cobra.CheckErr(cmd.Flags().MarkHidden("ask-vault-pass"))
+	cmd.Flags().StringArrayVar(&o.VaultPasswordFiles, "vault-password-file", passwordFiles, "vault password file")
+	cmd.Flags().StringArrayVar(&o.VaultPasswordFiles, "vault-pass-file", passwordFiles, "vault password file")
+	cobra.CheckErr(cmd.Flags().MarkHidden("vault-pass-file"))
+}
+
+// Secrets loads the vault secrets selected by the options and by DEFAULT_VAULT_IDENTITY_LIST.
+// Password files and --ask-vault-password give secrets with the DEFAULT_VAULT_IDENTITY vault ID.
+func (o *VaultOptions) Secrets() ([]vault.Secret, error) {
+	settings := config.Manager().Settings
+	defaultID := settings.DEFAULT_VAULT_IDENTITY
+	if defaultID == "" {
+		defaultID = vault.DefaultID
+	}
+
+	var ids []string
+	ids = append(ids, settings.DEFAULT_VAULT_IDENTITY_LIST...)
+	ids = append(ids, o.VaultIDs...)
+	for _, f := range o.VaultPasswordFiles {
+		ids = append(ids, defaultID+"@"+f)
+	}
+	if o.AskVaultPassword {
+		ids = append(ids, defaultID+"@"+VaultPromptSource)
+	}
+
+	var loaded []vault.Secret
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		secret, err := LoadVaultSecret(ParseVaultID(id, defaultID))
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, secret)
+	}
+	return loaded, nil
+}
+
+// ParseVaultID splits a vault identity given as [label@]source. Identities without a label get defaultID.
+func ParseVaultID(id, defaultID string) (label, source string) {
+	if i := strings.Index(id, "@"); i >= 0 {
+		return id[:i], id[i+1:]
+	}
+	return defaultID, id
+}
+
+// LoadVaultSecret reads the password of the vault ID from the source, a password file or VaultPromptSource.
+func LoadVaultSecret(label, source string) (vault.Secret, error) {
+	password, err := readVaultPassword(label, source)
+	return vault.Secret{ID: label, Password: password}, err
+}
+
+func readVaultPassword(label, source string) ([]byte, error) {
+	if source == VaultPromptSource || source == "prompt_ask_vault_pass" {
+		prompt := "Vault password: "
+		if label != vault.DefaultID {
+			prompt = fmt.Sprintf("Vault password (%s): ", label)
+		}
+		password, err := secrets.PromptPassword(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if len(password) == 0 {
+			return nil, fmt.Errorf("empty vault password for vault ID %s", label)
+		}
+		return password, nil
+	}
+	if source != "-" {
+		source = pathUtils.UnfrackPath(source, pathUtils.UnfrackOptions{})
+	}
+	password, err := secrets.ReadPasswordFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("vault ID %s: %w", label, err)
+	}
+	return password, nil
+}
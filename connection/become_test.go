@@ -0,0 +1,158 @@
+package connection
+
+import (
+	"bufio"
+	"github.com/scylladb/gosible/plugins/become"
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"io"
+	"strings"
+	"testing"
+)
+
+type testShell struct{}
+
+func (testShell) Echo() string              { return "echo" }
+func (testShell) CommandSep() string        { return ";" }
+func (testShell) Executable() string        { return "/bin/sh" }
+func (testShell) Exists(path string) string { return "test -e " + path }
+
+var _ shell.Shell = testShell{}
+
+// successMarker returns the marker printed by the command built by the plugin.
+func successMarker(t *testing.T, cmd string) string {
+	i := strings.Index(cmd, "BECOME-SUCCESS-")
+	if i < 0 {
+		t.Fatal("on", cmd, "expected a success marker")
+	}
+	return cmd[i : i+len("BECOME-SUCCESS-")+32]
+}
+
+// fakeBecome simulates the become command: it prints the prompt, reads the password and responds with the output
+// for the password.
+func fakeBecome(prompt string, respond func(password string) string) *types.ProcessPipes {
+	stdinR, stdinW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(stderrW, prompt)
+		password, _ := bufio.NewReader(stdinR).ReadString('\n')
+		_, _ = io.WriteString(stderrW, respond(strings.TrimSuffix(password, "\n")))
+		_ = stderrW.Close()
+	}()
+	return &types.ProcessPipes{Stdin: stdinW, Stderr: stderrR}
+}
+
+func TestWaitForBecome(t *testing.T) {
+	testCases := []struct {
+		name       string
+		password   string
+		errMessage string
+	}{
+		{name: "correct", password: "pass"},
+		{name: "incorrect", password: "wrong", errMessage: "incorrect sudo password"},
+	}
+
+	for _, testCase := range testCases {
+		args := &types.BecomeArgs{User: "become", Method: "sudo", Password: testCase.password}
+		plugin := become.NewSudo(args)
+		cmd, readLen, err := plugin.BuildBecomeCommand("id", testShell{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		prompt := cmd[strings.Index(cmd, "[sudo via ansible") : strings.Index(cmd, "password:")+len("password:")]
+		success := successMarker(t, cmd)
+		pipes := fakeBecome(prompt, func(password string) string {
+			if password == "pass" {
+				return success + "\n"
+			}
+			return "Sorry, try again.\n" + prompt
+		})
+
+		err = waitForBecome(pipes, plugin, args, readLen)
+		if testCase.errMessage == "" && err != nil {
+			t.Error("on", testCase.name, "unexpected error", err)
+		} else if testCase.errMessage != "" && (err == nil || err.Error() != testCase.errMessage) {
+			t.Error("on", testCase.name, "expected error", testCase.errMessage, "got", err)
+		}
+	}
+}
+
+func TestWaitForBecomeExited(t *testing.T) {
+	args := &types.BecomeArgs{User: "become", Method: "sudo", Password: "pass"}
+	plugin := become.NewSudo(args)
+	_, readLen, err := plugin.BuildBecomeCommand("id", testShell{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipes := &types.ProcessPipes{Stdin: io.Discard, Stderr: strings.NewReader("sudo: unknown user become\n")}
+	expected := "sudo exited before becoming become: sudo: unknown user become"
+	if err = waitForBecome(pipes, plugin, args, readLen); err == nil || err.Error() != expected {
+		t.Error("on", "exited", "expected error", expected, "got", err)
+	}
+}
+
+func TestWaitForBecomeMissingPassword(t *testing.T) {
+	args := &types.BecomeArgs{User: "become", Method: "pbrun"}
+	plugin := become.NewPbrun(args)
+	_, readLen, err := plugin.BuildBecomeCommand("id", testShell{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipes := &types.ProcessPipes{Stdin: io.Discard, Stderr: strings.NewReader("Password:")}
+	if err = waitForBecome(pipes, plugin, args, readLen); err == nil || err.Error() != "missing pbrun password" {
+		t.Error("on", "missing password", "expected error", "missing pbrun password", "got", err)
+	}
+}
+
+func TestWaitForTtyBecome(t *testing.T) {
+	testCases := []struct {
+		name       string
+		password   string
+		prompt     string
+		errMessage string
+	}{
+		{name: "password", password: "pass", prompt: "Password: "},
+		{name: "no prompt"},
+		{name: "missing password", prompt: "Password: ", errMessage: "missing machinectl password"},
+	}
+
+	for _, testCase := range testCases {
+		args := &types.BecomeArgs{User: "become", Method: "machinectl", Password: testCase.password}
+		plugin := become.NewMachinectl(args)
+		cmd, _, err := plugin.BuildBecomeCommand("id", testShell{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		success := successMarker(t, cmd)
+		stdinR, stdinW := io.Pipe()
+		stdoutR, stdoutW := io.Pipe()
+		go func(prompt string) {
+			if prompt != "" {
+				_, _ = io.WriteString(stdoutW, prompt)
+				if _, err := bufio.NewReader(stdinR).ReadString('\n'); err != nil {
+					return
+				}
+			}
+			// The terminal shows the marker colored, and the output of the command follows it.
+			_, _ = io.WriteString(stdoutW, "\x1b[1m"+success+"\x1b[0m\nuid=1000(become)")
+			_ = stdoutW.Close()
+		}(testCase.prompt)
+		pipes := &types.ProcessPipes{Stdin: stdinW, Stdout: stdoutR, Stderr: strings.NewReader("")}
+
+		err = waitForTtyBecome(pipes, plugin, args)
+		if testCase.errMessage != "" {
+			if err == nil || err.Error() != testCase.errMessage {
+				t.Error("on", testCase.name, "expected error", testCase.errMessage, "got", err)
+			}
+			_ = stdinR.Close()
+			continue
+		}
+		if err != nil {
+			t.Error("on", testCase.name, "unexpected error", err)
+			continue
+		}
+		if output, _ := io.ReadAll(pipes.Stdout); string(output) != "uid=1000(become)" {
+			t.Error("on", testCase.name, "expected the output of the command, got", string(output))
+		}
+	}
+}
@@ -29,6 +29,12 @@ type InteractiveCommandExecutor interface {
 	io.Closer
 }
 
+// HealthChecker interface represents connection able to verify that its underlying transport still works.
+type HealthChecker interface {
+	// CheckAlive returns an error if the connection is no longer usable.
+	CheckAlive() error
+}
+
 type FileSender interface {
 	// SendFile places file at given path with given mode at host.
 	SendFile(f io.Reader, path string, mode string) error
@@ -62,16 +68,40 @@ func DefaultExecCommandImpl(executor InteractiveCommandExecutor, cmd string, inD
 	return &stdoutBuf, &stderrBuf, nil
 }
 
+// rawTerminalModes make the terminal pass the data through unchanged, as the command speaks a binary protocol.
+var rawTerminalModes = ssh.TerminalModes{
+	ssh.ECHO:   0,
+	ssh.ICANON: 0,
+	ssh.ISIG:   0,
+	ssh.IEXTEN: 0,
+	ssh.IXON:   0,
+	ssh.ICRNL:  0,
+	ssh.INLCR:  0,
+	ssh.IGNCR:  0,
+	ssh.ISTRIP: 0,
+	ssh.OPOST:  0,
+	ssh.CS8:    1,
+}
+
 func StartBecome(session *ssh.Session, cmd string, becomeArgs *types.BecomeArgs, sh shell.Shell) (*types.ProcessPipes, error) {
-	pipes, err := types.NewProcessPipesFromSshSession(session)
-	if err != nil {
-		return nil, err
-	}
 	pluginConstructor, exists := repository.FindBecomePluginConstructor(becomeArgs.Method)
 	if !exists {
 		return nil, fmt.Errorf("become plugin `%s` not found", becomeArgs.Method)
 	}
 	plugin := pluginConstructor(becomeArgs)
+	if plugin.RequireTty() {
+		if err := session.RequestPty("xterm", 24, 80, rawTerminalModes); err != nil {
+			return nil, err
+		}
+		// The terminal merges stderr into stdout, which must hold only the output of the command.
+		if cmd != "" {
+			cmd += " 2>/dev/null"
+		}
+	}
+	pipes, err := types.NewProcessPipesFromSshSession(session)
+	if err != nil {
+		return nil, err
+	}
 	becomeCmd, saveOutputReadLen, err := plugin.BuildBecomeCommand(cmd, sh)
 	if err != nil {
 		return nil, err
@@ -80,30 +110,78 @@ func StartBecome(session *ssh.Session, cmd string, becomeArgs *types.BecomeArgs,
 	if err != nil {
 		return nil, err
 	}
-	if plugin.ExpectPrompt() {
-		var output []byte
-		buffer := make([]byte, saveOutputReadLen)
-		for !plugin.CheckSuccess(output) && !plugin.CheckPasswordPrompt(output) {
-			if _, err = pipes.Stderr.Read(buffer); err != nil {
-				return nil, err
-			}
-			output = append(output, buffer...)
+	if plugin.RequireTty() {
+		err = waitForTtyBecome(pipes, plugin, becomeArgs)
+	} else if plugin.ExpectPrompt() {
+		err = waitForBecome(pipes, plugin, becomeArgs, saveOutputReadLen)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pipes, nil
+}
+
+// waitForTtyBecome waits for the become command running in a terminal, which writes the prompt and the success marker
+// to stdout. The success marker is always awaited and its line consumed, so that stdout then holds just the output
+// of the command.
+func waitForTtyBecome(pipes *types.ProcessPipes, plugin repository.BecomePlugin, becomeArgs *types.BecomeArgs) error {
+	ttyPipes := &types.ProcessPipes{Stdin: pipes.Stdin, Stderr: pipes.Stdout}
+	// Reading a byte at a time doesn't consume the output following the marker.
+	if err := waitForBecome(ttyPipes, plugin, becomeArgs, 1); err != nil {
+		return err
+	}
+	b := make([]byte, 1)
+	for b[0] != '\n' {
+		if _, err := io.ReadFull(pipes.Stdout, b); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+// waitForBecome answers the password prompt of the become command and waits until it succeeds.
+func waitForBecome(pipes *types.ProcessPipes, plugin repository.BecomePlugin, becomeArgs *types.BecomeArgs, readLen int) error {
+	promptOrSuccess := func(output []byte) bool { return plugin.CheckSuccess(output) || plugin.CheckPasswordPrompt(output) }
+	output, err := readBecomeOutput(pipes.Stderr, plugin, becomeArgs, readLen, promptOrSuccess)
+	if err != nil || plugin.CheckSuccess(output) {
+		return err
+	}
+
+	if becomeArgs.Password == "" {
+		return fmt.Errorf("missing %s password", becomeArgs.Method)
+	}
+	if _, err = pipes.Stdin.Write([]byte(becomeArgs.Password + "\n")); err != nil {
+		return err
+	}
+	_, err = readBecomeOutput(pipes.Stderr, plugin, becomeArgs, readLen, plugin.CheckSuccess)
+	return err
+}
 
-		if !plugin.CheckSuccess(output) {
-			if _, err = pipes.Stdin.Write([]byte(becomeArgs.Password + "\n")); err != nil {
-				return nil, err
-			}
-			output = nil
-			for !plugin.CheckSuccess(output) {
-				if _, err = pipes.Stderr.Read(buffer); err != nil {
-					return nil, err
-				}
-				output = append(output, buffer...)
-			}
+// readBecomeOutput reads the output of the become command until done reports true. Fails as soon as the output
+// shows that the password is incorrect or missing.
+func readBecomeOutput(r io.Reader, plugin repository.BecomePlugin, becomeArgs *types.BecomeArgs, readLen int, done func([]byte) bool) ([]byte, error) {
+	if readLen < 1 {
+		readLen = 1
+	}
+	var output []byte
+	buffer := make([]byte, readLen)
+	for !done(output) {
+		if plugin.CheckIncorrectPassword(output) {
+			return nil, fmt.Errorf("incorrect %s password", becomeArgs.Method)
+		}
+		if plugin.CheckMissingPassword(output) {
+			return nil, fmt.Errorf("missing %s password", becomeArgs.Method)
+		}
+		n, err := r.Read(buffer)
+		output = append(output, buffer[:n]...)
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s exited before becoming %s: %s", becomeArgs.Method, becomeArgs.User, bytes.TrimSpace(output))
+		}
+		if err != nil {
+			return nil, err
 		}
 	}
-	return pipes, nil
+	return output, nil
 }
 
 type SendExecuteConnection interface {
@@ -0,0 +1,193 @@
+package sshConnection
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/scylladb/gosible/utils/display"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"io"
+	"net"
+	"sync"
+)
+
+var agents = struct {
+	sync.Mutex
+	clients map[string]*agentClient
+}{clients: make(map[string]*agentClient)}
+
+// getAgent returns a client of the ssh-agent listening on the given socket. A single client is shared by all connections.
+func getAgent(socket string) (agent.ExtendedAgent, error) {
+	agents.Lock()
+	client, ok := agents.clients[socket]
+	if !ok {
+		client = &agentClient{socket: socket}
+		agents.clients[socket] = client
+	}
+	agents.Unlock()
+
+	if _, err := client.current(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// agentClient is a client of the ssh-agent which dials the agent again when a call fails, so that a long run
+// survives a restart of the agent.
+type agentClient struct {
+	socket string
+	mu     sync.Mutex
+	conn   net.Conn
+	client agent.ExtendedAgent
+}
+
+var _ agent.ExtendedAgent = &agentClient{}
+
+// current returns the client of the connection to the agent, dialing it if there is none.
+func (a *agentClient) current() (agent.ExtendedAgent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.client != nil {
+		return a.client, nil
+	}
+	conn, err := net.Dial("unix", a.socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent at %s: %w", a.socket, err)
+	}
+	a.conn, a.client = conn, agent.NewClient(conn)
+	return a.client, nil
+}
+
+// drop closes the connection of the client, unless another call has already replaced it.
+func (a *agentClient) drop(client agent.ExtendedAgent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.client == client {
+		_ = a.conn.Close()
+		a.conn, a.client = nil, nil
+	}
+}
+
+// call runs fn with the client of the agent. If it fails, the agent is dialed again and fn is retried once.
+func (a *agentClient) call(fn func(client agent.ExtendedAgent) error) error {
+	client, err := a.current()
+	if err != nil {
+		return err
+	}
+	if err = fn(client); err == nil {
+		return nil
+	}
+	display.Debug(nil, "ssh-agent call failed, reconnecting: %s", err)
+	a.drop(client)
+	if client, err = a.current(); err != nil {
+		return err
+	}
+	return fn(client)
+}
+
+func (a *agentClient) List() (keys []*agent.Key, err error) {
+	err = a.call(func(client agent.ExtendedAgent) (err error) {
+		keys, err = client.List()
+		return
+	})
+	return
+}
+
+func (a *agentClient) Sign(key ssh.PublicKey, data []byte) (sig *ssh.Signature, err error) {
+	err = a.call(func(client agent.ExtendedAgent) (err error) {
+		sig, err = client.Sign(key, data)
+		return
+	})
+	return
+}
+
+func (a *agentClient) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (sig *ssh.Signature, err error) {
+	err = a.call(func(client agent.ExtendedAgent) (err error) {
+		sig, err = client.SignWithFlags(key, data, flags)
+		return
+	})
+	return
+}
+
+func (a *agentClient) Add(key agent.AddedKey) error {
+	return a.call(func(client agent.ExtendedAgent) error { return client.Add(key) })
+}
+
+func (a *agentClient) Remove(key ssh.PublicKey) error {
+	return a.call(func(client agent.ExtendedAgent) error { return client.Remove(key) })
+}
+
+func (a *agentClient) RemoveAll() error {
+	return a.call(func(client agent.ExtendedAgent) error { return client.RemoveAll() })
+}
+
+func (a *agentClient) Lock(passphrase []byte) error {
+	return a.call(func(client agent.ExtendedAgent) error { return client.Lock(passphrase) })
+}
+
+func (a *agentClient) Unlock(passphrase []byte) error {
+	return a.call(func(client agent.ExtendedAgent) error { return client.Unlock(passphrase) })
+}
+
+func (a *agentClient) Extension(extensionType string, contents []byte) (res []byte, err error) {
+	err = a.call(func(client agent.ExtendedAgent) (err error) {
+		res, err = client.Extension(extensionType, contents)
+		return
+	})
+	return
+}
+
+// Signers returns signers which sign through the current connection to the agent, instead of the one they were
+// listed with.
+func (a *agentClient) Signers() ([]ssh.Signer, error) {
+	keys, err := a.List()
+	if err != nil {
+		return nil, err
+	}
+	signers := make([]ssh.Signer, len(keys))
+	for i, key := range keys {
+		signers[i] = &agentSigner{agent: a, pub: key}
+	}
+	return signers, nil
+}
+
+type agentSigner struct {
+	agent *agentClient
+	pub   ssh.PublicKey
+}
+
+var _ ssh.AlgorithmSigner = &agentSigner{}
+
+func (s *agentSigner) PublicKey() ssh.PublicKey {
+	return s.pub
+}
+
+func (s *agentSigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	return s.SignWithAlgorithm(rand, data, "")
+}
+
+// SignWithAlgorithm signs with the signer of the agent's client, which maps the algorithm to the signature flags.
+func (s *agentSigner) SignWithAlgorithm(rand io.Reader, data []byte, algorithm string) (sig *ssh.Signature, err error) {
+	err = s.agent.call(func(client agent.ExtendedAgent) error {
+		signers, err := client.Signers()
+		if err != nil {
+			return err
+		}
+		for _, signer := range signers {
+			if !bytes.Equal(signer.PublicKey().Marshal(), s.pub.Marshal()) {
+				continue
+			}
+			algorithmSigner, ok := signer.(ssh.AlgorithmSigner)
+			if !ok {
+				sig, err = signer.Sign(rand, data)
+			} else {
+				sig, err = algorithmSigner.SignWithAlgorithm(rand, data, algorithm)
+			}
+			return err
+		}
+		return fmt.Errorf("key %s is no longer held by ssh-agent", ssh.FingerprintSHA256(s.pub))
+	})
+	return
+}
@@ -0,0 +1,97 @@
+package sshConnection
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// agentServer serves the keyring on a unix socket, its connections can be dropped to simulate a restart of the agent.
+type agentServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	conns    []net.Conn
+}
+
+func newAgentServer(t *testing.T, socket string, keyring agent.Agent) *agentServer {
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &agentServer{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+			go func() { _ = agent.ServeAgent(keyring, conn) }()
+		}
+	}()
+	return s
+}
+
+func (s *agentServer) close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func TestAgentClientReconnects(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring := agent.NewKeyring()
+	if err = keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	server := newAgentServer(t, socket, keyring)
+
+	client := &agentClient{socket: socket}
+	signers, err := client.Signers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 {
+		t.Fatal("expected one signer, got", len(signers))
+	}
+
+	// The agent restarts, the cached connection is dead.
+	server.close()
+	server = newAgentServer(t, socket, keyring)
+
+	if _, err = client.List(); err != nil {
+		t.Error("expected List to reconnect, got", err)
+	}
+	server.close()
+	server = newAgentServer(t, socket, keyring)
+	data := []byte("data")
+	sig, err := signers[0].Sign(rand.Reader, data)
+	if err != nil {
+		t.Fatal("expected the listed signer to sign through a new connection, got", err)
+	}
+	if err = signers[0].PublicKey().Verify(data, sig); err != nil {
+		t.Error(err)
+	}
+	if _, ok := signers[0].(ssh.AlgorithmSigner); !ok {
+		t.Error("expected the signer to support signature algorithms")
+	}
+
+	server.close()
+	if _, err = client.List(); err == nil {
+		t.Error("expected error when the agent is gone")
+	}
+}
@@ -0,0 +1,129 @@
+package sshConnection
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/utils/display"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"os"
+	"strings"
+)
+
+// certificateSuffix is appended to the identity file path by ssh(1) to find a matching certificate.
+const certificateSuffix = "-cert.pub"
+
+// authOptions holds the authentication settings which are not a part of sshfile.Config.
+type authOptions struct {
+	// identityAgent is the path to the ssh-agent socket. Empty when agent authentication is disabled.
+	identityAgent   string
+	certificateFile string
+	forwardAgent    bool
+}
+
+func newAuthOptions(args *sshArgs) *authOptions {
+	opts := &authOptions{
+		identityAgent:   os.Getenv("SSH_AUTH_SOCK"),
+		certificateFile: args.certificateFile,
+		forwardAgent:    args.forwardAgent,
+	}
+	switch {
+	case strings.EqualFold(args.identityAgent, "none"):
+		opts.identityAgent = ""
+	case strings.EqualFold(args.identityAgent, "SSH_AUTH_SOCK"):
+	case args.identityAgent != "":
+		opts.identityAgent = args.identityAgent
+	}
+	return opts
+}
+
+// authMethod returns the public key authentication method trying, in order, the certificate, the identity file
+// and the keys held by the ssh-agent.
+func (a *authOptions) authMethod(identityFile string) (ssh.AuthMethod, error) {
+	signers, err := a.fileSigners(identityFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var agentClient agent.ExtendedAgent
+	if a.identityAgent != "" {
+		if agentClient, err = getAgent(a.identityAgent); err != nil {
+			display.Debug(nil, "ssh-agent authentication disabled: %s", err)
+		}
+	}
+
+	return ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+		if agentClient == nil {
+			return signers, nil
+		}
+		agentSigners, err := agentClient.Signers()
+		if err != nil {
+			display.Debug(nil, "Failed to get keys from ssh-agent: %s", err)
+			return signers, nil
+		}
+		return append(signers[:len(signers):len(signers)], agentSigners...), nil
+	}), nil
+}
+
+func (a *authOptions) fileSigners(identityFile string) ([]ssh.Signer, error) {
+	if identityFile == "" {
+		return nil, nil
+	}
+	keyData, err := os.ReadFile(identityFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q file: %w", identityFile, err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyData)
+	var passphraseErr *ssh.PassphraseMissingError
+	if errors.As(err, &passphraseErr) && a.identityAgent != "" {
+		display.Debug(nil, "Identity file %s is encrypted, relying on ssh-agent", identityFile)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q file: %w", identityFile, err)
+	}
+
+	certFile := a.certificateFile
+	if certFile == "" {
+		certFile = identityFile + certificateSuffix
+		if _, err = os.Stat(certFile); err != nil {
+			return []ssh.Signer{signer}, nil
+		}
+	}
+	certSigner, err := newCertSigner(certFile, signer)
+	if err != nil {
+		return nil, err
+	}
+	return []ssh.Signer{certSigner, signer}, nil
+}
+
+func newCertSigner(certFile string, signer ssh.Signer) (ssh.Signer, error) {
+	certData, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q certificate: %w", certFile, err)
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(certData)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q certificate: %w", certFile, err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("%q is not an ssh certificate", certFile)
+	}
+	return ssh.NewCertSigner(cert, signer)
+}
+
+// setupAgentForwarding makes the connection serve forwarded agent requests using the local ssh-agent.
+func (a *authOptions) setupAgentForwarding(client *ssh.Client) error {
+	if !a.forwardAgent {
+		return nil
+	}
+	if a.identityAgent == "" {
+		return errors.New("ForwardAgent requested, but no ssh-agent is available (SSH_AUTH_SOCK is not set)")
+	}
+	agentClient, err := getAgent(a.identityAgent)
+	if err != nil {
+		return err
+	}
+	return agent.ForwardToAgent(client, agentClient)
+}
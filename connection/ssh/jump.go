@@ -0,0 +1,203 @@
+package sshConnection
+
+import (
+	"context"
+	"fmt"
+	"github.com/rjeczalik/gsh"
+	"github.com/rjeczalik/gsh/sshfile"
+	"github.com/rjeczalik/gsh/sshutil"
+	"github.com/scylladb/gosible/connection"
+	"github.com/scylladb/gosible/utils/display"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// jumpClient is a connection to a bastion host. It is shared by all connections whose ProxyJump chain
+// starts with the same hops.
+type jumpClient struct {
+	key    string
+	client *ssh.Client
+	parent *jumpClient
+	refs   int
+	// dialed is closed once the client is connected or err is set, so that the hop is dialed only once
+	// without holding the pool lock.
+	dialed chan struct{}
+	err    error
+}
+
+// jumpPool keeps the connections to bastion hosts, so that a single bastion connection is used for all hosts behind it.
+type jumpPool struct {
+	mu      sync.Mutex
+	clients map[string]*jumpClient
+}
+
+var bastions = &jumpPool{clients: make(map[string]*jumpClient)}
+
+// acquire returns a connection to the last hop of the chain, dialing the hops which are not connected yet.
+// Every returned client must be released with release.
+func (p *jumpPool) acquire(ctx context.Context, hops []jumpHost, opts *sshfile.Config, auth *authOptions) (*jumpClient, error) {
+	var parent *jumpClient
+	keys := make([]string, 0, len(hops))
+	for _, hop := range hops {
+		hopOpts := hopConfig(opts, hop)
+		keys = append(keys, hopOpts.User+"@"+net.JoinHostPort(hop.Host, strconv.Itoa(hop.Port)))
+
+		jc, err := p.acquireHop(ctx, strings.Join(keys, ","), hopOpts, auth, parent)
+		if err != nil {
+			p.release(parent)
+			return nil, fmt.Errorf("failed to connect to jump host %s: %w", hop, err)
+		}
+		parent = jc
+	}
+	return parent, nil
+}
+
+// acquireHop returns the connection to the hop with a reference taken. The pool lock is held only to look up or
+// publish the client, so that a slow hop doesn't block the hosts behind other hops.
+func (p *jumpPool) acquireHop(ctx context.Context, key string, opts *sshfile.Config, auth *authOptions, parent *jumpClient) (*jumpClient, error) {
+	p.mu.Lock()
+	jc, ok := p.clients[key]
+	if !ok {
+		jc = &jumpClient{key: key, parent: parent, dialed: make(chan struct{})}
+		p.clients[key] = jc
+	}
+	jc.refs++
+	p.mu.Unlock()
+
+	if ok {
+		<-jc.dialed
+	} else {
+		jc.client, jc.err = dialHop(ctx, opts, auth, parent)
+		close(jc.dialed)
+		if jc.err == nil {
+			go p.forgetOnDisconnect(jc)
+		}
+	}
+	if jc.err != nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		jc.refs--
+		if p.clients[jc.key] == jc {
+			delete(p.clients, jc.key)
+		}
+		return nil, jc.err
+	}
+	return jc, nil
+}
+
+// release drops a reference to the client and all its parents. Clients no longer used by anyone are closed.
+func (p *jumpPool) release(jc *jumpClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.releaseLocked(jc)
+}
+
+func (p *jumpPool) releaseLocked(jc *jumpClient) {
+	for ; jc != nil; jc = jc.parent {
+		jc.refs--
+		if jc.refs > 0 {
+			continue
+		}
+		if p.clients[jc.key] == jc {
+			delete(p.clients, jc.key)
+		}
+		if err := jc.client.Close(); err != nil {
+			display.Debug(nil, "Error while closing connection to jump host %s: %s", jc.key, err)
+		}
+	}
+}
+
+// forgetOnDisconnect removes a dead bastion connection from the pool, so that the next acquire dials it again.
+func (p *jumpPool) forgetOnDisconnect(jc *jumpClient) {
+	err := jc.client.Wait()
+	display.Debug(nil, "Connection to jump host %s closed: %v", jc.key, err)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clients[jc.key] == jc {
+		delete(p.clients, jc.key)
+	}
+}
+
+// hopConfig returns the ssh options used to connect to the jump host. Options other than the destination are
+// inherited from the target host configuration.
+func hopConfig(opts *sshfile.Config, hop jumpHost) *sshfile.Config {
+	hopOpts := *opts
+	hopOpts.Hostname = hop.Host
+	hopOpts.Port = hop.Port
+	if hop.User != "" {
+		hopOpts.User = hop.User
+	}
+	return &hopOpts
+}
+
+func dialHop(ctx context.Context, opts *sshfile.Config, auth *authOptions, parent *jumpClient) (*ssh.Client, error) {
+	state := &handshakeState{}
+	cfg, err := buildConfig(ctx, opts, auth, state)
+	if err != nil {
+		return nil, err
+	}
+
+	var tcpConn net.Conn
+	if parent != nil {
+		tcpConn, err = parent.client.Dial(cfg.Network, cfg.Address)
+	} else {
+		tcpConn, err = sshutil.DialContext(context.WithValue(ctx, gsh.ConfigKey, cfg), cfg.Network, cfg.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(tcpConn, cfg.Address, &cfg.ClientConfig)
+	if err != nil {
+		_ = tcpConn.Close()
+		return nil, state.wrapError(err)
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// handshakeState records how far the ssh handshake got, as the ssh library doesn't return typed errors for
+// rejected host keys and failed authentication.
+type handshakeState struct {
+	hostKeyErr      error
+	hostKeyAccepted bool
+}
+
+// wrapError marks the handshake error as an authentication failure if the host key was rejected, or if it was
+// accepted and the handshake failed afterwards, when authenticating. Other failures make the host unreachable.
+func (s *handshakeState) wrapError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case s.hostKeyErr != nil:
+		return connection.NewAuthenticationError(fmt.Errorf("host key verification failed: %w", s.hostKeyErr))
+	case s.hostKeyAccepted:
+		return connection.NewAuthenticationError(err)
+	}
+	return connection.NewUnreachableError(err)
+}
+
+// buildConfig turns ssh options into the client configuration used for dialing.
+func buildConfig(ctx context.Context, opts *sshfile.Config, auth *authOptions, state *handshakeState) (*gsh.Config, error) {
+	// The identity file is handled by authOptions, which also supports certificates and ssh-agent.
+	withoutIdentity := *opts
+	withoutIdentity.IdentityFile = ""
+	cfg, err := withoutIdentity.Callback()(ctx, "tcp", "")
+	if err != nil {
+		return nil, err
+	}
+	authMethod, err := auth.authMethod(opts.IdentityFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build identity auth: %w", err)
+	}
+	checkHostKey := cfg.HostKeyCallback
+	cfg.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := checkHostKey(hostname, remote, key)
+		state.hostKeyErr, state.hostKeyAccepted = err, err == nil
+		return err
+	}
+	return cfg.WithAuth(authMethod), nil
+}
@@ -0,0 +1,199 @@
+package sshConnection
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"github.com/rjeczalik/gsh/sshfile"
+	"github.com/scylladb/gosible/connection"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stalledHop accepts connections but never answers the ssh handshake, until it is closed.
+type stalledHop struct {
+	listener net.Listener
+	accepted int32
+	mu       sync.Mutex
+	conns    []net.Conn
+}
+
+func newStalledHop(t *testing.T) *stalledHop {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &stalledHop{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&h.accepted, 1)
+			h.mu.Lock()
+			h.conns = append(h.conns, conn)
+			h.mu.Unlock()
+		}
+	}()
+	return h
+}
+
+func (h *stalledHop) close() {
+	_ = h.listener.Close()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, conn := range h.conns {
+		_ = conn.Close()
+	}
+}
+
+func (h *stalledHop) jumpHost() jumpHost {
+	addr := h.listener.Addr().(*net.TCPAddr)
+	return jumpHost{Host: addr.IP.String(), Port: addr.Port}
+}
+
+func TestJumpPoolDialsOutsideLock(t *testing.T) {
+	pool := &jumpPool{clients: make(map[string]*jumpClient)}
+	opts := &sshfile.Config{User: "test"}
+	auth := &authOptions{}
+	ctx := context.Background()
+
+	slow := newStalledHop(t)
+	defer slow.close()
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := pool.acquire(ctx, []jumpHost{slow.jumpHost()}, opts, auth)
+			errs <- err
+		}()
+	}
+	for atomic.LoadInt32(&slow.accepted) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A hop without a listener fails while the slow hop is still being dialed.
+	refused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := refused.Addr().(*net.TCPAddr)
+	_ = refused.Close()
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.acquire(ctx, []jumpHost{{Host: addr.IP.String(), Port: addr.Port}}, opts, auth)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected error connecting to a closed port")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("acquire blocked by the dial of another jump host")
+	}
+
+	slow.close()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err == nil {
+			t.Error("expected error from the stalled jump host")
+		}
+	}
+	if accepted := atomic.LoadInt32(&slow.accepted); accepted != 1 {
+		t.Error("expected the jump host to be dialed once, got", accepted)
+	}
+	if len(pool.clients) != 0 {
+		t.Error("expected no clients left after failed dials, got", pool.clients)
+	}
+}
+
+// newRejectingServer starts an ssh server refusing all clients' credentials and returns its address.
+func newRejectingServer(t *testing.T) *net.TCPAddr {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(ssh.ConnMetadata, ssh.PublicKey) (*ssh.Permissions, error) {
+			return nil, errors.New("denied")
+		},
+	}
+	cfg.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _, _, _ = ssh.NewServerConn(conn, cfg)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return l.Addr().(*net.TCPAddr)
+}
+
+func TestDialHopErrors(t *testing.T) {
+	addr := newRejectingServer(t)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublicKey, err := ssh.NewPublicKey(otherKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(addr.String())}, otherPublicKey)
+	if err = os.WriteFile(knownHosts, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().(*net.TCPAddr)
+	_ = closed.Close()
+
+	testCases := []struct {
+		name      string
+		opts      *sshfile.Config
+		retryable bool
+	}{
+		{"rejected credentials", &sshfile.Config{Hostname: addr.IP.String(), Port: addr.Port, User: "test"}, false},
+		{"changed host key", &sshfile.Config{Hostname: addr.IP.String(), Port: addr.Port, User: "test", UserKnownHostsFile: knownHosts}, false},
+		{"closed port", &sshfile.Config{Hostname: closedAddr.IP.String(), Port: closedAddr.Port, User: "test"}, true},
+	}
+	for _, tc := range testCases {
+		_, err := dialHop(context.Background(), tc.opts, &authOptions{}, nil)
+		if err == nil {
+			t.Error("on", tc.name, "expected error")
+			continue
+		}
+		if !connection.IsUnreachable(err) {
+			t.Error("on", tc.name, "expected", err, "to make the host unreachable")
+		}
+		if connection.IsRetryable(err) != tc.retryable {
+			t.Error("on", tc.name, "expected retryable", tc.retryable, "got", err)
+		}
+	}
+}
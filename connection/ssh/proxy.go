@@ -0,0 +1,224 @@
+package sshConnection
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/shlex"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/stdIoConn"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// sshFlagsWithValue lists the ssh(1) command line flags which take an argument.
+const sshFlagsWithValue = "BbcDEeFIiJLlmOopQRSWw"
+
+// sshArgs holds the parts of ansible_ssh_common_args and ansible_ssh_extra_args understood by the connection.
+type sshArgs struct {
+	// options are `Key=Value` pairs in the format accepted by sshfile.ParseOptions.
+	options         []string
+	proxyJump       string
+	proxyCommand    string
+	identityAgent   string
+	certificateFile string
+	forwardAgent    bool
+}
+
+// parseSshArgs parses ssh(1) style command line arguments. Both `-o Key=Value` options and bare `Key=Value`
+// pairs are accepted. As in ssh, the first obtained value of ProxyJump and ProxyCommand is used.
+func parseSshArgs(args ...string) (*sshArgs, error) {
+	res := &sshArgs{}
+	for _, arg := range args {
+		words, err := shlex.Split(arg)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(words); i++ {
+			word := words[i]
+			if !strings.HasPrefix(word, "-") || len(word) < 2 {
+				res.addOption(word)
+				continue
+			}
+			flag := word[1]
+			if flag == 'A' || flag == 'a' {
+				res.addOption("ForwardAgent=" + strconv.FormatBool(flag == 'A'))
+				continue
+			}
+			if !strings.ContainsRune(sshFlagsWithValue, rune(flag)) {
+				display.Warning(display.WarnOptions{}, "Ignoring unsupported ssh argument %s", word)
+				continue
+			}
+			value := word[2:]
+			if value == "" {
+				if i+1 >= len(words) {
+					return nil, fmt.Errorf("ssh argument %s requires a value", word)
+				}
+				i++
+				value = words[i]
+			}
+			switch flag {
+			case 'o':
+				res.addOption(value)
+			case 'J':
+				res.addOption("ProxyJump=" + value)
+			case 'p':
+				res.addOption("Port=" + value)
+			case 'l':
+				res.addOption("User=" + value)
+			case 'i':
+				res.addOption("IdentityFile=" + value)
+			default:
+				display.Warning(display.WarnOptions{}, "Ignoring unsupported ssh argument %s %s", word[:2], value)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (a *sshArgs) addOption(option string) {
+	key, value := splitOption(option)
+	switch strings.ToLower(key) {
+	case "proxyjump":
+		if a.proxyJump == "" {
+			a.proxyJump = value
+		}
+	case "proxycommand":
+		if a.proxyCommand == "" {
+			a.proxyCommand = value
+		}
+	case "identityagent":
+		a.identityAgent = value
+	case "certificatefile":
+		a.certificateFile = value
+	case "forwardagent":
+		a.forwardAgent = strings.EqualFold(value, "yes") || strings.EqualFold(value, "true")
+	default:
+		a.options = append(a.options, option)
+	}
+}
+
+// splitOption splits an ssh option on the first `=` or space, whichever comes first.
+func splitOption(option string) (string, string) {
+	idx := strings.IndexAny(option, "= ")
+	if idx < 0 {
+		return option, ""
+	}
+	return strings.TrimSpace(option[:idx]), strings.TrimSpace(option[idx+1:])
+}
+
+// jumpHost is a single hop of a ProxyJump chain.
+type jumpHost struct {
+	User string
+	Host string
+	Port int
+}
+
+func (j jumpHost) String() string {
+	addr := net.JoinHostPort(j.Host, strconv.Itoa(j.Port))
+	if j.User == "" {
+		return addr
+	}
+	return j.User + "@" + addr
+}
+
+// parseProxyJump parses a comma separated list of `[user@]host[:port]` or `ssh://[user@]host[:port]` destinations.
+// Returns nil when the spec is empty or `none`.
+func parseProxyJump(spec string) ([]jumpHost, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.EqualFold(spec, "none") {
+		return nil, nil
+	}
+
+	var hops []jumpHost
+	for _, dest := range strings.Split(spec, ",") {
+		dest = strings.TrimPrefix(strings.TrimSpace(dest), "ssh://")
+		if dest == "" {
+			return nil, fmt.Errorf("empty destination in ProxyJump `%s`", spec)
+		}
+		var hop jumpHost
+		if idx := strings.LastIndex(dest, "@"); idx >= 0 {
+			hop.User, dest = dest[:idx], dest[idx+1:]
+		}
+		if strings.HasPrefix(dest, "[") || strings.Count(dest, ":") == 1 {
+			host, port, err := net.SplitHostPort(dest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid destination in ProxyJump `%s`: %w", spec, err)
+			}
+			if hop.Port, err = strconv.Atoi(port); err != nil {
+				return nil, fmt.Errorf("invalid port in ProxyJump `%s`: %w", spec, err)
+			}
+			hop.Host = host
+		} else {
+			hop.Host = dest
+		}
+		if hop.Host == "" {
+			return nil, fmt.Errorf("empty host in ProxyJump `%s`", spec)
+		}
+		if hop.Port == 0 {
+			hop.Port = 22
+		}
+		hops = append(hops, hop)
+	}
+	return hops, nil
+}
+
+// expandProxyCommand substitutes the `%h`, `%p`, `%r` and `%%` tokens in a ProxyCommand.
+func expandProxyCommand(cmd, host, port, user string) string {
+	var b strings.Builder
+	for i := 0; i < len(cmd); i++ {
+		if cmd[i] != '%' || i+1 == len(cmd) {
+			b.WriteByte(cmd[i])
+			continue
+		}
+		i++
+		switch cmd[i] {
+		case 'h':
+			b.WriteString(host)
+		case 'p':
+			b.WriteString(port)
+		case 'r':
+			b.WriteString(user)
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(cmd[i])
+		}
+	}
+	return b.String()
+}
+
+// dialProxyCommand starts the ProxyCommand locally and returns a connection speaking over its stdin and stdout.
+func dialProxyCommand(ctx context.Context, proxyCommand, addr, user string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	cmdLine := expandProxyCommand(proxyCommand, host, port, user)
+	display.Debug(nil, "Starting ssh ProxyCommand: %s", cmdLine)
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", cmdLine)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ProxyCommand `%s`: %w", cmdLine, err)
+	}
+
+	closer := customCloser(func() error {
+		_ = stdin.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil
+	})
+	return stdIoConn.NewStdIoConn(stdout, stdin, closer), nil
+}
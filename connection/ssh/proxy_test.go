@@ -0,0 +1,104 @@
+package sshConnection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSshArgs(t *testing.T) {
+	testCases := []struct {
+		args         []string
+		options      []string
+		proxyJump    string
+		proxyCommand string
+		forwardAgent bool
+	}{
+		{
+			args:    []string{"StrictHostKeyChecking=no"},
+			options: []string{"StrictHostKeyChecking=no"},
+		},
+		{
+			args:      []string{"-o StrictHostKeyChecking=no -J admin@bastion", "-oUserKnownHostsFile=/dev/null"},
+			options:   []string{"StrictHostKeyChecking=no", "UserKnownHostsFile=/dev/null"},
+			proxyJump: "admin@bastion",
+		},
+		{
+			args:         []string{`-o ProxyCommand="ssh -W %h:%p -q bastion"`, "-o ProxyCommand=other"},
+			proxyCommand: "ssh -W %h:%p -q bastion",
+		},
+		{
+			args:         []string{"-A -o IdentityAgent=/tmp/agent.sock"},
+			forwardAgent: true,
+		},
+		{
+			args:      []string{"-o ProxyJump=first", "-J second -p 2222 -C"},
+			options:   []string{"Port=2222"},
+			proxyJump: "first",
+		},
+	}
+
+	for _, testCase := range testCases {
+		res, err := parseSshArgs(testCase.args...)
+		if err != nil {
+			t.Fatal("on", testCase.args, "unexpected error", err)
+		}
+		if !reflect.DeepEqual(res.options, testCase.options) {
+			t.Error("on", testCase.args, "expected options", testCase.options, "got", res.options)
+		}
+		if res.proxyJump != testCase.proxyJump {
+			t.Error("on", testCase.args, "expected ProxyJump", testCase.proxyJump, "got", res.proxyJump)
+		}
+		if res.proxyCommand != testCase.proxyCommand {
+			t.Error("on", testCase.args, "expected ProxyCommand", testCase.proxyCommand, "got", res.proxyCommand)
+		}
+		if res.forwardAgent != testCase.forwardAgent {
+			t.Error("on", testCase.args, "expected ForwardAgent", testCase.forwardAgent, "got", res.forwardAgent)
+		}
+	}
+
+	if _, err := parseSshArgs("-o"); err == nil {
+		t.Error("expected error on flag without a value")
+	}
+}
+
+func TestParseProxyJump(t *testing.T) {
+	testCases := []struct {
+		spec string
+		hops []jumpHost
+	}{
+		{spec: "", hops: nil},
+		{spec: "none", hops: nil},
+		{spec: "bastion", hops: []jumpHost{{Host: "bastion", Port: 22}}},
+		{spec: "admin@bastion:2222", hops: []jumpHost{{User: "admin", Host: "bastion", Port: 2222}}},
+		{spec: "ssh://admin@[::1]:2222", hops: []jumpHost{{User: "admin", Host: "::1", Port: 2222}}},
+		{spec: "fe80::1", hops: []jumpHost{{Host: "fe80::1", Port: 22}}},
+		{
+			spec: "a@first,second:23",
+			hops: []jumpHost{{User: "a", Host: "first", Port: 22}, {Host: "second", Port: 23}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		hops, err := parseProxyJump(testCase.spec)
+		if err != nil {
+			t.Fatal("on", testCase.spec, "unexpected error", err)
+		}
+		if !reflect.DeepEqual(hops, testCase.hops) {
+			t.Error("on", testCase.spec, "expected", testCase.hops, "got", hops)
+		}
+	}
+
+	for _, spec := range []string{"first,,second", "bastion:port", "user@"} {
+		if _, err := parseProxyJump(spec); err == nil {
+			t.Error("expected error on", spec)
+		}
+	}
+}
+
+func TestExpandProxyCommand(t *testing.T) {
+	res := expandProxyCommand("ssh -W %h:%p -l %r 100%% %x%", "host", "22", "user")
+	expected := "ssh -W host:22 -l user 100% %x%"
+	if res != expected {
+		t.Fatal("expected", expected, "got", res)
+	}
+}
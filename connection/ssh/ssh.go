@@ -4,8 +4,10 @@ package sshConnection
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"github.com/alessio/shellescape"
 	"github.com/bramvdbogaerde/go-scp"
-	"github.com/google/shlex"
 	"github.com/rjeczalik/gsh"
 	"github.com/rjeczalik/gsh/sshfile"
 	"github.com/rjeczalik/gsh/sshutil"
@@ -14,8 +16,13 @@ import (
 	"github.com/scylladb/gosible/utils/shell"
 	"github.com/scylladb/gosible/utils/types"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 	"io"
+	"math/rand"
+	"net"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type ConnectionData struct {
@@ -24,19 +31,33 @@ type ConnectionData struct {
 	IdentityFile string
 	Password     string
 	Port         string
+	CommonArgs   string
 	Args         string
 }
 
 type Connection struct {
 	Conn  gsh.Conn
 	shell shell.Shell
+	// jump is the bastion connection the host is reached through, nil if connected directly.
+	jump         *jumpClient
+	client       *ssh.Client
+	forwardAgent bool
 }
 
 // Interface compliance check.
 var _ connection.Connection = &Connection{}
+var _ connection.HealthChecker = &Connection{}
+
+// keepAliveTimeout is the time CheckAlive waits for the server to respond.
+var keepAliveTimeout = 5 * time.Second
 
 func (conn *Connection) Close() error {
-	return conn.Conn.Close()
+	err := conn.Conn.Close()
+	if conn.jump != nil {
+		bastions.release(conn.jump)
+		conn.jump = nil
+	}
+	return err
 }
 
 func (conn *Connection) Shell() shell.Shell {
@@ -44,44 +65,111 @@ func (conn *Connection) Shell() shell.Shell {
 }
 
 func (conn *Connection) SendFile(f io.Reader, path string, mode string) error {
+	// scp fails to overwrite files which are not writable, e.g. executables, so the file is written under
+	// a temporary name and moved into place, which also makes the write atomic.
+	tmpPath := fmt.Sprintf("%s.%d.tmp", path, rand.Int63())
 	var sendFileHandler gsh.SessionFunc = func(conn context.Context, session *ssh.Session) error {
 		client := scp.NewConfigurer("", nil).Session(session).Create()
 		defer client.Close()
 
-		// FIXME this function breaks when file already exists. Also error is misleading.
-		return client.CopyFile(f, path, mode)
+		if err := client.CopyFile(f, tmpPath, mode); err != nil {
+			return fmt.Errorf("failed to copy file to %s: %w", tmpPath, err)
+		}
+		return nil
+	}
+	if err := conn.Conn.Session(sendFileHandler); err != nil {
+		return err
 	}
 
-	return conn.Conn.Session(sendFileHandler)
+	var moveHandler gsh.SessionFunc = func(conn context.Context, session *ssh.Session) error {
+		if out, err := session.CombinedOutput("mv -f " + shellescape.Quote(tmpPath) + " " + shellescape.Quote(path)); err != nil {
+			return fmt.Errorf("failed to move file to %s: %w: %s", path, err, bytes.TrimSpace(out))
+		}
+		return nil
+	}
+	return conn.Conn.Session(moveHandler)
 }
 
 func New(data *ConnectionData, sh shell.Shell) (*Connection, error) {
-	cfg, err := getConfig(data)
-
+	args, err := parseSshArgs(data.CommonArgs, data.Args)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := getConfig(data, args)
 	if err != nil {
 		return nil, err
 	}
+	hops, err := parseProxyJump(args.proxyJump)
+	if err != nil {
+		return nil, err
+	}
+	if len(hops) > 0 && args.proxyCommand != "" && !strings.EqualFold(args.proxyCommand, "none") {
+		return nil, errors.New("ProxyJump and ProxyCommand can't be used at the same time")
+	}
 
+	auth := newAuthOptions(args)
 	ctx := context.Background()
 
+	var jump *jumpClient
+	dial := sshutil.DialContext
+	if len(hops) > 0 {
+		display.Debug(nil, "Connecting to %s through jump hosts %s", data.HostName, args.proxyJump)
+		if jump, err = bastions.acquire(ctx, hops, cfg, auth); err != nil {
+			return nil, err
+		}
+		dial = func(_ context.Context, network, addr string) (net.Conn, error) {
+			return jump.client.Dial(network, addr)
+		}
+	} else if args.proxyCommand != "" && !strings.EqualFold(args.proxyCommand, "none") {
+		dial = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialProxyCommand(ctx, args.proxyCommand, addr, cfg.User)
+		}
+	}
+
+	state := &handshakeState{}
 	client := &gsh.Client{
-		ConfigCallback: cfg.Callback(),
-		DialContext:    sshutil.DialContext,
+		ConfigCallback: func(ctx context.Context, _, _ string) (*gsh.Config, error) {
+			return buildConfig(ctx, cfg, auth, state)
+		},
+		DialContext: dial,
 	}
 
+	var sshClient *ssh.Client
 	conn, err := client.Connect(ctx, "tcp", "")
+	err = state.wrapError(err)
+	if err == nil {
+		sshClient = conn.Context().Value(gsh.ClientKey).(*ssh.Client)
+		if err = auth.setupAgentForwarding(sshClient); err != nil {
+			_ = conn.Close()
+		}
+	}
 	if err != nil {
+		if jump != nil {
+			bastions.release(jump)
+		}
 		return nil, err
 	}
-	return &Connection{conn, sh}, nil
+	return &Connection{Conn: conn, shell: sh, jump: jump, client: sshClient, forwardAgent: auth.forwardAgent}, nil
 }
 
-func getConfig(data *ConnectionData) (*sshfile.Config, error) {
-	words, err := shlex.Split(data.Args)
-	if err != nil {
-		return nil, err
+// CheckAlive sends a keepalive request to verify that the ssh transport still works.
+func (conn *Connection) CheckAlive() error {
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := conn.client.SendRequest("keepalive@openssh.com", true, nil)
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(keepAliveTimeout):
+		return errors.New("ssh keepalive timed out")
 	}
-	opts, err := sshfile.ParseOptions(words)
+}
+
+func getConfig(data *ConnectionData, args *sshArgs) (*sshfile.Config, error) {
+	opts, err := sshfile.ParseOptions(args.options)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +207,7 @@ func (c customCloser) Close() error {
 func (conn *Connection) ExecInteractiveCommand(cmd string, becomeArgs *types.BecomeArgs) (pipes *types.ProcessPipes, closer io.Closer, err error) {
 	done := make(chan bool, 1)
 	sh := conn.Shell()
+	forwardAgent := conn.forwardAgent
 	var execCommandInteractiveHandler gsh.SessionFunc = func(conn context.Context, session *ssh.Session) error {
 		// We want the customCloser to capture err returned from session.Wait()
 		// this way we can propagate the error when closer is called -- otherwise
@@ -131,6 +220,11 @@ func (conn *Connection) ExecInteractiveCommand(cmd string, becomeArgs *types.Bec
 			}
 			return sessionCloseErr
 		})
+		if forwardAgent {
+			if err = agent.RequestAgentForwarding(session); err != nil {
+				return err
+			}
+		}
 		if becomeArgs.Become {
 			pipes, err = connection.StartBecome(session, cmd, becomeArgs, sh)
 			if err != nil {
@@ -166,31 +260,20 @@ func (conn *Connection) ExecInteractiveCommand(cmd string, becomeArgs *types.Bec
 }
 
 func FromVars(vars types.Vars, sh shell.Shell) (*Connection, error) {
-	var identityFile, pass, args, hostName, port, user string
-
-	if identityFileVal, ok := vars["private_key_file"].(string); ok {
-		identityFile = identityFileVal
-	}
-	if passVal, ok := vars["password"].(string); ok {
-		pass = passVal
-	}
-	if argsVal, ok := vars["ssh_extra_args"].(string); ok {
-		args = argsVal
-	}
-	if hostNameVal, ok := vars["remote_addr"].(string); ok {
-		hostName = hostNameVal
-	}
-	if portVal, ok := vars["port"].(string); ok {
-		port = portVal
-	}
-	if userVal, ok := vars["remote_user"].(string); ok {
-		user = userVal
-	}
+	// Values are formatted, as typed inventories give e.g. the port as a number.
+	identityFile, _ := vars.GetString("private_key_file")
+	pass, _ := vars.GetString("password")
+	commonArgs, _ := vars.GetString("ssh_common_args")
+	args, _ := vars.GetString("ssh_extra_args")
+	hostName, _ := vars.GetString("remote_addr")
+	port, _ := vars.GetString("port")
+	user, _ := vars.GetString("remote_user")
 
 	return New(&ConnectionData{
 		HostName:     hostName,
 		Port:         port,
 		User:         user,
+		CommonArgs:   commonArgs,
 		Args:         args,
 		IdentityFile: identityFile,
 		Password:     pass,
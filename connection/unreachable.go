@@ -0,0 +1,84 @@
+package connection
+
+import (
+	"errors"
+	"github.com/rjeczalik/gsh/sshfile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+)
+
+// UnreachableError marks an error caused by a host which could not be contacted.
+type UnreachableError struct {
+	Err error
+}
+
+func NewUnreachableError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var unreachable *UnreachableError
+	if errors.As(err, &unreachable) {
+		return err
+	}
+	return &UnreachableError{Err: err}
+}
+
+func (e *UnreachableError) Error() string {
+	return "host unreachable: " + e.Err.Error()
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// AuthenticationError marks a host which was contacted but refused the credentials or whose host key could not
+// be verified. The host is unreachable, but unlike a broken transport, retrying doesn't help.
+type AuthenticationError struct {
+	Err error
+}
+
+func NewAuthenticationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &AuthenticationError{Err: err}
+}
+
+func (e *AuthenticationError) Error() string {
+	return "authentication failed: " + e.Err.Error()
+}
+
+func (e *AuthenticationError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether connecting to the host again may succeed, i.e. whether the host is unreachable
+// because of a transport failure rather than rejected credentials or host key.
+func IsRetryable(err error) bool {
+	var authErr *AuthenticationError
+	return IsUnreachable(err) && !errors.As(err, &authErr)
+}
+
+// IsUnreachable reports whether the error was caused by a broken or impossible to establish connection to the host,
+// as opposed to a failure of the executed task. Only transport errors are considered: errors marked as unreachable
+// where the connection is established, failed dials and failed gRPC transports.
+func IsUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var unreachable *UnreachableError
+	var authErr *AuthenticationError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &unreachable), errors.As(err, &authErr):
+		return true
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return true
+	case errors.Is(err, sshfile.NoAuthMethods):
+		return true
+	}
+	// The remote executor never returns Unavailable itself, the gRPC client does when its transport fails.
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	return errors.As(err, &grpcErr) && grpcErr.GRPCStatus().Code() == codes.Unavailable
+}
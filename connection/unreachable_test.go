@@ -0,0 +1,59 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestIsUnreachable(t *testing.T) {
+	unreachable := []error{
+		NewUnreachableError(errors.New("connect failed")),
+		fmt.Errorf("on host h, %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+		fmt.Errorf("on task t, %w", status.Error(codes.Unavailable, "transport is closing")),
+		fmt.Errorf("on host h, %w", NewAuthenticationError(errors.New("unable to authenticate"))),
+	}
+	reachable := []error{
+		nil,
+		errors.New("module failed"),
+		status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+		// Errors of modules or plugins which merely wrap an EOF or a timeout don't make the host unreachable.
+		fmt.Errorf("while reading, %w", io.EOF),
+		fmt.Errorf("on task t, %w", &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}),
+		status.Error(codes.Unknown, "get_url failed: EOF"),
+		errors.New("ssh connection error: ssh: handshake failed: EOF"),
+	}
+
+	for _, err := range unreachable {
+		if !IsUnreachable(err) {
+			t.Error("expected", err, "to be classified as unreachable")
+		}
+	}
+	for _, err := range reachable {
+		if IsUnreachable(err) {
+			t.Error("expected", err, "not to be classified as unreachable")
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		err       error
+		retryable bool
+	}{
+		{NewUnreachableError(errors.New("connect failed")), true},
+		{NewUnreachableError(NewAuthenticationError(errors.New("unable to authenticate"))), false},
+		{fmt.Errorf("on host h, %w", NewAuthenticationError(errors.New("key mismatch"))), false},
+		{errors.New("module failed"), false},
+	}
+	for _, tc := range testCases {
+		if IsRetryable(tc.err) != tc.retryable {
+			t.Error("on", tc.err, "expected retryable", tc.retryable)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package conn
 
 import (
+	"context"
 	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/connection"
 	"github.com/scylladb/gosible/connection/factory"
 	"github.com/scylladb/gosible/inventory"
 	playbookTypes "github.com/scylladb/gosible/playbook/types"
@@ -13,14 +16,22 @@ import (
 	"github.com/scylladb/gosible/utils/parallel"
 	"github.com/scylladb/gosible/utils/shell"
 	"github.com/scylladb/gosible/utils/types"
+	"sync"
+	"time"
 )
 
+// Manager keeps the connections to a single host. One transport (e.g. SSH connection) is shared by the default
+// session and all become sessions, each of them running its own remote executor.
 type Manager struct {
-	defaultConn *plugins.ConnectionContext
-	becomeConns map[string]*plugins.ConnectionContext
-	Host        *inventory.Host
-	vars        types.Vars
-	passwords   types.Passwords
+	transport connection.Connection
+	// sessions are keyed by sessionKey.
+	sessions  map[string]*plugins.ConnectionContext
+	Host      *inventory.Host
+	vars      types.Vars
+	passwords types.Passwords
+	// lastUsed is when a session was last handed out, the transport is probed only after it was idle for a while.
+	lastUsed time.Time
+	lock     sync.Mutex
 }
 
 const argBecome = "become"
@@ -29,84 +40,232 @@ const argBecomeMethod = "become_method"
 const argBecomeFlags = "become_flags"
 
 const varBecomePassword = "become_pass"
+const varPassword = "password"
+
+const defaultSessionKey = ""
+
+const (
+	initialReconnectBackoff = 250 * time.Millisecond
+	maxReconnectBackoff     = 5 * time.Second
+	helloTimeout            = 10 * time.Second
+	// idleCheckInterval is the idle time after which the transport is probed before it is used again.
+	idleCheckInterval = 15 * time.Second
+)
 
 func NewManager(host *inventory.Host, vars types.Vars, passwords types.Passwords) *Manager {
-	return &Manager{Host: host, vars: vars, becomeConns: make(map[string]*plugins.ConnectionContext), passwords: passwords}
+	return &Manager{Host: host, vars: vars, sessions: make(map[string]*plugins.ConnectionContext), passwords: passwords}
 }
 
 func (cm *Manager) UpdateOpts(vars types.Vars) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
 	cm.vars = vars
 }
 
+// Close closes all sessions and the transport. Connections are re-established when the next task needs them.
 func (cm *Manager) Close() error {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	return cm.closeAll()
+}
+
+// Reset re-establishes connections to the host, as requested by `meta: reset_connection`.
+func (cm *Manager) Reset() error {
+	display.Debug(&cm.Host.Name, "Resetting connection to host")
+	return cm.Close()
+}
+
+func (cm *Manager) closeAll() error {
 	f := func(c *plugins.ConnectionContext) error { return c.Close() }
-	if errors := parallel.ForAll(maps.Values(cm.becomeConns), f); errors.IsError() {
+	errors := parallel.ForAll(maps.Values(cm.sessions), f)
+	cm.sessions = make(map[string]*plugins.ConnectionContext)
+	errors = append(errors, cm.closeTransport())
+	if errors.IsError() {
 		return fmt.Errorf("while closing connection to host %s, %w", cm.Host.Name, errors.Combine())
 	}
 	return nil
 }
 
-func createConnection(host *inventory.Host, vars types.Vars, becomeArgs *types.BecomeArgs) (*plugins.ConnectionContext, error) {
-	display.Debug(&host.Name, "Creating a connection for host")
+func (cm *Manager) closeTransport() error {
+	if cm.transport == nil {
+		return nil
+	}
+	err := cm.transport.Close()
+	cm.transport = nil
+	return err
+}
+
+func (cm *Manager) getTransport() (connection.Connection, error) {
+	if cm.transport != nil {
+		return cm.transport, nil
+	}
+	display.Debug(&cm.Host.Name, "Creating a connection for host")
 
-	sh, err := shell.Get(vars)
+	sh, err := shell.Get(cm.vars)
 	if err != nil {
 		return nil, err
 	}
+	transport, err := factory.CreateConnection(cm.connectionVars(), sh)
+	if err != nil {
+		return nil, connection.NewUnreachableError(err)
+	}
+	cm.transport = transport
+	return transport, nil
+}
+
+// connectionVars returns the vars of the connection, with the connection password given on the command line
+// unless the host defines its own.
+func (cm *Manager) connectionVars() types.Vars {
+	if _, ok := cm.vars[varPassword]; ok || len(cm.passwords.Ssh) == 0 {
+		return cm.vars
+	}
+	vars := make(types.Vars, len(cm.vars)+1)
+	for k, v := range cm.vars {
+		vars[k] = v
+	}
+	vars[varPassword] = string(cm.passwords.Ssh)
+	return vars
+}
+
+// checkTransport closes the transport if it is no longer usable. Returns false in such case.
+func (cm *Manager) checkTransport() bool {
+	if cm.transport == nil {
+		return false
+	}
+	checker, ok := cm.transport.(connection.HealthChecker)
+	if !ok {
+		return true
+	}
+	if err := checker.CheckAlive(); err != nil {
+		display.Warning(display.WarnOptions{}, "Connection to host %s lost: %s", cm.Host.Name, err)
+		_ = cm.closeAll()
+		return false
+	}
+	return true
+}
 
-	conn, err := factory.CreateConnection(vars, sh)
+func (cm *Manager) createSession(becomeArgs *types.BecomeArgs) (*plugins.ConnectionContext, error) {
+	conn, err := cm.getTransport()
 	if err != nil {
 		return nil, err
 	}
-	display.Debug(&host.Name, "Starting the gosible executable on host and creating a grpc connection to it")
+	display.Debug(&cm.Host.Name, "Starting the gosible executable on host and creating a grpc connection to it")
 	grpcConn, err := remote.Execute(conn, becomeArgs)
 	if err != nil {
+		// Starting the executor fails also for reasons like a wrong become password, the host is unreachable
+		// only if the transport is broken.
+		if connection.IsUnreachable(err) || !cm.checkTransport() {
+			return nil, connection.NewUnreachableError(err)
+		}
 		return nil, err
 	}
 
-	return &plugins.ConnectionContext{
+	client := pb.NewGosibleClientClient(grpcConn)
+	agent, err := cm.hello(client)
+	if err != nil {
+		_ = grpcConn.Close()
+		return nil, err
+	}
+
+	ctx := &plugins.ConnectionContext{
 		Connection:           conn,
 		RemoteExecutorConn:   grpcConn,
-		RemoteExecutorClient: pb.NewGosibleClientClient(grpcConn),
-		Host:                 host,
-	}, nil
+		RemoteExecutorClient: client,
+		Agent:                agent,
+		Host:                 cm.Host,
+	}
+	cm.sessions[sessionKey(becomeArgs)] = ctx
+	return ctx, nil
 }
 
-func (cm *Manager) GetConnForTask(task *playbookTypes.Task) (*plugins.ConnectionContext, error) {
-	becomeArgs := cm.getBecomeArgs(task)
-	if becomeArgs.Become {
-		return cm.getBecomeConn(becomeArgs)
+func (cm *Manager) hello(client pb.GosibleClientClient) (*remote.AgentInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helloTimeout)
+	defer cancel()
+	agent, err := remote.Hello(ctx, client)
+	if connection.IsUnreachable(err) {
+		return nil, connection.NewUnreachableError(err)
+	} else if err != nil {
+		return nil, fmt.Errorf("host %s: %w", cm.Host.Name, err)
 	}
-	return cm.getDefaultConn()
+	return agent, nil
 }
 
-func (cm *Manager) getDefaultConn() (*plugins.ConnectionContext, error) {
-	if cm.defaultConn != nil {
-		return cm.defaultConn, nil
+// reconnect creates the session again, retrying with exponential backoff for PERSISTENT_CONNECT_RETRY_TIMEOUT seconds.
+// Only transport failures are retried, other errors like rejected credentials are returned immediately.
+func (cm *Manager) reconnect(becomeArgs *types.BecomeArgs) (*plugins.ConnectionContext, error) {
+	timeout := time.Duration(config.Manager().Settings.PERSISTENT_CONNECT_RETRY_TIMEOUT) * time.Second
+	deadline := time.Now().Add(timeout)
+	backoff := initialReconnectBackoff
+	for {
+		ctx, err := cm.createSession(becomeArgs)
+		if err == nil {
+			display.Debug(&cm.Host.Name, "Reconnected to host")
+			return ctx, nil
+		}
+		if !connection.IsRetryable(err) {
+			return nil, err
+		}
+		cm.checkTransport()
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, connection.NewUnreachableError(fmt.Errorf("failed to reconnect to host %s within %s: %w", cm.Host.Name, timeout, err))
+		}
+		display.Debug(&cm.Host.Name, "Reconnecting to host failed, retrying in %s: %s", backoff, err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
 	}
-	conn, err := createConnection(cm.Host, cm.vars, &types.BecomeArgs{})
-	if err != nil {
-		return nil, err
+}
+
+func (cm *Manager) GetConnForTask(task *playbookTypes.Task) (*plugins.ConnectionContext, error) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	becomeArgs := cm.getBecomeArgs(task)
+	if !becomeArgs.Become {
+		becomeArgs = &types.BecomeArgs{}
+	}
+	key := sessionKey(becomeArgs)
+	ctx, ok := cm.sessions[key]
+	if !ok {
+		return cm.useSession(cm.createSession(becomeArgs))
 	}
-	cm.defaultConn = conn
-	return conn, nil
+	// Probing the transport is a round-trip to the host, so it is done only after the connection was idle
+	// or when the session is broken.
+	healthy := ctx.IsHealthy()
+	if healthy && time.Since(cm.lastUsed) < idleCheckInterval {
+		return cm.useSession(ctx, nil)
+	}
+	if cm.checkTransport() && healthy {
+		return cm.useSession(ctx, nil)
+	}
+
+	display.Warning(display.WarnOptions{}, "Remote executor on host %s is not responding, reconnecting", cm.Host.Name)
+	if cm.sessions[key] == ctx {
+		_ = ctx.Close()
+		delete(cm.sessions, key)
+	}
+	return cm.useSession(cm.reconnect(becomeArgs))
 }
 
-func (cm *Manager) getBecomeConn(args *types.BecomeArgs) (*plugins.ConnectionContext, error) {
-	if conn, ok := cm.becomeConns[args.User]; ok {
-		return conn, nil
+func (cm *Manager) useSession(ctx *plugins.ConnectionContext, err error) (*plugins.ConnectionContext, error) {
+	if err == nil {
+		cm.lastUsed = time.Now()
 	}
-	conn, err := createConnection(cm.Host, cm.vars, args)
-	if err != nil {
-		return nil, err
+	return ctx, err
+}
+
+func sessionKey(becomeArgs *types.BecomeArgs) string {
+	if !becomeArgs.Become {
+		return defaultSessionKey
 	}
-	cm.becomeConns[args.User] = conn
-	return conn, nil
+	return "become:" + becomeArgs.User
 }
 
 func (cm *Manager) getBecomeArgs(task *playbookTypes.Task) *types.BecomeArgs {
 	args := types.NewBecomeArgs()
-	if pass, ok := cm.vars[varBecomePassword].(string); ok {
+	args.Password = string(cm.passwords.Become)
+	if pass, ok := cm.vars.GetString(varBecomePassword); ok {
 		args.Password = pass
 	}
 	if v, ok := task.Keywords[argBecome].(bool); ok {
@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"errors"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/modules"
+	playbookTypes "github.com/scylladb/gosible/playbook/types"
+	"github.com/scylladb/gosible/utils/types"
+)
+
+const gatherFactsTaskName = "Gathering Facts"
+
+// factsGatheringError is the failure of the setup module, which removes the host from the play like in Ansible.
+type factsGatheringError struct {
+	err error
+}
+
+func newFactsGatheringError(res *modules.Return, err error) error {
+	if err == nil {
+		msg := res.Msg
+		if msg == "" && res.InternalReturn != nil {
+			msg = res.Exception
+		}
+		err = errors.New(msg)
+	}
+	return &factsGatheringError{err}
+}
+
+func (e *factsGatheringError) Error() string {
+	return "gathering facts failed: " + e.err.Error()
+}
+
+func (e *factsGatheringError) Unwrap() error {
+	return e.err
+}
+
+// gatherFacts runs the setup module on the hosts of the play before its tasks. Whether the facts of a host are
+// gathered depends on gather_facts and DEFAULT_GATHERING: implicit gathers them unless gather_facts is false,
+// explicit only when gather_facts is true, and smart like implicit, but skips the hosts whose facts are cached.
+// Hosts whose facts can't be gathered are removed from the play.
+func (ex *playExecutor) gatherFacts() error {
+	gather := ex.factsGatheringFilter()
+	if gather == nil {
+		return nil
+	}
+	ex.factsTask = newGatherFactsTask(ex.play)
+	tasksExecutor := &tasksExecutor{
+		playExecutor: ex,
+		tasks:        []*playbookTypes.Task{ex.factsTask},
+		hostFilter:   gather,
+	}
+	return tasksExecutor.execute()
+}
+
+// factsGatheringFilter returns the function selecting the hosts whose facts are gathered, or nil if none are.
+func (ex *playExecutor) factsGatheringFilter() func(host *inventory.Host) bool {
+	gatherFacts := ex.play.GatherFacts
+	implied := gatherFacts == nil || *gatherFacts
+	all := func(*inventory.Host) bool { return true }
+
+	switch config.Manager().Settings.DEFAULT_GATHERING {
+	case "explicit":
+		if gatherFacts != nil && *gatherFacts {
+			return all
+		}
+	case "smart":
+		if implied {
+			return func(host *inventory.Host) bool { return !ex.varsManager.FactsGathered(host) }
+		}
+	default:
+		if implied {
+			return all
+		}
+	}
+	return nil
+}
+
+func newGatherFactsTask(play *playbookTypes.Play) *playbookTypes.Task {
+	settings := config.Manager().Settings
+	subset := play.GatherSubset
+	if subset == nil {
+		subset = settings.DEFAULT_GATHER_SUBSET
+	}
+	args := types.Vars{"gather_timeout": settings.DEFAULT_GATHER_TIMEOUT}
+	if len(subset) > 0 {
+		items := make([]interface{}, len(subset))
+		for i, s := range subset {
+			items[i] = s
+		}
+		args["gather_subset"] = items
+	}
+	if settings.DEFAULT_FACT_PATH != "" {
+		args["fact_path"] = settings.DEFAULT_FACT_PATH
+	}
+	return &playbookTypes.Task{
+		Name:   gatherFactsTaskName,
+		Action: &playbookTypes.Action{Name: "setup", Args: args},
+	}
+}
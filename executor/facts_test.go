@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/executor/moduleExecutor"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/modules"
+	defaultModules "github.com/scylladb/gosible/modules/default"
+	playbookTypes "github.com/scylladb/gosible/playbook/types"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"runtime"
+	"testing"
+)
+
+func TestGatherFactsTaskRunsThroughRegistry(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("facts are gathered only on linux")
+	}
+	reg := modules.NewRegistry()
+	defaultModules.Register(reg)
+	defaultModules.RegisterPython(reg)
+
+	subsets := [][]string{nil, {"min"}, {"!all"}}
+	for _, subset := range subsets {
+		task := newGatherFactsTask(&playbookTypes.Play{GatherSubset: subset})
+		mod, ok := reg.FindModule(task.Action.Name)
+		if !ok {
+			t.Fatal("on", subset, "expected module", task.Action.Name, "to be registered")
+		}
+		ret := mod.Run(&modules.RunContext{}, task.Action.Args)
+		if ret.Failed {
+			t.Error("on", subset, "expected success, got", ret.Msg)
+			continue
+		}
+		if ret.InternalReturn == nil || ret.AnsibleFacts["ansible_module_setup"] != true {
+			t.Error("on", subset, "expected setup facts, got", ret.InternalReturn)
+		}
+	}
+}
+
+// pythonlessClient runs the modules of the registry locally, like the remote executor on a host without Python,
+// where the discovery of the interpreter fails.
+type pythonlessClient struct {
+	pb.GosibleClientClient
+	modules     *modules.ModuleRegistry
+	discoveries int
+}
+
+func (c *pythonlessClient) DiscoverPythonInterpreter(context.Context, *pb.DiscoverPythonInterpreterRequest, ...grpc.CallOption) (*pb.DiscoverPythonInterpreterReply, error) {
+	c.discoveries++
+	return nil, status.Error(codes.Unknown, "no python interpreters found on host, set ansible_python_interpreter")
+}
+
+func (c *pythonlessClient) ExecuteModule(_ context.Context, req *pb.ExecuteModuleRequest, _ ...grpc.CallOption) (*pb.ExecuteModuleReply, error) {
+	mod, ok := c.modules.FindModule(req.ModuleName)
+	if !ok {
+		return nil, errors.New("module not found")
+	}
+	var vars types.Vars
+	if err := json.Unmarshal(req.VarsJson, &vars); err != nil {
+		return nil, err
+	}
+	ret, err := json.Marshal(mod.Run(&modules.RunContext{MetaArgs: req.MetaArgs}, vars))
+	return &pb.ExecuteModuleReply{ReturnValueJson: ret}, err
+}
+
+func TestImplicitGatheringWithoutPython(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("facts are gathered only on linux")
+	}
+	// The modules don't stream their output at the default verbosity.
+	if err := display.Instance().SetVerbosity(display.DefaultVerbosityLevel); err != nil {
+		t.Fatal(err)
+	}
+	reg := modules.NewRegistry()
+	defaultModules.Register(reg)
+	defaultModules.RegisterPython(reg)
+
+	play := &playbookTypes.Play{}
+	ex := &playExecutor{play: play}
+	if ex.factsGatheringFilter() == nil {
+		t.Fatal("expected facts to be gathered by default")
+	}
+	client := &pythonlessClient{modules: reg}
+	conn := &plugins.ConnectionContext{
+		RemoteExecutorClient: client,
+		Host:                 &inventory.Host{Name: "host"},
+		Agent:                remote.NewAgentInfo(runtime.GOOS, runtime.GOARCH, reg.Names()),
+	}
+	ret, err := moduleExecutor.ExecuteRemoteModuleTask(newGatherFactsTask(play), play, conn, types.Vars{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Failed {
+		t.Error("expected gathering to succeed, got", ret.Msg, ret.Exception)
+	}
+	if client.discoveries != 0 {
+		t.Error("expected no discovery of the Python interpreter, got", client.discoveries)
+	}
+}
+
+func TestFactsGatheringError(t *testing.T) {
+	testCases := []struct {
+		res      *modules.Return
+		err      error
+		expected string
+	}{
+		{&modules.Return{Failed: true, Msg: "invalid subset"}, nil, "gathering facts failed: invalid subset"},
+		{&modules.Return{Failed: true, InternalReturn: &modules.InternalReturn{Exception: "trace"}}, nil, "gathering facts failed: trace"},
+		{nil, errors.New("module crashed"), "gathering facts failed: module crashed"},
+	}
+	for _, tc := range testCases {
+		err := fmt.Errorf("on host h, %w", newFactsGatheringError(tc.res, tc.err))
+		var gatherErr *factsGatheringError
+		if !errors.As(err, &gatherErr) {
+			t.Error("on", tc.expected, "expected", err, "to be a facts gathering error")
+			continue
+		}
+		if gatherErr.Error() != tc.expected {
+			t.Error("on", tc.expected, "got", gatherErr.Error())
+		}
+	}
+}
@@ -3,7 +3,9 @@ package moduleExecutor
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/scylladb/gosible/module_utils/pythonModule"
 	"github.com/scylladb/gosible/modules"
 	playbookTypes "github.com/scylladb/gosible/playbook/types"
 	"github.com/scylladb/gosible/plugins"
@@ -21,10 +23,24 @@ import (
 const TimeoutRemoteTaskExecution = 10 * time.Second
 
 func ExecuteRemoteModuleTask(task *playbookTypes.Task, play *playbookTypes.Play, conn *plugins.ConnectionContext, varsEnv types.Vars) (*modules.Return, error) {
-	return executeRemoteModuleTask(task, play, conn, varsEnv, false)
+	if err := checkModuleSupported(task.Action.Name, conn); err != nil {
+		return nil, err
+	}
+	interpreter, discoveredFacts, err := getPythonInterpreter(task.Action.Name, conn, varsEnv)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := executeRemoteModuleTask(task, play, conn, varsEnv, interpreter, false)
+	if err != nil {
+		return nil, err
+	}
+	addFacts(ret, discoveredFacts)
+	display.Debug(&conn.Host.Name, spew.Sdump(ret))
+	// TODO do something meaningful with the execution result
+	return ret, nil
 }
 
-func executeRemoteModuleTask(task *playbookTypes.Task, play *playbookTypes.Play, conn *plugins.ConnectionContext, varsEnv types.Vars, uploadPyRuntime bool) (*modules.Return, error) {
+func executeRemoteModuleTask(task *playbookTypes.Task, play *playbookTypes.Play, conn *plugins.ConnectionContext, varsEnv types.Vars, interpreter string, uploadPyRuntime bool) (*modules.Return, error) {
 	preparedArgs, err := prepareArgs(task, varsEnv)
 	if err != nil {
 		return nil, err
@@ -33,7 +49,7 @@ func executeRemoteModuleTask(task *playbookTypes.Task, play *playbookTypes.Play,
 	if err != nil {
 		return nil, err
 	}
-	metaArgs, err := prepareMetaArgs(task, uploadPyRuntime)
+	metaArgs, err := prepareMetaArgs(interpreter, uploadPyRuntime)
 	if err != nil {
 		return nil, err
 	}
@@ -45,23 +61,38 @@ func executeRemoteModuleTask(task *playbookTypes.Task, play *playbookTypes.Play,
 		VarsJson:   argsJson,
 		MetaArgs:   metaArgs,
 	}
-	rsp, err := conn.RemoteExecutorClient.ExecuteModule(ctx, req)
+	rsp, err := executeModule(ctx, conn, req)
 	if err != nil {
 		return nil, err
 	}
-	display.Display(display.Options{}, "Remote module execution result: %s", rsp.ReturnValueJson)
+	display.Debug(&conn.Host.Name, "Remote module execution result: %s", rsp.ReturnValueJson)
 	var ret modules.Return
 	if err = json.Unmarshal(rsp.ReturnValueJson, &ret); err != nil {
 		return nil, err
 	}
 	if ret.InternalReturn != nil && ret.NeedsPythonRuntime && !uploadPyRuntime {
-		return executeRemoteModuleTask(task, play, conn, varsEnv, true)
+		return executeRemoteModuleTask(task, play, conn, varsEnv, interpreter, true)
 	}
-	display.Debug(&conn.Host.Name, spew.Sdump(ret))
-	// TODO do something meaningful with the execution result
 	return &ret, nil
 }
 
+// checkModuleSupported verifies that the remote executor can run the module. Modules of Ansible collections
+// are run by the Python runtime, which gets extended with the module and its dependencies.
+func checkModuleSupported(name string, conn *plugins.ConnectionContext) error {
+	if conn.Agent.HasModule(name) {
+		return nil
+	}
+	if m, ok := pythonModule.FindCollectionModule(name); ok && conn.Agent.HasFeature(remote.FeatureCollectionModules) {
+		files, err := m.RuntimeFiles()
+		if err != nil {
+			return fmt.Errorf("can't package collection module %s: %w", name, err)
+		}
+		remote.AddPythonRuntimeFiles(files)
+		return nil
+	}
+	return fmt.Errorf("module %s is not supported by the remote executor version %s on host %s", name, conn.Agent.Version, conn.Host.Name)
+}
+
 func prepareArgs(task *playbookTypes.Task, varsEnv types.Vars) (types.Vars, error) {
 	templatedArgs, err := varsPkg.TemplateActionArgs(task.Action.Args, varsEnv)
 	if err != nil {
@@ -75,10 +106,11 @@ func prepareArgs(task *playbookTypes.Task, varsEnv types.Vars) (types.Vars, erro
 	return prepared, nil
 }
 
-func prepareMetaArgs(task *playbookTypes.Task, uploadPyRuntime bool) (*pb.MetaArgs, error) {
+func prepareMetaArgs(pythonInterpreter string, uploadPyRuntime bool) (*pb.MetaArgs, error) {
 	ret := &pb.MetaArgs{}
 
-	ret.PythonInterpreter = getPythonInterpreter(task)
+	ret.PythonInterpreter = pythonInterpreter
+	ret.PyRuntimeChecksum = remote.PythonRuntimeChecksum()
 
 	if uploadPyRuntime {
 		display.Display(display.Options{}, "Uploading Python runtime to remote host")
@@ -92,7 +124,18 @@ func prepareMetaArgs(task *playbookTypes.Task, uploadPyRuntime bool) (*pb.MetaAr
 	return ret, nil
 }
 
-func getPythonInterpreter(task *playbookTypes.Task) string {
-	// TODO in some edge cases ansible uses different python executable.
-	return "/usr/bin/python3"
+// addFacts adds facts gathered by the controller to the module result.
+func addFacts(ret *modules.Return, facts types.Facts) {
+	if len(facts) == 0 {
+		return
+	}
+	if ret.InternalReturn == nil {
+		ret.InternalReturn = &modules.InternalReturn{}
+	}
+	if ret.AnsibleFacts == nil {
+		ret.AnsibleFacts = make(types.Facts)
+	}
+	for k, v := range facts {
+		ret.AnsibleFacts[k] = v
+	}
 }
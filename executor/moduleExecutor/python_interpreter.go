@@ -0,0 +1,108 @@
+package moduleExecutor
+
+import (
+	"context"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/connection"
+	"github.com/scylladb/gosible/module_utils/pythonModule"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/types"
+	"strings"
+	"time"
+)
+
+const (
+	varPythonInterpreter         = "ansible_python_interpreter"
+	varPythonInterpreterFallback = "ansible_interpreter_python_fallback"
+	// factDiscoveredInterpreter holds the result of the discovery, so that it is done once per host.
+	factDiscoveredInterpreter = "discovered_interpreter_python"
+)
+
+const discoveryTimeout = 10 * time.Second
+
+// pythonModulePrefix is the prefix of the names of the Ansible modules run by the Python runtime.
+const pythonModulePrefix = "py_"
+
+// getPythonInterpreter returns the interpreter used to run Python modules on the host. The interpreter is discovered
+// only for the modules run by the Python runtime, the native modules get the default one unless it's already known,
+// so that hosts without Python can be managed. When the interpreter was discovered, the returned facts hold the result to be
+// saved for the host.
+func getPythonInterpreter(module string, conn *plugins.ConnectionContext, varsEnv types.Vars) (string, types.Facts, error) {
+	interpreter := config.Manager().Settings.INTERPRETER_PYTHON
+	if v, ok := varsEnv[varPythonInterpreter].(string); ok && v != "" {
+		interpreter = v
+	}
+	if interpreter == "" {
+		return pythonModule.DefaultInterpreter, nil, nil
+	}
+	if !pythonModule.IsDiscoveryMode(interpreter) {
+		return interpreter, nil, nil
+	}
+	if discovered, ok := discoveredInterpreter(varsEnv); ok {
+		return discovered, nil, nil
+	}
+	if !runsInPythonRuntime(module) {
+		return pythonModule.DefaultInterpreter, nil, nil
+	}
+	if !conn.Agent.HasFeature(remote.FeaturePythonDiscovery) {
+		display.Warning(display.WarnOptions{}, "Remote executor on host %s can't discover the Python interpreter, using %s",
+			conn.Host.Name, pythonModule.DefaultInterpreter)
+		return pythonModule.DefaultInterpreter, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+	rsp, err := conn.RemoteExecutorClient.DiscoverPythonInterpreter(ctx, &pb.DiscoverPythonInterpreterRequest{
+		Mode:     interpreter,
+		Fallback: interpreterFallback(varsEnv),
+	})
+	if connection.IsUnreachable(err) {
+		return "", nil, err
+	}
+	if err != nil {
+		// Like Ansible, the default interpreter is used when the discovery fails, and it's kept for the host.
+		display.Warning(display.WarnOptions{}, "Python interpreter discovery failed on host %s, using %s: %s",
+			conn.Host.Name, pythonModule.DefaultInterpreter, err)
+		return pythonModule.DefaultInterpreter, types.Facts{factDiscoveredInterpreter: pythonModule.DefaultInterpreter}, nil
+	}
+	if !pythonModule.IsSilentDiscoveryMode(interpreter) {
+		for _, warning := range rsp.Warnings {
+			display.Warning(display.WarnOptions{}, "Host %s: %s", conn.Host.Name, warning)
+		}
+	}
+	display.Debug(&conn.Host.Name, "Discovered Python interpreter %s", rsp.Interpreter)
+	return rsp.Interpreter, types.Facts{factDiscoveredInterpreter: rsp.Interpreter}, nil
+}
+
+func runsInPythonRuntime(module string) bool {
+	return strings.HasPrefix(module, pythonModulePrefix) || pythonModule.IsCollectionFqcn(module)
+}
+
+func discoveredInterpreter(varsEnv types.Vars) (string, bool) {
+	if facts, ok := varsEnv["ansible_facts"].(types.Vars); ok {
+		if v, ok := facts[factDiscoveredInterpreter].(string); ok && v != "" {
+			return v, true
+		}
+	}
+	v, ok := varsEnv[factDiscoveredInterpreter].(string)
+	return v, ok && v != ""
+}
+
+func interpreterFallback(varsEnv types.Vars) []string {
+	switch v := varsEnv[varPythonInterpreterFallback].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		fallback := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				fallback = append(fallback, s)
+			}
+		}
+		return fallback
+	}
+	return config.Manager().Settings.INTERPRETER_PYTHON_FALLBACK
+}
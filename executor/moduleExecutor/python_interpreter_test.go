@@ -0,0 +1,82 @@
+package moduleExecutor
+
+import (
+	"context"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/module_utils/pythonModule"
+	playbookTypes "github.com/scylladb/gosible/playbook/types"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"runtime"
+	"testing"
+)
+
+// pythonlessClient is a remote executor on a host without Python: the discovery of the interpreter fails and the
+// modules succeed.
+type pythonlessClient struct {
+	pb.GosibleClientClient
+	discoveries  int
+	interpreters []string
+}
+
+func (c *pythonlessClient) DiscoverPythonInterpreter(context.Context, *pb.DiscoverPythonInterpreterRequest, ...grpc.CallOption) (*pb.DiscoverPythonInterpreterReply, error) {
+	c.discoveries++
+	return nil, status.Error(codes.Unknown, "no python interpreters found on host, set ansible_python_interpreter")
+}
+
+func (c *pythonlessClient) ExecuteModule(_ context.Context, req *pb.ExecuteModuleRequest, _ ...grpc.CallOption) (*pb.ExecuteModuleReply, error) {
+	c.interpreters = append(c.interpreters, req.MetaArgs.PythonInterpreter)
+	return &pb.ExecuteModuleReply{ReturnValueJson: []byte(`{"Msg": "ok"}`)}, nil
+}
+
+func TestExecuteWithoutPython(t *testing.T) {
+	// The modules don't stream their output at the default verbosity.
+	if err := display.Instance().SetVerbosity(display.DefaultVerbosityLevel); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		module      string
+		vars        types.Vars
+		discoveries int
+		fact        bool
+	}{
+		{"ping", types.Vars{varPythonInterpreter: "auto"}, 0, false},
+		{"setup", types.Vars{varPythonInterpreter: "auto_silent"}, 0, false},
+		{"py_apt", types.Vars{varPythonInterpreter: "auto"}, 1, true},
+		{"py_apt", types.Vars{varPythonInterpreter: "auto", factDiscoveredInterpreter: "/usr/bin/python3.9"}, 0, false},
+		{"py_apt", types.Vars{varPythonInterpreter: "/opt/python"}, 0, false},
+	}
+	for _, tc := range testCases {
+		client := &pythonlessClient{}
+		conn := &plugins.ConnectionContext{
+			RemoteExecutorClient: client,
+			Host:                 &inventory.Host{Name: "host"},
+			Agent:                remote.NewAgentInfo(runtime.GOOS, runtime.GOARCH, []string{"ping", "setup", "py_apt"}),
+		}
+		task := &playbookTypes.Task{Action: &playbookTypes.Action{Name: tc.module, Args: types.Vars{}}}
+		ret, err := ExecuteRemoteModuleTask(task, &playbookTypes.Play{}, conn, tc.vars)
+		if err != nil {
+			t.Error("on", tc.module, tc.vars, "expected the module to run, got", err)
+			continue
+		}
+		if ret.Failed || ret.Msg != "ok" {
+			t.Error("on", tc.module, tc.vars, "unexpected result", ret)
+		}
+		if client.discoveries != tc.discoveries {
+			t.Error("on", tc.module, tc.vars, "expected", tc.discoveries, "discoveries, got", client.discoveries)
+		}
+		fact := ret.InternalReturn != nil && ret.AnsibleFacts[factDiscoveredInterpreter] != nil
+		if fact != tc.fact {
+			t.Error("on", tc.module, tc.vars, "expected discovered interpreter fact", tc.fact, "got", ret.InternalReturn)
+		}
+		if tc.discoveries > 0 && client.interpreters[0] != pythonModule.DefaultInterpreter {
+			t.Error("on", tc.module, tc.vars, "expected fallback to", pythonModule.DefaultInterpreter, "got", client.interpreters)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package moduleExecutor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"github.com/scylladb/gosible/utils/display"
+	"io"
+)
+
+// streamingVerbosity is the verbosity above which the output of modules is shown while they run.
+const streamingVerbosity = 2
+
+func executeModule(ctx context.Context, conn *plugins.ConnectionContext, req *pb.ExecuteModuleRequest) (*pb.ExecuteModuleReply, error) {
+	if display.Instance().GetVerbosity() <= streamingVerbosity || !conn.Agent.HasFeature(remote.FeatureExecuteModuleStream) {
+		return conn.RemoteExecutorClient.ExecuteModule(ctx, req)
+	}
+	return executeModuleStream(ctx, conn, req)
+}
+
+func executeModuleStream(ctx context.Context, conn *plugins.ConnectionContext, req *pb.ExecuteModuleRequest) (*pb.ExecuteModuleReply, error) {
+	stream, err := conn.RemoteExecutorClient.ExecuteModuleStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &outputPrinter{host: conn.Host.Name, prefix: "stdout"}
+	stderr := &outputPrinter{host: conn.Host.Name, prefix: "stderr"}
+	defer stdout.flush()
+	defer stderr.flush()
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("module output stream ended without a result")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch e := event.Event.(type) {
+		case *pb.ExecuteModuleEvent_Output:
+			if e.Output.Stream == pb.OutputChunk_STDERR {
+				stderr.write(e.Output.Data)
+			} else {
+				stdout.write(e.Output.Data)
+			}
+		case *pb.ExecuteModuleEvent_Progress:
+			display.VVV(&conn.Host.Name, "%s: %s", req.ModuleName, e.Progress.Message)
+		case *pb.ExecuteModuleEvent_Reply:
+			return e.Reply, nil
+		}
+	}
+}
+
+// outputPrinter displays streamed output line by line.
+type outputPrinter struct {
+	host   string
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (p *outputPrinter) write(data []byte) {
+	p.buf.Write(data)
+	for {
+		idx := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if idx < 0 {
+			return
+		}
+		line := p.buf.Next(idx + 1)
+		display.VVV(&p.host, "%s: %s", p.prefix, bytes.TrimRight(line, "\r\n"))
+	}
+}
+
+func (p *outputPrinter) flush() {
+	if p.buf.Len() > 0 {
+		display.VVV(&p.host, "%s: %s", p.prefix, p.buf.String())
+		p.buf.Reset()
+	}
+}
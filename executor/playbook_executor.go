@@ -2,10 +2,14 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/connection"
 	"github.com/scylladb/gosible/executor/conn"
 	"github.com/scylladb/gosible/executor/moduleExecutor"
 	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/modules"
 	playbookTypes "github.com/scylladb/gosible/playbook/types"
 	"github.com/scylladb/gosible/plugins"
 	"github.com/scylladb/gosible/plugins/meta"
@@ -13,24 +17,44 @@ import (
 	"github.com/scylladb/gosible/utils/display"
 	"github.com/scylladb/gosible/utils/maps"
 	"github.com/scylladb/gosible/utils/parallel"
+	"github.com/scylladb/gosible/utils/slices"
 	"github.com/scylladb/gosible/utils/types"
 	varsPkg "github.com/scylladb/gosible/vars"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // TimeoutLocalTaskExecution is the timeout for task executed on the local machine.
 const TimeoutLocalTaskExecution = moduleExecutor.TimeoutRemoteTaskExecution
 
+const keywordIgnoreUnreachable = "ignore_unreachable"
+
 type playExecutor struct {
 	play               *playbookTypes.Play
 	inv                *inventory.Data
 	varsManager        *varsPkg.Manager
 	hosts              map[string]*inventory.Host
 	connectionManagers map[string]*conn.Manager
+	// unreachableHosts are shared by all plays of the playbook, unreachable hosts are excluded from subsequent plays.
+	unreachableHosts map[string]error
+	// failedHosts are shared by all plays of the playbook like unreachableHosts, they hold the hosts whose facts
+	// couldn't be gathered.
+	failedHosts map[string]error
+	onResult    ResultCallback
+	// factsTask is the task gathering the facts of the hosts before the tasks of the play, if they are gathered.
+	factsTask *playbookTypes.Task
 }
 
+// ResultCallback receives the result of a task on a host. The error is set instead of the result when the task
+// could not be executed, e.g. when the host is unreachable.
+type ResultCallback func(host *inventory.Host, result *modules.Return, err error)
+
 type tasksExecutor struct {
 	*playExecutor
 	tasks []*playbookTypes.Task
+	// hostFilter selects the hosts of the play executing the tasks, all of them execute the tasks if it is nil.
+	hostFilter func(host *inventory.Host) bool
 }
 
 type taskOnHostExecutor struct {
@@ -54,18 +78,66 @@ func ExecutePlaybook(pbook *playbookTypes.Playbook, inventory *inventory.Data, v
 	// TODO support loop_control
 	display.Display(display.Options{}, "Executing %d plays from the specified playbook", len(pbook.Plays))
 
+	unreachableHosts, failedHosts := make(map[string]error), make(map[string]error)
 	for _, play := range pbook.Plays {
 		playExecutor := &playExecutor{
-			play:        play,
-			inv:         inventory,
-			varsManager: varsManager,
+			play:             play,
+			inv:              inventory,
+			varsManager:      varsManager,
+			unreachableHosts: unreachableHosts,
+			failedHosts:      failedHosts,
 		}
 
 		if err := playExecutor.execute(passwords); err != nil {
 			return err
 		}
 	}
-	return nil
+	return reportHostErrors(failedHosts, unreachableHosts)
+}
+
+// ExecutePlay executes a single play, e.g. one built for an ad-hoc task. The result of each task on each host
+// is passed to onResult, which may be nil.
+func ExecutePlay(play *playbookTypes.Play, inventory *inventory.Data, varsManager *varsPkg.Manager, passwords types.Passwords, onResult ResultCallback) error {
+	unreachableHosts, failedHosts := make(map[string]error), make(map[string]error)
+	playExecutor := &playExecutor{
+		play:             play,
+		inv:              inventory,
+		varsManager:      varsManager,
+		unreachableHosts: unreachableHosts,
+		failedHosts:      failedHosts,
+		onResult:         onResult,
+	}
+	if err := playExecutor.execute(passwords); err != nil {
+		return err
+	}
+	return reportHostErrors(failedHosts, unreachableHosts)
+}
+
+// reportHostErrors displays the hosts removed from the plays and returns an error if there were any.
+func reportHostErrors(failedHosts, unreachableHosts map[string]error) error {
+	settings := config.Manager().Settings
+	failedErr := reportHosts(failedHosts, "Failed hosts:", "failed", settings.COLOR_ERROR)
+	unreachableErr := reportHosts(unreachableHosts, "Unreachable hosts:", "were unreachable", settings.COLOR_UNREACHABLE)
+	if failedErr != nil && unreachableErr != nil {
+		return fmt.Errorf("%s, %w", failedErr, unreachableErr)
+	}
+	if failedErr != nil {
+		return failedErr
+	}
+	return unreachableErr
+}
+
+func reportHosts(hosts map[string]error, title, verb, color string) error {
+	if len(hosts) == 0 {
+		return nil
+	}
+	names := maps.Keys(hosts)
+	sort.Strings(names)
+	display.Display(display.Options{Color: color}, title)
+	for _, name := range names {
+		display.Display(display.Options{Color: color}, "  %s: %s", name, hosts[name])
+	}
+	return fmt.Errorf("%d host(s) %s: %s", len(names), verb, strings.Join(names, ", "))
 }
 
 func (ex *playExecutor) execute(passwords types.Passwords) error {
@@ -76,6 +148,10 @@ func (ex *playExecutor) execute(passwords types.Passwords) error {
 	if err := ex.determineHosts(); err != nil {
 		return err
 	}
+	if len(ex.hosts) == 0 {
+		display.Display(display.Options{Color: config.Manager().Settings.COLOR_SKIP}, "skipping: no hosts matched")
+		return nil
+	}
 
 	err := ex.setupConnectionManagers(passwords)
 
@@ -91,6 +167,9 @@ func (ex *playExecutor) execute(passwords types.Passwords) error {
 	if err != nil {
 		return err
 	}
+	if err = ex.gatherFacts(); err != nil {
+		return err
+	}
 
 	return ex.executeStrategy()
 }
@@ -125,6 +204,10 @@ func (ex *playExecutor) executeStrategy() error {
 }
 
 func (ex *playExecutor) showPlayNameBanner() (err error) {
+	if ex.onResult != nil {
+		// The results are displayed by the callback, e.g. one line per host in ad-hoc commands.
+		return nil
+	}
 	vars, err := ex.varsManager.GetVars(ex.play, nil, nil)
 	if err != nil {
 		return err
@@ -146,6 +229,39 @@ func (ex *playExecutor) determineHosts() (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to determine hosts for play: %w", err)
 	}
+	for name := range ex.unreachableHosts {
+		delete(ex.hosts, name)
+	}
+	for name := range ex.failedHosts {
+		delete(ex.hosts, name)
+	}
+	return nil
+}
+
+// markUnreachable removes the host from the play. Remaining hosts continue the play.
+func (ex *playExecutor) markUnreachable(host *inventory.Host, err error) error {
+	if ex.onResult != nil {
+		ex.onResult(host, nil, err)
+	} else {
+		display.Display(display.Options{Color: config.Manager().Settings.COLOR_UNREACHABLE}, "fatal: [%s]: UNREACHABLE! => %s", host.Name, err)
+	}
+	ex.unreachableHosts[host.Name] = err
+	return ex.removeHost(host)
+}
+
+// markFailed removes the host, whose facts couldn't be gathered, from the play. Remaining hosts continue the play.
+func (ex *playExecutor) markFailed(host *inventory.Host, err error) error {
+	display.Display(display.Options{Color: config.Manager().Settings.COLOR_ERROR}, "fatal: [%s]: FAILED! => %s", host.Name, err)
+	ex.failedHosts[host.Name] = err
+	return ex.removeHost(host)
+}
+
+func (ex *playExecutor) removeHost(host *inventory.Host) error {
+	delete(ex.hosts, host.Name)
+	if cm, ok := ex.connectionManagers[host.Name]; ok {
+		delete(ex.connectionManagers, host.Name)
+		return cm.Close()
+	}
 	return nil
 }
 
@@ -173,8 +289,27 @@ func (ex *tasksExecutor) execute() error {
 		}
 	}
 
-	if errors := parallel.ForAll(maps.Values(ex.hosts), ex.executeTasksOnHost); errors.IsError() {
-		return errors.Combine()
+	hosts := maps.Values(ex.hosts)
+	if ex.hostFilter != nil {
+		hosts = slices.Filter(hosts, ex.hostFilter)
+	}
+	errs := parallel.ForAll(hosts, ex.executeTasksOnHost)
+	var gatherErr *factsGatheringError
+	for i, err := range errs {
+		if connection.IsUnreachable(err) {
+			if closeErr := ex.markUnreachable(hosts[i], err); closeErr != nil {
+				display.Debug(&hosts[i].Name, "Error while closing connection to unreachable host: %s", closeErr)
+			}
+			errs[i] = nil
+		} else if errors.As(err, &gatherErr) {
+			if closeErr := ex.markFailed(hosts[i], err); closeErr != nil {
+				display.Debug(&hosts[i].Name, "Error while closing connection to failed host: %s", closeErr)
+			}
+			errs[i] = nil
+		}
+	}
+	if errs.IsError() {
+		return errs.Combine()
 	}
 	return nil
 }
@@ -194,12 +329,27 @@ func (ex *tasksExecutor) executeTasksOnHost(host *inventory.Host) error {
 			connectionManager: ex.connectionManagers[host.Name],
 		}
 		if err := taskInstance.execute(); err != nil {
+			if connection.IsUnreachable(err) && ignoresUnreachable(t) {
+				display.Warning(display.WarnOptions{}, "Host %s is unreachable, ignoring as requested by ignore_unreachable: %s", host.Name, err)
+				continue
+			}
 			return fmt.Errorf("on task %s, %w", t.Name, err)
 		}
 	}
 	return nil
 }
 
+func ignoresUnreachable(task *playbookTypes.Task) bool {
+	switch v := task.Keywords[keywordIgnoreUnreachable].(type) {
+	case bool:
+		return v
+	case string:
+		b, err := strconv.ParseBool(v)
+		return err == nil && b
+	}
+	return false
+}
+
 func (ex *taskOnHostExecutor) execute() error {
 	if err := ex.showTaskNameBanner(); err != nil {
 		return err
@@ -223,6 +373,9 @@ func (ex *taskOnHostExecutor) execute() error {
 }
 
 func (ex *taskOnHostExecutor) showTaskNameBanner() (err error) {
+	if ex.onResult != nil {
+		return nil
+	}
 	vars, err := ex.varsManager.GetVars(ex.play, nil, ex.task)
 	if err != nil {
 		return err
@@ -288,35 +441,54 @@ func (ex *taskOnHostExecutor) executeActionIfWhenSatisfied() error {
 }
 
 func (ex *taskOnHostExecutor) executeAction(varsEnv types.Vars) error {
+	var res *modules.Return
+	var err error
 	if action, ok := plugins.FindAction(ex.task.Action.Name); ok {
 		// Execute plugin if one exists for this action.
-		templatedArgs, err := varsPkg.TemplateActionArgs(ex.task.Action.Args, varsEnv)
-		if err != nil {
-			return err
+		templatedArgs, templateErr := varsPkg.TemplateActionArgs(ex.task.Action.Args, varsEnv)
+		if templateErr != nil {
+			return templateErr
 		}
 		ctx := plugins.CreateActionContext(ex.connection, templatedArgs, varsEnv)
-		res, err := executePluginAction(action, &ctx)
-		if err == nil && res.InternalReturn != nil {
-			ex.varsManager.SaveFacts(res.FactBucket, res.AnsibleFacts, ex.host)
-		}
+		res, err = executePluginAction(action, &ctx)
 	} else {
 		// Otherwise, try executing the action as a module.
-		res, err := moduleExecutor.ExecuteRemoteModuleTask(ex.task, ex.play, ex.connection, varsEnv)
-		if err == nil && res.InternalReturn != nil {
-			ex.varsManager.SaveFacts(res.FactBucket, res.AnsibleFacts, ex.host)
+		res, err = moduleExecutor.ExecuteRemoteModuleTask(ex.task, ex.play, ex.connection, varsEnv)
+	}
+	if connection.IsUnreachable(err) {
+		return err
+	}
+	if err == nil && res.InternalReturn != nil {
+		ex.varsManager.SaveFacts(res.FactBucket, res.AnsibleFacts, ex.host)
+	}
+	ex.reportResult(res, err)
+	if ex.task == ex.factsTask {
+		if err != nil || res.Failed {
+			return newFactsGatheringError(res, err)
 		}
+		ex.varsManager.MarkFactsGathered(ex.host)
 	}
 	// TODO do something meaningful with the execution result (in particular, support register)
 
 	return nil
 }
 
+func (ex *taskOnHostExecutor) reportResult(res *modules.Return, err error) {
+	if ex.onResult != nil {
+		ex.onResult(ex.host, res, err)
+	}
+}
+
 func executePluginAction(action plugins.Action, actionCtx *plugins.ActionContext) (*plugins.Return, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), TimeoutLocalTaskExecution)
 	defer cancel()
 	rsp := action.Run(ctx, actionCtx)
 
-	display.Display(display.Options{}, "Plugin execution result msg: %s", rsp.Msg)
+	display.Debug(&actionCtx.Connection.Host.Name, "Plugin execution result msg: %s", rsp.Msg)
 
+	// Failures of the connection to the remote executor make the host unreachable, like in the modules.
+	if failed, ok := action.(plugins.FailedAction); ok && connection.IsUnreachable(failed.Err()) {
+		return rsp, failed.Err()
+	}
 	return rsp, nil
 }
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/modules"
+	playbookTypes "github.com/scylladb/gosible/playbook/types"
+	varsPkg "github.com/scylladb/gosible/vars"
+	"io"
+	"os"
+	"testing"
+)
+
+// captureStdout returns what fn writes to the standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	_ = w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestBannersSilencedWithResultCallback(t *testing.T) {
+	play := &playbookTypes.Play{Name: "Ad-hoc"}
+	task := &playbookTypes.Task{Name: "ping"}
+	host := &inventory.Host{Name: "h1"}
+	varsManager := varsPkg.MakeManager(&inventory.Data{})
+	onResult := func(*inventory.Host, *modules.Return, error) {}
+
+	testCases := []struct {
+		onResult ResultCallback
+		expected string
+	}{
+		{nil, "Executing play 'Ad-hoc'\nExecuting task 'ping' on host 'h1'\n"},
+		{onResult, ""},
+	}
+	for _, tc := range testCases {
+		ex := &taskOnHostExecutor{
+			tasksExecutor: &tasksExecutor{playExecutor: &playExecutor{play: play, varsManager: varsManager, onResult: tc.onResult}},
+			host:          host,
+			task:          task,
+		}
+		out := captureStdout(t, func() {
+			if err := ex.showPlayNameBanner(); err != nil {
+				t.Error(err)
+			}
+			if err := ex.showTaskNameBanner(); err != nil {
+				t.Error(err)
+			}
+		})
+		if out != tc.expected {
+			t.Error("on callback", tc.onResult != nil, "expected", tc.expected, "got", out)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package inventory
 import (
 	"github.com/scylladb/gosible/utils/types"
 	"gopkg.in/errgo.v2/fmt/errors"
-	"strings"
 )
 
 func newData() *Data {
@@ -61,6 +60,9 @@ func (d *Data) addUngroupedGroup() {
 	all := d.groupByName("all")
 
 	ungrouped.Hosts = append(ungrouped.Hosts, all.Hosts...)
+	for _, host := range all.Hosts {
+		host.Groups = append(host.Groups, ungrouped)
+	}
 	all.Hosts = make([]*Host, 0)
 }
 
@@ -120,6 +122,72 @@ func circularReference(parents []*Group, group *Group, maxDepth int) bool {
 	return false
 }
 
+// merge adds the hosts and groups of the other inventory, parsed from another source. Vars of the hosts and groups
+// defined in both are merged, with the other inventory taking precedence.
+func (d *Data) merge(other *Data, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, host := range orderHosts(other.Hosts) {
+		d.addVarsToHost(host.Name, host.Vars)
+	}
+	for name, group := range other.Groups {
+		g := d.groupByName(name)
+		g.initialized = true
+		mergeVars(g.Vars, group.Vars)
+		for _, host := range group.Hosts {
+			g.addHost(d.Hosts[host.Name])
+		}
+		for _, child := range group.Children {
+			g.addChild(d.groupByName(child.Name))
+		}
+	}
+	return nil
+}
+
+// reconcile removes the hosts that belong to other groups from the ungrouped group,
+// which may happen when the hosts are defined in many sources.
+func (d *Data) reconcile() {
+	ungrouped, ok := d.Groups["ungrouped"]
+	if !ok {
+		return
+	}
+	hosts := make([]*Host, 0, len(ungrouped.Hosts))
+	for _, host := range ungrouped.Hosts {
+		if len(host.Groups) == 1 {
+			hosts = append(hosts, host)
+			continue
+		}
+		for i, group := range host.Groups {
+			if group == ungrouped {
+				host.Groups = append(host.Groups[:i], host.Groups[i+1:]...)
+				break
+			}
+		}
+	}
+	ungrouped.Hosts = hosts
+}
+
+func (g *Group) addHost(host *Host) {
+	for _, h := range g.Hosts {
+		if h == host {
+			return
+		}
+	}
+	g.Hosts = append(g.Hosts, host)
+	host.Groups = append(host.Groups, g)
+}
+
+func (g *Group) addChild(child *Group) {
+	for _, c := range g.Children {
+		if c == child {
+			return
+		}
+	}
+	g.Children = append(g.Children, child)
+	child.Parents = append(child.Parents, g)
+}
+
 func (d *Data) groupByName(name string) *Group {
 	group, ok := d.Groups[name]
 	if !ok {
@@ -141,6 +209,7 @@ func (d *Data) hostByName(name string) *Host {
 	host, ok := d.Hosts[name]
 	if !ok {
 		host = newHost(name)
+		host.order = len(d.Hosts)
 		d.Hosts[name] = host
 	}
 	return host
@@ -166,112 +235,3 @@ func deduplicate(array []*Group) []*Group {
 
 	return ret
 }
-
-// determineHostsFromGroupsPattern takes a multi groups pattern describing a hosts list
-// and returns a list of hosts that match the pattern.
-// example pattern: "webservers:dbservers:&staging:!phoenix"
-func (d *Data) determineHostsFromGroupsPattern(groupsPattern string) (map[string]*Host, error) {
-	hosts := make(map[string]*Host)
-
-	// Multiple groups
-	// webservers:dbservers
-	// all hosts in webservers plus all hosts in dbservers
-	//
-	// Excluding groups
-	// webservers:!atlanta
-	// all hosts in webservers except those in atlanta
-	//
-	// Intersection of groups
-	// webservers:&staging
-	// any hosts in webservers that are also in staging
-
-	maybeGroupsList := strings.Split(groupsPattern, ":")
-	for _, groupName := range maybeGroupsList {
-		excludeGroup, intersectWithGroup := false, false
-		if groupName == "" {
-			return nil, errors.Newf("group `%s` does not exist", groupName)
-		} else if groupName[0] == '!' {
-			excludeGroup = true
-			groupName = groupName[1:]
-		} else if groupName[0] == '&' {
-			intersectWithGroup = true
-			groupName = groupName[1:]
-		}
-
-		if group, ok := d.Groups[groupName]; ok {
-			groupHosts := group.GetHosts()
-
-			if excludeGroup {
-				for hostName := range groupHosts {
-					delete(hosts, hostName)
-				}
-			} else if intersectWithGroup {
-				hostsToDelete := make([]string, 0)
-				for hostName, _ := range hosts {
-					if _, ok := groupHosts[hostName]; !ok {
-						hostsToDelete = append(hostsToDelete, hostName)
-					}
-				}
-				for _, hostName := range hostsToDelete {
-					delete(hosts, hostName)
-				}
-			} else {
-				for hostName, host := range groupHosts {
-					hosts[hostName] = host
-				}
-			}
-		} else {
-			return nil, errors.Newf("group `%s` does not exist", groupName)
-		}
-	}
-
-	return hosts, nil
-}
-
-// DetermineHosts takes a pattern describing a hosts list (value of hosts property in playbook)
-// and returns a list of hosts that match the pattern.
-func (d *Data) DetermineHosts(pattern string) (map[string]*Host, error) {
-	// Simplified version of https://docs.ansible.com/ansible/latest/user_guide/intro_patterns.html#common-patterns
-
-	pattern = strings.TrimSpace(pattern)
-
-	hosts := make(map[string]*Host)
-
-	if pattern == "" {
-		return hosts, nil
-	}
-	if pattern == "all" {
-		return d.Hosts, nil
-	}
-
-	hasCommas := strings.Contains(pattern, ",")
-	hasColons := strings.Contains(pattern, ":")
-
-	// FIXME Doesn't work for literal ipv6 addresses
-
-	if !hasCommas && !hasColons {
-		// No commas or colons, so this is a single host or group
-		if d.Hosts[pattern] != nil {
-			hosts[pattern] = d.Hosts[pattern]
-		} else if d.Groups[pattern] != nil {
-			hosts = d.Groups[pattern].GetHosts()
-		} else {
-			return nil, errors.Newf("host or group `%s` does not exist", pattern)
-		}
-	} else if hasCommas {
-		maybeHostsList := strings.Split(pattern, ",")
-		for _, hostName := range maybeHostsList {
-			if host, ok := d.Hosts[hostName]; ok {
-				hosts[hostName] = host
-			} else {
-				return nil, errors.Newf("host `%s` does not exist", hostName)
-			}
-		}
-	} else {
-		// FIXME this parser (for simplicity) only allows ',' as hosts separator;
-		// FIXME should be able to use ':' as host separator too
-		return d.determineHostsFromGroupsPattern(pattern)
-	}
-
-	return hosts, nil
-}
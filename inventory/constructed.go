@@ -0,0 +1,339 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/template"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/types"
+	"gopkg.in/yaml.v2"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// constructedPlugin builds vars and groups of the hosts already in the inventory from Jinja2 expressions over their
+// vars, see https://docs.ansible.com/ansible/latest/collections/ansible/builtin/constructed_inventory.html.
+// The configuration file should come after the sources defining the hosts, e.g. be the last file of a directory.
+//
+//	plugin: constructed
+//	strict: false
+//	compose:
+//	  ansible_port: port | default(22)
+//	groups:
+//	  webservers: "'web' in inventory_hostname"
+//	keyed_groups:
+//	  - key: location.datacenter
+//	    prefix: dc
+//	  - key: location.rack
+//	    prefix: rack
+//	    parent_group: "dc_{{ location.datacenter }}"
+type constructedPlugin struct{}
+
+const constructedName = "constructed"
+
+type constructedConfig struct {
+	Plugin           string        `yaml:"plugin"`
+	Strict           bool          `yaml:"strict"`
+	Compose          yaml.MapSlice `yaml:"compose"`
+	Groups           yaml.MapSlice `yaml:"groups"`
+	KeyedGroups      []keyedGroup  `yaml:"keyed_groups"`
+	LeadingSeparator *bool         `yaml:"leading_separator"`
+}
+
+type keyedGroup struct {
+	Key               string  `yaml:"key"`
+	Prefix            string  `yaml:"prefix"`
+	Separator         *string `yaml:"separator"`
+	ParentGroup       string  `yaml:"parent_group"`
+	DefaultValue      *string `yaml:"default_value"`
+	TrailingSeparator *bool   `yaml:"trailing_separator"`
+}
+
+func (constructedPlugin) VerifyFile(source string) bool {
+	extensions := append([]string{".config"}, config.Manager().Settings.YAML_FILENAME_EXTENSIONS...)
+	return isFile(source) && (filepath.Ext(source) == "" || hasExtension(source, extensions))
+}
+
+func (constructedPlugin) Parse(inventory *Data, source string) error {
+	var cfg constructedConfig
+	if err := readPluginConfig(source, constructedName, &cfg); err != nil {
+		return err
+	}
+	for _, host := range orderHosts(inventory.Hosts) {
+		if err := cfg.construct(inventory, host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cfg *constructedConfig) construct(inventory *Data, host *Host) error {
+	if err := cfg.setComposedVars(inventory, host); err != nil {
+		return err
+	}
+	// Vars are fetched again, as composed vars may be used in expressions of groups.
+	vars := constructedVars(inventory, host)
+	if err := cfg.addToComposedGroups(inventory, host, vars); err != nil {
+		return err
+	}
+	return cfg.addToKeyedGroups(inventory, host, vars)
+}
+
+func (cfg *constructedConfig) setComposedVars(inventory *Data, host *Host) error {
+	vars := constructedVars(inventory, host)
+	for _, item := range cfg.Compose {
+		name := fmt.Sprint(item.Key)
+		value, err := compose(fmt.Sprint(item.Value), vars)
+		if err != nil {
+			if cfg.Strict {
+				return fmt.Errorf("could not set %s for host %s: %w", name, host.Name, err)
+			}
+			continue
+		}
+		host.Vars[name] = value
+	}
+	return nil
+}
+
+func (cfg *constructedConfig) addToComposedGroups(inventory *Data, host *Host, vars types.Vars) error {
+	for _, item := range cfg.Groups {
+		name := toSafeGroupName(fmt.Sprint(item.Key))
+		conditional := fmt.Sprintf("{%% if %v %%} True {%% else %%} False {%% endif %%}", item.Value)
+		result, err := template.Template(conditional, vars, constructedTemplateOptions())
+		if err != nil {
+			if cfg.Strict {
+				return fmt.Errorf("could not add host %s to group %s: %w", host.Name, name, err)
+			}
+			continue
+		}
+		if strings.TrimSpace(fmt.Sprint(result)) == "True" {
+			inventory.constructedGroup(name).addHost(host)
+		}
+	}
+	return nil
+}
+
+func (cfg *constructedConfig) addToKeyedGroups(inventory *Data, host *Host, vars types.Vars) error {
+	for _, keyed := range cfg.KeyedGroups {
+		if keyed.TrailingSeparator != nil && keyed.DefaultValue != nil {
+			return errors.New("parameters are mutually exclusive for keyed groups: default_value|trailing_separator")
+		}
+		key, err := compose(keyed.Key, vars)
+		if err != nil {
+			if cfg.Strict {
+				return fmt.Errorf("could not generate group for host %s from %s entry: %w", host.Name, keyed.Key, err)
+			}
+			continue
+		}
+		names, err := keyed.groupNames(key)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			if cfg.Strict && !isEmptyCollection(key) {
+				return fmt.Errorf("no key or key resulted empty for %s in host %s, invalid entry", keyed.Key, host.Name)
+			}
+			continue
+		}
+
+		var parent *Group
+		if keyed.ParentGroup != "" {
+			parentName, err := template.TemplateToString(keyed.ParentGroup, vars, constructedTemplateOptions())
+			if err != nil {
+				return fmt.Errorf("could not template parent group %s for host %s: %w", keyed.ParentGroup, host.Name, err)
+			}
+			parent = inventory.constructedGroup(toSafeGroupName(parentName.(string)))
+		}
+		sep := keyed.separator()
+		if keyed.Prefix == "" && cfg.LeadingSeparator != nil && !*cfg.LeadingSeparator {
+			sep = ""
+		}
+		for _, name := range names {
+			group := inventory.constructedGroup(toSafeGroupName(keyed.Prefix + sep + name))
+			group.addHost(host)
+			if parent != nil {
+				parent.addChild(group)
+			}
+		}
+	}
+	return nil
+}
+
+// groupNames returns the names of the groups for the value of the key, without the prefix. A string gives a group,
+// a list gives a group per item and a dictionary gives a group per item named <key><separator><value>.
+// Empty values are replaced by the default value if there is one.
+func (keyed *keyedGroup) groupNames(key interface{}) ([]string, error) {
+	withDefault := func(name string) string {
+		if name == "" && keyed.DefaultValue != nil {
+			return *keyed.DefaultValue
+		}
+		return name
+	}
+
+	var names []string
+	switch k := key.(type) {
+	case nil:
+	case string:
+		if k != "" || keyed.DefaultValue != nil {
+			names = append(names, withDefault(k))
+		}
+	case []interface{}:
+		for _, item := range k {
+			names = append(names, withDefault(fmt.Sprint(item)))
+		}
+	case map[interface{}]interface{}, map[string]interface{}, types.Vars:
+		dict := toStringMap(k)
+		keys := make([]string, 0, len(dict))
+		for name := range dict {
+			keys = append(keys, name)
+		}
+		sort.Strings(keys)
+		for _, name := range keys {
+			value := fmt.Sprint(dict[name])
+			if value == "" && keyed.DefaultValue == nil && keyed.TrailingSeparator != nil && !*keyed.TrailingSeparator {
+				names = append(names, name)
+			} else {
+				names = append(names, name+keyed.separator()+withDefault(value))
+			}
+		}
+	case bool:
+		// Rendered like by Python.
+		names = append(names, map[bool]string{true: "True", false: "False"}[k])
+	default:
+		if isNumericValue(k) {
+			names = append(names, fmt.Sprint(k))
+		} else {
+			return nil, fmt.Errorf("invalid group name format, expected a string or a list of them or dictionary, got: %T", key)
+		}
+	}
+	return names, nil
+}
+
+func (keyed *keyedGroup) separator() string {
+	if keyed.Separator == nil {
+		return "_"
+	}
+	return *keyed.Separator
+}
+
+// constructedGroup returns the group, creating it if it does not exist.
+func (d *Data) constructedGroup(name string) *Group {
+	group := d.groupByName(name)
+	group.initialized = true
+	return group
+}
+
+// constructedVars returns the vars available in the expressions for the host: the vars of its groups and its own,
+// with the inventory_hostname, inventory_hostname_short and group_names magic vars.
+func constructedVars(inventory *Data, host *Host) types.Vars {
+	vars := make(types.Vars)
+	if all, ok := inventory.Groups["all"]; ok {
+		mergeVars(vars, all.Vars)
+	}
+	groupNames := make([]string, 0)
+	for _, group := range host.GetGroups() {
+		mergeVars(vars, group.Vars)
+		groupNames = append(groupNames, group.Name)
+	}
+	mergeVars(vars, host.Vars)
+	sort.Strings(groupNames)
+	vars["inventory_hostname"] = host.Name
+	vars["inventory_hostname_short"] = strings.Split(host.Name, ".")[0]
+	vars["group_names"] = groupNames
+	return vars
+}
+
+var varPathRegexp = regexp.MustCompile(`^\s*([A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*)\s*$`)
+
+// compose evaluates the Jinja2 expression with the vars. Expressions referring to a variable, or to a key of
+// a dictionary variable, give its value as is, so that lists and dictionaries keep their types.
+func compose(expression string, vars types.Vars) (interface{}, error) {
+	if m := varPathRegexp.FindStringSubmatch(expression); m != nil {
+		if value, ok := lookupVarPath(vars, strings.Split(m[1], ".")); ok {
+			return value, nil
+		}
+	}
+	return template.Template("{{"+expression+"}}", vars, constructedTemplateOptions())
+}
+
+func lookupVarPath(vars types.Vars, path []string) (interface{}, bool) {
+	value, ok := vars[path[0]]
+	for _, key := range path[1:] {
+		if !ok {
+			return nil, false
+		}
+		dict := toStringMap(value)
+		value, ok = dict[key]
+	}
+	return value, ok
+}
+
+func constructedTemplateOptions() *template.Options {
+	return template.NewOptions().SetDisableLookups(true).SetFailOnUndefined(true)
+}
+
+var invalidGroupChars = regexp.MustCompile(`^[\d\W]|[^\w]`)
+
+// toSafeGroupName replaces the characters which are invalid in variable names with underscores,
+// as configured by TRANSFORM_INVALID_GROUP_CHARS.
+func toSafeGroupName(name string) string {
+	if !invalidGroupChars.MatchString(name) {
+		return name
+	}
+	switch config.Manager().Settings.TRANSFORM_INVALID_GROUP_CHARS {
+	case "always":
+		display.Warning(display.WarnOptions{}, "invalid characters were found in group name %s and replaced", name)
+		return invalidGroupChars.ReplaceAllString(name, "_")
+	case "silently":
+		return invalidGroupChars.ReplaceAllString(name, "_")
+	case "ignore":
+		return name
+	default:
+		display.Warning(display.WarnOptions{}, "invalid characters were found in group name %s but not replaced", name)
+		return name
+	}
+}
+
+func toStringMap(v interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	switch m := v.(type) {
+	case map[interface{}]interface{}:
+		for k, value := range m {
+			result[fmt.Sprint(k)] = value
+		}
+	case map[string]interface{}:
+		for k, value := range m {
+			result[k] = value
+		}
+	case types.Vars:
+		for k, value := range m {
+			result[k] = value
+		}
+	}
+	return result
+}
+
+func isEmptyCollection(v interface{}) bool {
+	switch c := v.(type) {
+	case []interface{}:
+		return len(c) == 0
+	case map[interface{}]interface{}:
+		return len(c) == 0
+	case map[string]interface{}:
+		return len(c) == 0
+	case types.Vars:
+		return len(c) == 0
+	}
+	return false
+}
+
+func isNumericValue(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
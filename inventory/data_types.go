@@ -1,11 +1,17 @@
 package inventory
 
-import "github.com/scylladb/gosible/utils/types"
+import (
+	"github.com/scylladb/gosible/utils/types"
+	"sort"
+	"strconv"
+	"sync"
+)
 
 type Host struct {
 	Name   string
 	Vars   types.Vars
 	Groups []*Group
+	order  int // position in the inventory, matched hosts are returned in this order
 }
 
 type Group struct {
@@ -18,8 +24,13 @@ type Group struct {
 }
 
 type Data struct {
-	Hosts  map[string]*Host
-	Groups map[string]*Group
+	Hosts   map[string]*Host
+	Groups  map[string]*Group
+	Sources []string // paths the inventory was parsed from
+	limit   []string // patterns set by Limit
+	// lock guards the hosts and groups replaced by Refresh against the host matching of concurrent plays.
+	// The vars manager reads the hosts under its own lock, which it also holds while refreshing.
+	lock sync.RWMutex
 }
 
 // AllVars returns all variables that host have either from itself or its groups.
@@ -40,6 +51,66 @@ func (g *Group) AllVars() types.Vars {
 	return mergeVars(vars, g.Vars)
 }
 
+// GetGroups returns the groups of the host and their ancestors, except the `all` group. Groups are sorted by depth,
+// ansible_group_priority and name, which is the order in which their vars are merged.
+func (h *Host) GetGroups() []*Group {
+	seen := make(map[*Group]bool)
+	var groups []*Group
+	var visit func([]*Group)
+	visit = func(gs []*Group) {
+		for _, g := range gs {
+			if !seen[g] {
+				seen[g] = true
+				if g.Name != "all" {
+					groups = append(groups, g)
+				}
+				visit(g.Parents)
+			}
+		}
+	}
+	visit(h.Groups)
+
+	depths := make(map[*Group]int, len(groups))
+	for _, g := range groups {
+		depths[g] = g.depth()
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		gi, gj := groups[i], groups[j]
+		if depths[gi] != depths[gj] {
+			return depths[gi] < depths[gj]
+		}
+		if gi.priority() != gj.priority() {
+			return gi.priority() < gj.priority()
+		}
+		return gi.Name < gj.Name
+	})
+	return groups
+}
+
+// depth returns the length of the longest path from the group to a top level group.
+func (g *Group) depth() int {
+	d := 0
+	for _, parent := range g.Parents {
+		if pd := parent.depth() + 1; pd > d {
+			d = pd
+		}
+	}
+	return d
+}
+
+// priority returns the ansible_group_priority of the group, which orders the groups of the same depth.
+func (g *Group) priority() int {
+	switch p := g.Vars["ansible_group_priority"].(type) {
+	case int:
+		return p
+	case string:
+		if i, err := strconv.Atoi(p); err == nil {
+			return i
+		}
+	}
+	return 1
+}
+
 // GetHosts returns all host that are in the group or its children.
 func (g *Group) GetHosts() map[string]*Host {
 	hosts := make(map[string]*Host)
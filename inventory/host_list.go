@@ -0,0 +1,43 @@
+package inventory
+
+import (
+	"os"
+	"strings"
+)
+
+// hostListPlugin parses a comma separated list of hosts, e.g. "host1,host2:2222,", which are added to the ungrouped
+// group. Hosts may be followed by a port.
+type hostListPlugin struct{}
+
+func (hostListPlugin) VerifyFile(source string) bool {
+	_, err := os.Stat(source)
+	return err != nil && strings.Contains(source, ",")
+}
+
+func (hostListPlugin) Parse(inventory *Data, source string) error {
+	return inventory.merge(parseHostList(source))
+}
+
+func parseHostList(list string) (*Data, error) {
+	data := newData()
+	all := data.groupByName("all")
+	all.initialized = true
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		hostName, port, err := splitPort(name)
+		if err != nil {
+			hostName, port = name, 0
+		}
+		host := data.hostByName(hostName)
+		if port != 0 {
+			host.Vars["ansible_port"] = port
+		}
+		all.Hosts = append(all.Hosts, host)
+	}
+	if err := data.formatAndValidate(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
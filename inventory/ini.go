@@ -2,12 +2,14 @@ package inventory
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
+	"github.com/scylladb/gosible/parsing"
 	"github.com/scylladb/gosible/utils/types"
-	"os"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 type sectionType int
@@ -24,16 +26,28 @@ type stateStruct struct {
 	typ   sectionType
 }
 
-func parseIni(filename string) (*Data, error) {
-	file, err := os.Open(filename)
+// iniPlugin parses INI inventory files.
+type iniPlugin struct{}
+
+func (iniPlugin) VerifyFile(source string) bool {
+	return isFile(source)
+}
+
+func (iniPlugin) Parse(inventory *Data, source string) error {
+	dat, err := readSourceFile(source)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer file.Close()
+	if bytes.HasPrefix(dat, []byte("#!")) {
+		return fmt.Errorf("the file %s looks like an executable inventory script, but is not marked executable, perhaps you want to correct this with `chmod +x %s`", source, source)
+	}
+	return inventory.merge(parseIni(dat))
+}
 
+func parseIni(dat []byte) (*Data, error) {
 	state := newState()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(dat))
 	for scanner.Scan() {
 		err := state.handleLine(scanner.Text())
 		if err != nil {
@@ -41,8 +55,7 @@ func parseIni(filename string) (*Data, error) {
 		}
 	}
 
-	err = state.data.formatAndValidate()
-	if err != nil {
+	if err := state.data.formatAndValidate(); err != nil {
 		return nil, err
 	}
 	return state.data, nil
@@ -62,7 +75,7 @@ func newState() *stateStruct {
 
 func (state *stateStruct) handleLine(line string) error {
 	trimmed := strings.TrimSpace(line)
-	if trimmed == "" {
+	if trimmed == "" || isComment(trimmed) {
 		return nil
 	}
 	if isSection(trimmed) {
@@ -72,8 +85,25 @@ func (state *stateStruct) handleLine(line string) error {
 	return state.handleDataLine(trimmed)
 }
 
+func isComment(line string) bool {
+	return line[0] == '#' || line[0] == ';'
+}
+
+// removeComment removes a comment started by '#' at the beginning of a word outside of quotes.
 func removeComment(line string) string {
-	// TODO
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || unicode.IsSpace(rune(line[i-1]))):
+			return strings.TrimSpace(line[:i])
+		}
+	}
 	return line
 }
 
@@ -90,13 +120,15 @@ func (state *stateStruct) handleSectionLine(line string) {
 func (state *stateStruct) handleDataLine(line string) error {
 	switch state.typ {
 	case section:
-		name, vars, err := getHostData(line)
+		names, vars, err := getHostData(line)
 		if err != nil {
 			return err
 		}
-		host := state.data.addVarsToHost(name, vars)
-		host.Groups = append(host.Groups, state.group)
-		state.group.Hosts = append(state.group.Hosts, host)
+		for _, name := range names {
+			host := state.data.addVarsToHost(name, vars)
+			host.Groups = append(host.Groups, state.group)
+			state.group.Hosts = append(state.group.Hosts, host)
+		}
 	case vars:
 		name, value, err := getVar(line)
 		if err != nil {
@@ -119,60 +151,73 @@ func (state *stateStruct) handleDataLine(line string) error {
 
 const nameRegexStr = `((?:\w|-|_)+)`
 
-const dataRegexStr = nameRegexStr + `=('.+'|".+"|\S+)`
-
-var dataRegex = regexp.MustCompile(dataRegexStr)
-
-var hostRegexStr = fmt.Sprintf(`^%s(\s+%s)*$`, nameRegexStr, dataRegexStr)
-var hostRegex = regexp.MustCompile(hostRegexStr)
-var nameRegex = regexp.MustCompile(nameRegexStr)
 var nameLineRegex = regexp.MustCompile("^" + nameRegexStr + "$")
 
-var sectionRegex = regexp.MustCompile(`^\[\S+(?::(?:vars|children))?]$`)
+var sectionRegex = regexp.MustCompile(`^\[([^:\]\s]+)(?::(vars|children))?]\s*(?:#.*)?$`)
 
 func getChild(line string) (string, error) {
-	match := nameLineRegex.FindString(line)
+	match := nameLineRegex.FindString(removeComment(line))
 	if match == "" {
 		return "", errors.New("unexpected child line format")
 	}
 	return match, nil
 }
 
-func getVar(line string) (string, string, error) {
-	unexpectedFormatErr := errors.New("unexpected Vars line format")
-	if !dataRegex.MatchString(line) {
-		return "", "", unexpectedFormatErr
-	}
+func getVar(line string) (string, interface{}, error) {
 	split := strings.SplitN(line, "=", 2)
-	return split[0], split[1], nil
+	if len(split) != 2 || strings.TrimSpace(split[0]) == "" {
+		return "", nil, fmt.Errorf("expected key=value, got: %s", line)
+	}
+	return strings.TrimSpace(split[0]), parseValue(strings.TrimSpace(split[1])), nil
 }
 
-func getHostData(line string) (string, types.Vars, error) {
-	if !hostRegex.MatchString(line) {
-		return "", nil, errors.New("unexpected host line format")
+// getHostData parses a host line, i.e. a host pattern followed by key=value variables.
+func getHostData(line string) ([]string, types.Vars, error) {
+	line = removeComment(line)
+	pattern := strings.Fields(line)[0]
+	names, port, err := expandHostPattern(pattern)
+	if err != nil {
+		return nil, nil, err
 	}
+
 	var vars = make(types.Vars)
-	for _, variable := range dataRegex.FindAllString(line, -1) {
-		split := strings.SplitN(variable, "=", 2)
-		vars[split[0]] = split[1]
+	if rest := strings.TrimSpace(line[len(pattern):]); rest != "" {
+		pairs := parsing.ParseKeyValuePairsString(rest, false)
+		if pairs == nil {
+			return nil, nil, fmt.Errorf("unable to parse host variables: %s", rest)
+		}
+		if raw, ok := pairs["_raw_params"]; ok {
+			return nil, nil, fmt.Errorf("expected key=value host variable assignment, got: %s", raw)
+		}
+		for k, v := range pairs {
+			vars[k] = parseValue(v)
+		}
+	}
+	if _, ok := vars["ansible_port"]; !ok && port != 0 {
+		vars["ansible_port"] = port
+	}
+	return names, vars, nil
+}
+
+// parseValue types a value like Ansible's INI inventory does, interpreting it as a Python literal if possible,
+// e.g. 42, True, [1, 2] or 'quoted'. Other values are kept as strings.
+func parseValue(v string) interface{} {
+	if value, err := parsing.ParseLiteral(v); err == nil {
+		return value
 	}
-	name := nameRegex.FindAllString(line, 1)[0]
-	return name, vars, nil
+	return v
 }
 
 func getSectionData(trimmed string) (string, sectionType) {
-	noBrackets := strings.Trim(trimmed, "[]")
-	split := strings.Split(noBrackets, ":")
+	m := sectionRegex.FindStringSubmatch(trimmed)
 	typ := section
-	if len(split) == 2 {
-		switch split[1] {
-		case "vars":
-			typ = vars
-		case "children":
-			typ = children
-		}
+	switch m[2] {
+	case "vars":
+		typ = vars
+	case "children":
+		typ = children
 	}
-	return split[0], typ
+	return m[1], typ
 }
 
 func isSection(line string) bool {
@@ -1,8 +1,12 @@
 package inventory
 
 import (
+	"fmt"
+	"github.com/scylladb/gosible/config"
 	"github.com/scylladb/gosible/utils/maps"
 	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
@@ -48,7 +52,7 @@ func hostMapEqual(h1 map[string]*Host, h2 map[string]*Host) bool {
 		return false
 	}
 	for k, v := range h1 {
-		v2, ok := h1[k]
+		v2, ok := h2[k]
 		if !ok || !hostEqual(v, v2) {
 			return false
 		}
@@ -57,7 +61,7 @@ func hostMapEqual(h1 map[string]*Host, h2 map[string]*Host) bool {
 }
 
 func hostNames(hosts []*Host) []string {
-	names := make([]string, len(hosts))
+	names := make([]string, 0, len(hosts))
 	for _, host := range hosts {
 		names = append(names, host.Name)
 	}
@@ -65,7 +69,7 @@ func hostNames(hosts []*Host) []string {
 }
 
 func groupNames(hosts []*Group) []string {
-	names := make([]string, len(hosts))
+	names := make([]string, 0, len(hosts))
 	for _, host := range hosts {
 		names = append(names, host.Name)
 	}
@@ -84,19 +88,19 @@ func getBasicData() *Data {
 
 	host1.Vars = types.Vars{
 		"ansible_host":           "54.86.186.22",
-		"ansible_port":           "22",
+		"ansible_port":           22,
 		"ansible_user":           "centos",
 		"ansible_ssh_extra_args": "-o StrictHostKeyChecking=no",
 	}
 	host2.Vars = types.Vars{
 		"ansible_host":           "54.86.186.23",
-		"ansible_port":           "22",
+		"ansible_port":           22,
 		"ansible_user":           "centos",
 		"ansible_ssh_extra_args": "-o StrictHostKeyChecking=no",
 	}
 	host3.Vars = types.Vars{
 		"ansible_host":           "54.86.186.24",
-		"ansible_port":           "22",
+		"ansible_port":           22,
 		"ansible_user":           "centos",
 		"ansible_ssh_extra_args": "-o StrictHostKeyChecking=no",
 	}
@@ -126,11 +130,11 @@ func getGroupsData() *Data {
 	host := newHost("host")
 
 	host.Vars = types.Vars{
-		"foo": "42",
+		"foo": 42,
 	}
 	groupGroup.Vars = types.Vars{
-		"foo": "6",
-		"bar": "9",
+		"foo": 6,
+		"bar": 9,
 	}
 
 	host.Groups = append(host.Groups, groupGroup)
@@ -268,12 +272,12 @@ func TestGetVars(t *testing.T) {
 	allVars := make(types.Vars)
 	ungroupedVars := make(types.Vars)
 	groupVars := types.Vars{
-		"foo": "6",
-		"bar": "9",
+		"foo": 6,
+		"bar": 9,
 	}
 	hostVars := types.Vars{
-		"foo": "42",
-		"bar": "9",
+		"foo": 42,
+		"bar": 9,
 	}
 
 	if !reflect.DeepEqual(allVars, d.Groups["all"].AllVars()) {
@@ -322,5 +326,426 @@ func TestDetermineHosts(t *testing.T) {
 	// Multiple group patterns
 	assertEqual("g1:g2:g3", []string{"h2", "h3", "h4"})
 	assertEqual("all:!g1", []string{"h1", "h3", "h4", "h5"})
-	assertEqual("all:&g5:ungrouped", []string{"h1", "h4", "h5"})
+	// Intersections and exclusions are applied after all plain patterns.
+	assertEqual("all:&g5:ungrouped", []string{"h4", "h5"})
+	assertEqual("!g5", []string{"h1", "h2", "h3"})
+
+	// Mixed hosts and groups
+	assertEqual("h1:g5", []string{"h1", "h4", "h5"})
+	assertEqual("h1,g1", []string{"h1", "h2"})
+	assertEqual("g2:&h3", []string{"h3"})
+
+	// Wildcards and regular expressions
+	assertEqual("h*", []string{"h1", "h2", "h3", "h4", "h5"})
+	assertEqual("h[!12]:g1", []string{"h2", "h3", "h4", "h5"})
+	assertEqual("~h[13]", []string{"h1", "h3"})
+	assertEqual("all:!~h[2-4]", []string{"h1", "h5"})
+	assertEqual("g?", []string{"h2", "h3", "h4", "h5"})
+
+	// Subscripts
+	assertEqual("all[0]", []string{"h1"})
+	assertEqual("all[-1]", []string{"h5"})
+	assertEqual("all[1:3]", []string{"h2", "h3", "h4"})
+	assertEqual("all[3:]", []string{"h4", "h5"})
+	assertEqual("g5[1]:h1", []string{"h1", "h5"})
+	assertEqual("all[10]", []string{})
+
+	// Unknown patterns match nothing
+	assertEqual("unknown", []string{})
+	assertEqual("", []string{})
+}
+
+func TestMatchHostsOrder(t *testing.T) {
+	data, err := Parse("tests/assets/simpleManyGroups.ini")
+	if err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+	hosts, err := data.MatchHosts("g5:h2:h1")
+	if err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+	expected := []string{"h4", "h5", "h2", "h1"}
+	if names := hostNames(hosts); !reflect.DeepEqual(names, expected) {
+		t.Error("on", "g5:h2:h1", "expected", expected, "got", names)
+	}
+}
+
+func TestSplitPattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{pattern: "web:db", expected: []string{"web", "db"}},
+		{pattern: " web , db ", expected: []string{"web", "db"}},
+		{pattern: "web[0:2]:!db", expected: []string{"web[0:2]", "!db"}},
+		{pattern: "web[0:2],db", expected: []string{"web[0:2]", "db"}},
+		{pattern: "fe80::1", expected: []string{"fe80::1"}},
+		{pattern: "[fe80::1]:22", expected: []string{"[fe80::1]:22"}},
+		{pattern: "fe80::1,::1", expected: []string{"fe80::1", "::1"}},
+		{pattern: "~web\\d+:&staging", expected: []string{"~web\\d+", "&staging"}},
+		{pattern: "", expected: []string{}},
+	}
+
+	for _, testCase := range testCases {
+		if res := splitPattern(testCase.pattern); !reflect.DeepEqual(res, testCase.expected) {
+			t.Error("on", testCase.pattern, "expected", testCase.expected, "got", res)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	data, err := Parse("tests/assets/simpleManyGroups.ini")
+	if err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+	retryFile := filepath.Join(t.TempDir(), "playbook.retry")
+	if err = os.WriteFile(retryFile, []byte("h2\nh5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		limit    string
+		pattern  string
+		expected []string
+	}{
+		{limit: "g5", pattern: "all", expected: []string{"h4", "h5"}},
+		{limit: "!h1", pattern: "ungrouped:g1", expected: []string{"h2"}},
+		{limit: "@" + retryFile, pattern: "all", expected: []string{"h2", "h5"}},
+		{limit: "@" + retryFile + ",h1", pattern: "ungrouped:g5", expected: []string{"h1", "h5"}},
+		{limit: "", pattern: "g5", expected: []string{"h4", "h5"}},
+	}
+
+	for _, testCase := range testCases {
+		if err = data.Limit(testCase.limit); err != nil {
+			t.Fatal("on", testCase.limit, err)
+		}
+		names := determineHostNamesByPattern(data, testCase.pattern, t)
+		if !nameListEqual(names, testCase.expected) {
+			t.Error("on", testCase.limit, testCase.pattern, "expected", testCase.expected, "got", names)
+		}
+	}
+
+	if err = data.Limit("@" + filepath.Join(t.TempDir(), "missing.retry")); err == nil {
+		t.Error("on missing limit file", "expected error")
+	}
+}
+
+func TestExpandHostPattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+		port     int
+		err      bool
+	}{
+		{pattern: "web", expected: []string{"web"}},
+		{pattern: "web:2222", expected: []string{"web"}, port: 2222},
+		{pattern: "web[1:3]", expected: []string{"web1", "web2", "web3"}},
+		{pattern: "web[08:10].example.com", expected: []string{"web08.example.com", "web09.example.com", "web10.example.com"}},
+		{pattern: "web[:4:2]", expected: []string{"web0", "web2", "web4"}},
+		{pattern: "db-[a:c]", expected: []string{"db-a", "db-b", "db-c"}},
+		{pattern: "db-[a:e:2]-[1:2]", expected: []string{"db-a-1", "db-a-2", "db-c-1", "db-c-2", "db-e-1", "db-e-2"}},
+		{pattern: "web[1:2]:22", expected: []string{"web1", "web2"}, port: 22},
+		{pattern: "10.0.0.1:22", expected: []string{"10.0.0.1"}, port: 22},
+		{pattern: "fe80::1", expected: []string{"fe80::1"}},
+		{pattern: "[fe80::1]", expected: []string{"fe80::1"}},
+		{pattern: "[fe80::1]:22", expected: []string{"fe80::1"}, port: 22},
+		{pattern: "web[01:100]", err: true},
+		{pattern: "web[c:a]", err: true},
+		{pattern: "web[1:c]", err: true},
+		{pattern: "web[1:]", err: true},
+		{pattern: "web[1:3:0]", err: true},
+		{pattern: "all:", err: true},
+		{pattern: "web:99999", err: true},
+	}
+
+	for _, testCase := range testCases {
+		names, port, err := expandHostPattern(testCase.pattern)
+		if testCase.err {
+			if err == nil {
+				t.Error("on", testCase.pattern, "expected error, got", names)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error("on", testCase.pattern, "unexpected error", err)
+		} else if !reflect.DeepEqual(names, testCase.expected) || port != testCase.port {
+			t.Error("on", testCase.pattern, "expected", testCase.expected, testCase.port, "got", names, port)
+		}
+	}
+}
+
+func TestRanges(t *testing.T) {
+	hostVars := map[string]types.Vars{
+		"web01.example.com": {"http_port": 8080, "enabled": true},
+		"web02.example.com": {"http_port": 8080, "enabled": true},
+		"web03.example.com": {"http_port": 8080, "enabled": true},
+		"db-a":              {"ansible_port": 2222, "tags": []interface{}{"db", "primary"}},
+		"db-b":              {"ansible_port": 2222, "tags": []interface{}{"db", "primary"}},
+		"db-c":              {"ansible_port": 2222, "tags": []interface{}{"db", "primary"}},
+		"fe80::1":           {"ansible_port": 2200, "comment": "with # inside"},
+	}
+	groupVars := types.Vars{
+		"ntp_servers": []interface{}{"0.pool.ntp.org", "1.pool.ntp.org"},
+		"ratio":       0.5,
+		"name":        "quoted",
+		"plain":       "some text",
+	}
+	order := []string{"web01.example.com", "web02.example.com", "web03.example.com", "db-a", "db-b", "db-c", "fe80::1"}
+
+	for _, file := range []string{"tests/assets/ranges.ini", "tests/assets/ranges.yaml"} {
+		data, err := Parse(file)
+		if err != nil {
+			t.Fatal("on", file, "Error was not expected", err)
+		}
+		if len(data.Hosts) != len(hostVars) {
+			t.Error("on", file, "expected hosts", maps.Keys(hostVars), "got", maps.Keys(data.Hosts))
+		}
+		for name, vars := range hostVars {
+			if host, ok := data.Hosts[name]; !ok || !reflect.DeepEqual(host.Vars, vars) {
+				t.Error("on", file, name, "expected", vars, "got", host)
+			}
+		}
+		if !reflect.DeepEqual(data.Groups["web"].Vars, groupVars) {
+			t.Error("on", file, "expected group vars", groupVars, "got", data.Groups["web"].Vars)
+		}
+		hosts, err := data.MatchHosts("web")
+		if err != nil {
+			t.Fatal("on", file, err)
+		}
+		if names := hostNames(hosts); !reflect.DeepEqual(names, order) {
+			t.Error("on", file, "expected order", order, "got", names)
+		}
+	}
+}
+
+func TestParseSources(t *testing.T) {
+	testCases := []struct {
+		sources   []string
+		order     []string
+		hostVars  map[string]types.Vars
+		groupVars map[string]types.Vars
+		ungrouped []string
+	}{
+		{
+			sources: []string{"tests/assets/sources"},
+			order:   []string{"web1", "web2", "db1", "db2", "web3"},
+			hostVars: map[string]types.Vars{
+				"web1": {"http_port": 80},
+				"web2": {"http_port": 8080},
+				"db1":  {"primary": true, "ansible_port": 2222},
+			},
+			groupVars: map[string]types.Vars{
+				"web": {"role": "frontend"},
+				"db":  {"role": "database"},
+				"dc":  {"region": "eu"},
+			},
+			ungrouped: []string{},
+		},
+		{
+			sources:   []string{"tests/assets/hostVarsScript"},
+			order:     []string{"h1", "h2"},
+			hostVars:  map[string]types.Vars{"h1": {"name": "h1", "id": 1}, "h2": {"name": "h2", "id": 1}},
+			ungrouped: []string{"h1"},
+		},
+		{
+			sources:   []string{"a, b:2222,[fe80::1]:22,"},
+			order:     []string{"a", "b", "fe80::1"},
+			hostVars:  map[string]types.Vars{"a": {}, "b": {"ansible_port": 2222}, "fe80::1": {"ansible_port": 22}},
+			ungrouped: []string{"a", "b", "fe80::1"},
+		},
+		{
+			sources:   []string{"tests/assets/simpleManyGroups.ini", "h2,h6"},
+			order:     []string{"h1", "h2", "h3", "h4", "h5", "h6"},
+			ungrouped: []string{"h1", "h6"},
+		},
+	}
+
+	for _, tc := range testCases {
+		data, err := Parse(tc.sources...)
+		if err != nil {
+			t.Fatal("on", tc.sources, "Error was not expected", err)
+		}
+		hosts, err := data.MatchHosts("all")
+		if err != nil {
+			t.Fatal("on", tc.sources, err)
+		}
+		if names := hostNames(hosts); !reflect.DeepEqual(names, tc.order) {
+			t.Error("on", tc.sources, "expected hosts", tc.order, "got", names)
+		}
+		for name, vars := range tc.hostVars {
+			if host, ok := data.Hosts[name]; !ok || !reflect.DeepEqual(host.Vars, vars) {
+				t.Error("on", tc.sources, name, "expected", vars, "got", host)
+			}
+		}
+		for name, vars := range tc.groupVars {
+			if group, ok := data.Groups[name]; !ok || !reflect.DeepEqual(group.Vars, vars) {
+				t.Error("on", tc.sources, name, "expected", vars, "got", group)
+			}
+		}
+		if names := hostNames(data.Groups["ungrouped"].Hosts); !nameListEqual(names, tc.ungrouped) {
+			t.Error("on", tc.sources, "expected ungrouped", tc.ungrouped, "got", names)
+		}
+	}
+}
+
+func TestParseSourcesErrors(t *testing.T) {
+	dir := t.TempDir()
+	notExecutable := filepath.Join(dir, "inventory.sh")
+	if err := os.WriteFile(notExecutable, []byte("#!/bin/sh\necho '{}'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	failing := filepath.Join(dir, "failing.sh")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range []string{filepath.Join(dir, "missing"), notExecutable, failing} {
+		if _, err := Parse(source); err == nil {
+			t.Error("on", source, "expected error")
+		}
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "inventory.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat \"$(dirname \"$0\")/list.json\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeList := func(list string) {
+		if err := os.WriteFile(filepath.Join(dir, "list.json"), []byte(list), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeList(`{"g": ["a", "b"], "_meta": {"hostvars": {}}}`)
+	data, err := Parse(script)
+	if err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+	a := data.Hosts["a"]
+
+	writeList(`{"g": ["a", "c"], "_meta": {"hostvars": {"a": {"x": 1}}}}`)
+	if err = data.Refresh(); err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+	if names := maps.Keys(data.Hosts); !nameListEqual(names, []string{"a", "c"}) {
+		t.Error("expected hosts", []string{"a", "c"}, "got", names)
+	}
+	if data.Hosts["a"] != a {
+		t.Error("expected host a to be preserved")
+	}
+	if !reflect.DeepEqual(a.Vars, types.Vars{"x": 1}) {
+		t.Error("expected refreshed vars of a, got", a.Vars)
+	}
+	if hosts := data.Groups["g"].GetHosts(); hosts["a"] != a || hosts["c"] == nil {
+		t.Error("expected refreshed hosts of g, got", hosts)
+	}
+}
+
+func TestRefreshConcurrentMatch(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "inventory.sh")
+	list := `{"g": ["a", "b"], "_meta": {"hostvars": {"a": {"x": 1}}}}`
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho '"+list+"'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := Parse(script)
+	if err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			if err := data.Refresh(); err != nil {
+				t.Error("Error was not expected", err)
+			}
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		hosts, err := data.MatchHosts("g")
+		if err != nil {
+			t.Fatal("Error was not expected", err)
+		}
+		for _, host := range hosts {
+			if host.Name != "a" && host.Name != "b" {
+				t.Error("unexpected host", host.Name)
+			}
+		}
+	}
+}
+
+func TestConstructed(t *testing.T) {
+	data, err := Parse("tests/assets/constructed")
+	if err != nil {
+		t.Fatal("Error was not expected", err)
+	}
+	hostGroups := map[string][]string{
+		"node1":   {"dc_eu1", "europe", "loc_datacenter_eu1", "loc_rack_1", "owner_alice", "rack_eu1_1", "scylla", "seeds", "tag_seed", "tag_ssd"},
+		"node2":   {"dc_eu1", "europe", "loc_datacenter_eu1", "loc_rack_2", "owner_nobody", "rack_eu1_2", "scylla", "tag_ssd"},
+		"node3":   {"dc_us1", "loc_datacenter_us1", "loc_rack_1", "owner_nobody", "rack_us1_1", "scylla"},
+		"monitor": {"owner_nobody", "scylla"},
+	}
+	for name, expected := range hostGroups {
+		if groups := groupNames(data.Hosts[name].GetGroups()); !nameListEqual(groups, expected) {
+			t.Error("on", name, "expected groups", expected, "got", groups)
+		}
+	}
+	if children := groupNames(data.Groups["dc_eu1"].Children); !nameListEqual(children, []string{"rack_eu1_1", "rack_eu1_2"}) {
+		t.Error("expected racks of dc_eu1, got", children)
+	}
+	if vars := data.Hosts["node1"].Vars; vars["dc"] != "eu1" || vars["short_name"] != "NODE1" {
+		t.Error("expected composed vars, got", vars)
+	}
+	if _, ok := data.Hosts["monitor"].Vars["dc"]; ok {
+		t.Error("expected no dc for monitor")
+	}
+}
+
+func TestConstructedStrict(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "01-hosts.yaml"), []byte("all:\n  hosts:\n    h1:\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, strict := range []bool{false, true} {
+		config := fmt.Sprintf("plugin: constructed\nstrict: %t\nkeyed_groups:\n  - key: undefined_var\n", strict)
+		if err := os.WriteFile(filepath.Join(dir, "02-constructed.yml"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Parse(dir); (err != nil) != strict {
+			t.Error("on strict", strict, "unexpected error", err)
+		}
+	}
+}
+
+func TestEnabledPlugins(t *testing.T) {
+	settings := &config.Manager().Settings
+	enabled := settings.INVENTORY_ENABLED
+	defer func() { settings.INVENTORY_ENABLED = enabled }()
+
+	testCases := []struct {
+		enabled []string
+		source  string
+		valid   bool
+	}{
+		{enabled: []string{"yaml"}, source: "tests/assets/basicRead.yaml", valid: true},
+		{enabled: []string{"ansible.builtin.ini"}, source: "tests/assets/basicRead.yaml", valid: false},
+		{enabled: []string{"ini", "toml"}, source: "tests/assets/basicRead.ini", valid: true},
+		{enabled: []string{"yaml", "ini"}, source: "h1,h2", valid: false},
+		{enabled: []string{"host_list"}, source: "h1,h2", valid: true},
+		{enabled: []string{"yaml"}, source: "tests/assets/constructed/02-constructed.yml", valid: false},
+	}
+	for _, tc := range testCases {
+		settings.INVENTORY_ENABLED = tc.enabled
+		if _, err := Parse(tc.source); (err == nil) != tc.valid {
+			t.Error("on", tc.enabled, tc.source, "expected valid", tc.valid, "got", err)
+		}
+	}
 }
@@ -1,17 +1,119 @@
 package inventory
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
 
-type parser = func(string) (*Data, error)
+// ignoredExtensions are the extensions of the files skipped in inventory directories, the default of Ansible's
+// INVENTORY_IGNORE_EXTS.
+var ignoredExtensions = []string{".pyc", ".pyo", ".swp", ".bak", "~", ".rpm", ".md", ".txt", ".rst", ".orig", ".ini", ".cfg", ".retry"}
 
-var parsers = [...]parser{parseYAML, parseIni}
+// ignoredAlways are the patterns of the names always skipped in inventory directories.
+var ignoredAlways = regexp.MustCompile(`^\.|^host_vars$|^group_vars$|^vars_plugins$`)
 
-func Parse(filename string) (*Data, error) {
-	for _, parser := range parsers {
-		data, err := parser(filename)
-		if err == nil {
-			return data, nil
+// Parse reads the inventory from the sources, which are merged in the given order. A source is a directory of sources
+// or anything parsed by an enabled plugin: an inventory file, an executable inventory script, a comma separated
+// host list, e.g. "host1,host2:2222,", or a plugin configuration file.
+func Parse(sources ...string) (*Data, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no inventory sources given")
+	}
+	data := newData()
+	for _, source := range sources {
+		if err := data.parseSource(source); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	data.Sources = sources
+	data.reconcile()
+	if err := data.formatAndValidate(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Refresh parses the sources of the inventory again, e.g. to get the current hosts of dynamic inventories.
+// Hosts present before the refresh keep their identity, so that the state kept for them, e.g. facts, is preserved.
+// Only their variables, groups and order change, their names are never written.
+func (d *Data) Refresh() error {
+	fresh, err := Parse(d.Sources...)
+	if err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for name, host := range fresh.Hosts {
+		if old, ok := d.Hosts[name]; ok {
+			old.Vars, old.Groups, old.order = host.Vars, host.Groups, host.order
+			fresh.Hosts[name] = old
 		}
 	}
-	return nil, errors.New("unknown parse format")
+	for _, group := range fresh.Groups {
+		for i, host := range group.Hosts {
+			group.Hosts[i] = fresh.Hosts[host.Name]
+		}
+	}
+	d.Hosts, d.Groups = fresh.Hosts, fresh.Groups
+	return nil
+}
+
+func (d *Data) parseSource(source string) error {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return d.parseDir(source)
+	}
+	return d.parseWithPlugins(source)
+}
+
+// parseDir parses the sources in the directory in lexical order. Hidden files, vars directories
+// and files with ignored extensions are skipped.
+func (d *Data) parseDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	ignored, err := ignoredPatterns()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if ignoredAlways.MatchString(name) || hasIgnoredExtension(name) || (ignored != nil && ignored.MatchString(name)) {
+			continue
+		}
+		if err = d.parseSource(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasIgnoredExtension(name string) bool {
+	for _, ext := range ignoredExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func ignoredPatterns() (*regexp.Regexp, error) {
+	patterns := config.Manager().Settings.INVENTORY_IGNORE_PATTERNS
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	re, err := regexp.Compile(strings.Join(patterns, "|"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid INVENTORY_IGNORE_PATTERNS: %w", err)
+	}
+	return re, nil
 }
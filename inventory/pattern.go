@@ -0,0 +1,418 @@
+package inventory
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/utils/display"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Host patterns, see https://docs.ansible.com/ansible/latest/user_guide/intro_patterns.html.
+//
+// A pattern is a list of terms separated by ',' or ':'. A term selects hosts by a host or group name,
+// a glob (web*), or a regular expression (~web\d+), optionally followed by a subscript (webservers[0], webservers[1:3]).
+// Terms prefixed with '&' intersect the selected hosts, terms prefixed with '!' exclude them.
+
+// MatchHosts returns the hosts matching the pattern (value of hosts property in playbook), in inventory order.
+// Only the hosts allowed by Limit are returned.
+func (d *Data) MatchHosts(pattern string) ([]*Host, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	hosts, err := d.evaluatePatterns(splitPattern(pattern))
+	if err != nil {
+		return nil, err
+	}
+	if d.limit == nil {
+		return hosts, nil
+	}
+	allowed, err := d.evaluatePatterns(d.limit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit: %w", err)
+	}
+	return intersectHosts(hosts, allowed), nil
+}
+
+// DetermineHosts takes a pattern describing a hosts list (value of hosts property in playbook)
+// and returns a list of hosts that match the pattern.
+func (d *Data) DetermineHosts(pattern string) (map[string]*Host, error) {
+	matched, err := d.MatchHosts(pattern)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make(map[string]*Host, len(matched))
+	for _, host := range matched {
+		hosts[host.Name] = host
+	}
+	return hosts, nil
+}
+
+// Limit restricts the hosts matched by MatchHosts to the ones matching the pattern, like the --limit flag.
+// Terms starting with '@' name files listing a term per line, e.g. retry files. An empty pattern removes the limit.
+func (d *Data) Limit(pattern string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if strings.TrimSpace(pattern) == "" {
+		d.limit = nil
+		return nil
+	}
+	var limit []string
+	for _, term := range splitPattern(pattern) {
+		if term[0] != '@' {
+			limit = append(limit, term)
+			continue
+		}
+		terms, err := readLimitFile(term[1:])
+		if err != nil {
+			return err
+		}
+		limit = append(limit, terms...)
+	}
+	d.limit = limit
+	return nil
+}
+
+func readLimitFile(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find limit file %s", path)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("limit starting with \"@\" must be a file, not a directory: %s", path)
+	}
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var terms []string
+	for _, line := range strings.Split(string(dat), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			terms = append(terms, line)
+		}
+	}
+	return terms, nil
+}
+
+// splitPattern splits the pattern into terms. Commas take precedence; without them, terms are separated by colons
+// which are not part of an IPv6 address or of a bracketed subscript.
+func splitPattern(pattern string) []string {
+	var terms []string
+	if strings.Contains(pattern, ",") {
+		terms = strings.Split(pattern, ",")
+	} else if isIPv6Term(strings.TrimSpace(pattern)) {
+		terms = []string{pattern}
+	} else {
+		terms = tokenizeColonPattern(pattern)
+	}
+
+	result := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			result = append(result, term)
+		}
+	}
+	return result
+}
+
+// isIPv6Term reports whether the term is an IPv6 address, optionally bracketed and followed by a port.
+func isIPv6Term(term string) bool {
+	term = strings.TrimLeft(term, "!&")
+	if strings.HasPrefix(term, "[") {
+		end := strings.Index(term, "]")
+		if end < 0 {
+			return false
+		}
+		if rest := term[end+1:]; rest != "" {
+			if _, err := strconv.Atoi(strings.TrimPrefix(rest, ":")); err != nil || rest[0] != ':' {
+				return false
+			}
+		}
+		term = term[1:end]
+	}
+	return strings.Count(term, ":") >= 2 && net.ParseIP(term) != nil
+}
+
+// tokenizeColonPattern returns the runs of characters other than whitespace, ':' and brackets.
+// Complete bracketed expressions are kept within their term.
+func tokenizeColonPattern(pattern string) []string {
+	var terms []string
+	var term strings.Builder
+	flush := func() {
+		if term.Len() > 0 {
+			terms = append(terms, term.String())
+			term.Reset()
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				flush()
+				continue
+			}
+			term.WriteString(pattern[i : i+end+1])
+			i += end
+		case c == ':' || c == ']' || unicode.IsSpace(rune(c)):
+			flush()
+		default:
+			term.WriteByte(c)
+		}
+	}
+	flush()
+	return terms
+}
+
+// evaluatePatterns returns the hosts selected by the terms. Terms are applied in the order: plain ones,
+// intersections and exclusions. If there are no plain terms, the intersections and exclusions apply to all hosts.
+func (d *Data) evaluatePatterns(terms []string) ([]*Host, error) {
+	var plain, intersections, exclusions []string
+	for _, term := range terms {
+		switch term[0] {
+		case '&':
+			intersections = append(intersections, term)
+		case '!':
+			exclusions = append(exclusions, term)
+		default:
+			plain = append(plain, term)
+		}
+	}
+	if len(plain) == 0 && len(terms) > 0 {
+		plain = []string{"all"}
+	}
+
+	var hosts []*Host
+	for _, term := range append(append(plain, intersections...), exclusions...) {
+		matched, err := d.matchTerm(strings.TrimLeft(term, "&!"))
+		if err != nil {
+			return nil, err
+		}
+		switch term[0] {
+		case '&':
+			hosts = intersectHosts(hosts, matched)
+		case '!':
+			hosts = excludeHosts(hosts, matched)
+		default:
+			hosts = unionHosts(hosts, matched)
+		}
+	}
+	return hosts, nil
+}
+
+var subscriptRegexp = regexp.MustCompile(`^(.+)\[(?:(-?[0-9]+)|([0-9]+)([:-])([0-9]*))\]$`)
+
+// matchTerm returns the hosts selected by a single term, without the '&' or '!' prefix.
+func (d *Data) matchTerm(term string) ([]*Host, error) {
+	if term == "" {
+		return nil, fmt.Errorf("empty host pattern")
+	}
+	var sub *subscript
+	if term[0] != '~' {
+		if m := subscriptRegexp.FindStringSubmatch(term); m != nil {
+			term = m[1]
+			sub = parseSubscript(term, m[2], m[3], m[4], m[5])
+		}
+	}
+
+	hosts, err := d.enumerateMatches(term)
+	if err != nil {
+		return nil, err
+	}
+	return sub.apply(hosts), nil
+}
+
+// subscript selects the hosts from start to end inclusive, or only the host at start if single.
+// A negative start counts from the end.
+type subscript struct {
+	start, end int
+	single     bool
+	toEnd      bool
+}
+
+func parseSubscript(term, idx, start, sep, end string) *subscript {
+	if idx != "" {
+		i, _ := strconv.Atoi(idx)
+		return &subscript{start: i, single: true}
+	}
+	if sep == "-" {
+		display.Warning(display.WarnOptions{}, "use [x:y] inclusive subscripts instead of [x-y] in %s", term)
+	}
+	s := &subscript{toEnd: end == ""}
+	s.start, _ = strconv.Atoi(start)
+	s.end, _ = strconv.Atoi(end)
+	return s
+}
+
+func (s *subscript) apply(hosts []*Host) []*Host {
+	if s == nil {
+		return hosts
+	}
+	start := s.start
+	if start < 0 {
+		start += len(hosts)
+	}
+	if start < 0 || start >= len(hosts) {
+		return nil
+	}
+	if s.single {
+		return hosts[start : start+1]
+	}
+	end := s.end
+	if s.toEnd || end >= len(hosts) {
+		end = len(hosts) - 1
+	}
+	if end < start {
+		return nil
+	}
+	return hosts[start : end+1]
+}
+
+// enumerateMatches returns the hosts of the groups matching the term, and the hosts matching it if no group matched
+// or if the term is a glob or a regular expression.
+func (d *Data) enumerateMatches(term string) ([]*Host, error) {
+	matcher, err := compileTerm(term)
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []*Host
+	groupNames := make([]string, 0, len(d.Groups))
+	for name := range d.Groups {
+		if matcher.MatchString(name) {
+			groupNames = append(groupNames, name)
+		}
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		hosts = unionHosts(hosts, orderHosts(d.Groups[name].GetHosts()))
+	}
+
+	if len(groupNames) == 0 || term[0] == '~' || strings.ContainsAny(term, ".?*[") {
+		matched := make(map[string]*Host)
+		for name, host := range d.Hosts {
+			if matcher.MatchString(name) {
+				matched[name] = host
+			}
+		}
+		hosts = unionHosts(hosts, orderHosts(matched))
+	}
+
+	if len(hosts) == 0 && len(groupNames) == 0 && term != "all" {
+		if err = reportMismatch(term); err != nil {
+			return nil, err
+		}
+	}
+	return hosts, nil
+}
+
+// compileTerm compiles the term to a regular expression. Terms starting with '~' are regular expressions matched
+// at the beginning of names, other terms are globs matching whole names.
+func compileTerm(term string) (*regexp.Regexp, error) {
+	expr := "^(?:" + globToRegexp(term) + ")$"
+	if term[0] == '~' {
+		expr = "^(?:" + term[1:] + ")"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host list pattern %s: %w", term, err)
+	}
+	return re, nil
+}
+
+// globToRegexp translates a shell glob, supporting '*', '?' and '[...]' character classes, to a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+func reportMismatch(term string) error {
+	msg := fmt.Sprintf("could not match supplied host pattern, ignoring: %s", term)
+	switch config.Manager().Settings.HOST_PATTERN_MISMATCH {
+	case "error":
+		return fmt.Errorf("could not match supplied host pattern: %s", term)
+	case "ignore":
+		display.Debug(nil, "%s", msg)
+	default:
+		display.Warning(display.WarnOptions{}, "%s", msg)
+	}
+	return nil
+}
+
+// orderHosts returns the hosts in inventory order.
+func orderHosts(hosts map[string]*Host) []*Host {
+	ordered := make([]*Host, 0, len(hosts))
+	for _, host := range hosts {
+		ordered = append(ordered, host)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].order != ordered[j].order {
+			return ordered[i].order < ordered[j].order
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+	return ordered
+}
+
+func unionHosts(hosts, other []*Host) []*Host {
+	seen := make(map[*Host]bool, len(hosts))
+	for _, host := range hosts {
+		seen[host] = true
+	}
+	for _, host := range other {
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func intersectHosts(hosts, other []*Host) []*Host {
+	return filterHosts(hosts, other, true)
+}
+
+func excludeHosts(hosts, other []*Host) []*Host {
+	return filterHosts(hosts, other, false)
+}
+
+func filterHosts(hosts, other []*Host, keepOther bool) []*Host {
+	inOther := make(map[*Host]bool, len(other))
+	for _, host := range other {
+		inOther[host] = true
+	}
+	result := make([]*Host, 0, len(hosts))
+	for _, host := range hosts {
+		if inOther[host] == keepOther {
+			result = append(result, host)
+		}
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/parsing/vault"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/fqcn"
+	"github.com/scylladb/gosible/utils/slices"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Plugin parses inventory sources, like Ansible's inventory plugins.
+// The plugins enabled by INVENTORY_ENABLED are tried in order, the first one that verifies and parses a source wins.
+type Plugin interface {
+	// VerifyFile reports whether the source looks like one the plugin parses, e.g. by its extension.
+	// It should be cheap, the source is parsed by Parse.
+	VerifyFile(source string) bool
+	// Parse adds the hosts and groups of the source to the inventory, which holds the sources parsed so far.
+	Parse(inventory *Data, source string) error
+}
+
+var plugins = map[string]Plugin{
+	"host_list":   hostListPlugin{},
+	"script":      scriptPlugin{},
+	"auto":        autoPlugin{},
+	"yaml":        yamlPlugin{},
+	"ini":         iniPlugin{},
+	"constructed": constructedPlugin{},
+}
+
+// RegisterPlugin makes the plugin available by the name, in INVENTORY_ENABLED and in plugin configuration files.
+func RegisterPlugin(name string, plugin Plugin) {
+	plugins[name] = plugin
+}
+
+// FindPlugin returns the plugin with the name, which may be given as an ansible.builtin FQCN, or nil if there is none.
+func FindPlugin(name string) Plugin {
+	for n, plugin := range plugins {
+		if slices.Contains(fqcn.ToInternalFcqns(n), name) {
+			return plugin
+		}
+	}
+	return nil
+}
+
+type namedPlugin struct {
+	name string
+	Plugin
+}
+
+func enabledPlugins() []namedPlugin {
+	var enabled []namedPlugin
+	for _, name := range config.Manager().Settings.INVENTORY_ENABLED {
+		if plugin := FindPlugin(name); plugin != nil {
+			enabled = append(enabled, namedPlugin{name, plugin})
+		} else {
+			display.Debug(nil, "inventory plugin %s is not available, skipping it", name)
+		}
+	}
+	return enabled
+}
+
+// parseWithPlugins parses the source with the first enabled plugin which verifies it and parses it successfully.
+func (d *Data) parseWithPlugins(source string) error {
+	var failures []string
+	for _, plugin := range enabledPlugins() {
+		if !plugin.VerifyFile(source) {
+			continue
+		}
+		if err := plugin.Parse(d, source); err != nil {
+			failures = append(failures, fmt.Sprintf("%s plugin: %v", plugin.name, err))
+			continue
+		}
+		display.Debug(nil, "parsed %s inventory source with %s plugin", source, plugin.name)
+		return nil
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	if _, err := os.Stat(source); err != nil {
+		return err
+	}
+	return errors.New("no inventory plugin could verify the source")
+}
+
+// isFile reports whether the path is an existing file, the common requirement of file based plugins.
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func hasExtension(path string, extensions []string) bool {
+	return slices.Contains(extensions, filepath.Ext(path))
+}
+
+// readSourceFile reads the inventory source, decrypting it if it is vault encrypted.
+func readSourceFile(path string) ([]byte, error) {
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return vault.DecryptFile(dat, path)
+}
+
+// readPluginConfig reads the YAML configuration file of the plugin into config.
+// The plugin key of the file must name the plugin.
+func readPluginConfig(path, plugin string, config interface{}) error {
+	dat, err := readSourceFile(path)
+	if err != nil {
+		return err
+	}
+	var header struct {
+		Plugin string `yaml:"plugin"`
+	}
+	if err = yaml.Unmarshal(dat, &header); err != nil {
+		return err
+	}
+	if !slices.Contains(fqcn.ToInternalFcqns(plugin), header.Plugin) {
+		return fmt.Errorf("incorrect plugin name in file: %s", header.Plugin)
+	}
+	return yaml.Unmarshal(dat, config)
+}
+
+// autoPlugin loads the plugin named by the plugin key of a YAML configuration file, and parses the file with it.
+type autoPlugin struct{}
+
+func (autoPlugin) VerifyFile(source string) bool {
+	return isFile(source) && hasExtension(source, []string{".yml", ".yaml"})
+}
+
+func (autoPlugin) Parse(inventory *Data, source string) error {
+	dat, err := readSourceFile(source)
+	if err != nil {
+		return err
+	}
+	var header struct {
+		Plugin string `yaml:"plugin"`
+	}
+	if err = yaml.Unmarshal(dat, &header); err != nil || header.Plugin == "" {
+		return fmt.Errorf("no root 'plugin' key found, '%s' is not a valid YAML inventory plugin config file", source)
+	}
+	plugin := FindPlugin(header.Plugin)
+	if plugin == nil {
+		return fmt.Errorf("inventory config '%s' specifies unknown plugin '%s'", source, header.Plugin)
+	}
+	if !plugin.VerifyFile(source) {
+		return fmt.Errorf("inventory source '%s' could not be verified by inventory plugin '%s'", source, header.Plugin)
+	}
+	return plugin.Parse(inventory, source)
+}
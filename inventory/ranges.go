@@ -0,0 +1,150 @@
+package inventory
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const asciiLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var (
+	bracketedAddrRegex = regexp.MustCompile(`^\[([^\]]+)\](?::([0-9]+))?$`)
+	hostPortRegex      = regexp.MustCompile(`^(.*[^:]):([0-9]+)$`)
+	hostNameRegex      = regexp.MustCompile(`^[\w.\-]+$`)
+)
+
+// expandHostPattern returns the host names described by a host pattern of an inventory, e.g. web[01:20].example.com,
+// and the port given after the names, or 0 if there is none.
+func expandHostPattern(pattern string) ([]string, int, error) {
+	name, port, err := splitPort(pattern)
+	if err != nil {
+		return nil, 0, err
+	}
+	names := []string{name}
+	if detectRange(name) {
+		if names, err = expandHostnameRange(name); err != nil {
+			return nil, 0, fmt.Errorf("invalid host pattern %s: %w", pattern, err)
+		}
+	}
+	for _, n := range names {
+		if !hostNameRegex.MatchString(n) && net.ParseIP(n) == nil {
+			return nil, 0, fmt.Errorf("not a valid host pattern: %s", pattern)
+		}
+	}
+	return names, port, nil
+}
+
+// splitPort splits the host pattern into the name and the port. IPv6 addresses followed by a port must be
+// bracketed, e.g. [fe80::1]:2222.
+func splitPort(pattern string) (string, int, error) {
+	if net.ParseIP(pattern) != nil {
+		return pattern, 0, nil
+	}
+	m := bracketedAddrRegex.FindStringSubmatch(pattern)
+	if m == nil || net.ParseIP(m[1]) == nil {
+		m = hostPortRegex.FindStringSubmatch(pattern)
+		if m == nil || strings.Count(m[1], "[") != strings.Count(m[1], "]") {
+			return pattern, 0, nil
+		}
+	}
+	if m[2] == "" {
+		return m[1], 0, nil
+	}
+	port, err := strconv.Atoi(m[2])
+	if err != nil || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port in host pattern %s", pattern)
+	}
+	return m[1], port, nil
+}
+
+// detectRange reports whether the name contains a range, i.e. a [begin:end] expression.
+func detectRange(name string) bool {
+	start := strings.Index(name, "[")
+	if start < 0 {
+		return false
+	}
+	colon := strings.Index(name[start:], ":")
+	end := strings.Index(name[start:], "]")
+	return colon > 0 && end > colon
+}
+
+// expandHostnameRange expands the ranges of the name. Ranges are given as [begin:end] or [begin:end:step], where
+// begin and end are both numbers or both letters. Numeric ranges starting with a zero are padded to the length of
+// begin, e.g. db[08:10] expands to db08, db09 and db10.
+func expandHostnameRange(name string) ([]string, error) {
+	start := strings.Index(name, "[")
+	end := start + strings.Index(name[start:], "]")
+	head, tail := name[:start], name[end+1:]
+	bounds := strings.Split(name[start+1:end], ":")
+	if len(bounds) != 2 && len(bounds) != 3 {
+		return nil, fmt.Errorf("host range must be begin:end or begin:end:step")
+	}
+	beg, last := bounds[0], bounds[1]
+	step := 1
+	if len(bounds) == 3 {
+		var err error
+		if step, err = strconv.Atoi(bounds[2]); err != nil || step <= 0 {
+			return nil, fmt.Errorf("host range step must be a positive number")
+		}
+	}
+	if beg == "" {
+		beg = "0"
+	}
+	if last == "" {
+		return nil, fmt.Errorf("host range must specify end value")
+	}
+
+	seq, err := rangeSequence(beg, last, step)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range seq {
+		expanded := head + item + tail
+		if !detectRange(expanded) {
+			names = append(names, expanded)
+			continue
+		}
+		more, err := expandHostnameRange(expanded)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, more...)
+	}
+	return names, nil
+}
+
+func rangeSequence(beg, last string, step int) ([]string, error) {
+	iBeg, iLast := strings.Index(asciiLetters, beg), strings.Index(asciiLetters, last)
+	if len(beg) == 1 && len(last) == 1 && iBeg >= 0 && iLast >= 0 {
+		if iBeg > iLast {
+			return nil, fmt.Errorf("host range must have begin <= end")
+		}
+		var seq []string
+		for i := iBeg; i <= iLast; i += step {
+			seq = append(seq, asciiLetters[i:i+1])
+		}
+		return seq, nil
+	}
+
+	nBeg, errBeg := strconv.Atoi(beg)
+	nLast, errLast := strconv.Atoi(last)
+	if errBeg != nil || errLast != nil || nBeg < 0 || nLast < 0 {
+		return nil, fmt.Errorf("host range bounds must be both numbers or both letters")
+	}
+	format := "%d"
+	if beg[0] == '0' && len(beg) > 1 {
+		if len(beg) != len(last) {
+			return nil, fmt.Errorf("host range must specify equal-length begin and end formats")
+		}
+		format = fmt.Sprintf("%%0%dd", len(beg))
+	}
+	var seq []string
+	for i := nBeg; i <= nLast; i += step {
+		seq = append(seq, fmt.Sprintf(format, i))
+	}
+	return seq, nil
+}
@@ -0,0 +1,145 @@
+package inventory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/utils/types"
+	"gopkg.in/yaml.v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Inventory scripts, see https://docs.ansible.com/ansible/latest/dev_guide/developing_inventory.html.
+//
+// A script run with --list prints a JSON dictionary of groups. A group is either a list of hosts or a dictionary
+// with hosts, vars and children. Host vars are given in _meta.hostvars, if _meta is missing the script is run
+// with --host <hostname> for each host and prints a dictionary of the host vars.
+
+// scriptPlugin runs executable inventory scripts.
+type scriptPlugin struct{}
+
+func (scriptPlugin) VerifyFile(source string) bool {
+	info, err := os.Stat(source)
+	return err == nil && !info.IsDir() && info.Mode()&0111 != 0
+}
+
+func (scriptPlugin) Parse(inventory *Data, source string) error {
+	return inventory.merge(runScript(source))
+}
+
+type scriptGroup struct {
+	Hosts    []string   `yaml:"hosts"`
+	Vars     types.Vars `yaml:"vars"`
+	Children []string   `yaml:"children"`
+}
+
+func (g *scriptGroup) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&g.Hosts); err == nil {
+		return nil
+	}
+	type plain scriptGroup
+	return unmarshal((*plain)(g))
+}
+
+type scriptMeta struct {
+	Meta *struct {
+		HostVars map[string]types.Vars `yaml:"hostvars"`
+	} `yaml:"_meta"`
+}
+
+const metaKey = "_meta"
+
+func runScript(path string) (*Data, error) {
+	out, err := execScript(path, "--list")
+	if err != nil {
+		return nil, err
+	}
+	// JSON is parsed as YAML, which it is a subset of, to get the same types of values as in YAML inventories.
+	var groups map[string]scriptGroup
+	if err = yaml.Unmarshal(out, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory script output: %w", err)
+	}
+	var order yaml.MapSlice
+	if err = yaml.Unmarshal(out, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory script output: %w", err)
+	}
+	var meta scriptMeta
+	if err = yaml.Unmarshal(out, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory script _meta: %w", err)
+	}
+
+	data := newData()
+	data.groupByName("all").initialized = true
+	for _, el := range order {
+		name, ok := el.Key.(string)
+		if !ok {
+			return nil, errors.New("unexpected group name type in inventory script output")
+		}
+		if name != metaKey {
+			data.addScriptGroup(name, groups[name])
+		}
+	}
+
+	if meta.Meta != nil {
+		for _, host := range data.Hosts {
+			mergeVars(host.Vars, meta.Meta.HostVars[host.Name])
+		}
+	} else {
+		for _, host := range orderHosts(data.Hosts) {
+			if err = host.loadScriptHostVars(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = data.formatAndValidate(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (d *Data) addScriptGroup(name string, raw scriptGroup) {
+	group := d.groupByName(name)
+	group.initialized = true
+	mergeVars(group.Vars, raw.Vars)
+	for _, hostName := range raw.Hosts {
+		group.addHost(d.hostByName(hostName))
+	}
+	for _, childName := range raw.Children {
+		child := d.groupByName(childName)
+		child.initialized = true
+		group.addChild(child)
+	}
+}
+
+func (h *Host) loadScriptHostVars(path string) error {
+	out, err := execScript(path, "--host", h.Name)
+	if err != nil {
+		return err
+	}
+	var vars types.Vars
+	if err = yaml.Unmarshal(out, &vars); err != nil {
+		return fmt.Errorf("failed to parse inventory script output for host %s: %w", h.Name, err)
+	}
+	mergeVars(h.Vars, vars)
+	return nil
+}
+
+func execScript(path string, args ...string) ([]byte, error) {
+	// An absolute path prevents looking up scripts given by a bare name in PATH.
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("inventory script %s %s failed: %w: %s", path, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
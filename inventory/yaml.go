@@ -2,9 +2,11 @@ package inventory
 
 import (
 	"errors"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/parsing/vault"
 	"github.com/scylladb/gosible/utils/types"
 	"gopkg.in/yaml.v2"
-	"os"
+	"path/filepath"
 )
 
 type orderedRawData = yaml.MapSlice
@@ -17,8 +19,23 @@ type typedRawGroup struct {
 
 type typedRawData = map[string]typedRawGroup
 
-func parseYAML(filename string) (*Data, error) {
-	dat, err := os.ReadFile(filename)
+// yamlPlugin parses YAML inventory files.
+type yamlPlugin struct{}
+
+func (yamlPlugin) VerifyFile(source string) bool {
+	return isFile(source) && (filepath.Ext(source) == "" || hasExtension(source, config.Manager().Settings.YAML_FILENAME_EXTENSIONS))
+}
+
+func (yamlPlugin) Parse(inventory *Data, source string) error {
+	dat, err := readSourceFile(source)
+	if err != nil {
+		return err
+	}
+	return inventory.merge(parseYAML(dat))
+}
+
+func parseYAML(dat []byte) (*Data, error) {
+	dat, err := vault.DecryptYAML(dat)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +113,10 @@ func (d *Data) processGroupElement(el *yaml.MapItem, group *Group, raw *typedRaw
 	}
 	switch key {
 	case "hosts":
-		hosts := d.addHosts(raw.Hosts)
+		hosts, err := d.addHosts(raw.Hosts, value)
+		if err != nil {
+			return err
+		}
 		group.Hosts = append(group.Hosts, hosts...)
 		for _, host := range hosts {
 			host.Groups = append(host.Groups, group)
@@ -118,13 +138,27 @@ func (d *Data) processGroupElement(el *yaml.MapItem, group *Group, raw *typedRaw
 	return nil
 }
 
-func (d *Data) addHosts(hosts map[string]types.Vars) []*Host {
+// addHosts adds the hosts in the order they are declared. Host names may be patterns, e.g. web[01:20].example.com.
+func (d *Data) addHosts(hosts map[string]types.Vars, order orderedRawData) ([]*Host, error) {
 	var hostPointers []*Host
-	for name, data := range hosts {
-		host := d.addVarsToHost(name, data)
-		hostPointers = append(hostPointers, host)
+	for _, el := range order {
+		pattern, okKey := el.Key.(string)
+		if !okKey {
+			return nil, errors.New("unexpected type")
+		}
+		names, port, err := expandHostPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		data := hosts[pattern]
+		if _, ok := data["ansible_port"]; !ok && port != 0 {
+			data = mergeVars(types.Vars{"ansible_port": port}, data)
+		}
+		for _, name := range names {
+			hostPointers = append(hostPointers, d.addVarsToHost(name, data))
+		}
 	}
-	return hostPointers
+	return hostPointers, nil
 }
 
 func (d *Data) addGroups(groups map[string]typedRawGroup, order orderedRawData) (groupPointers []*Group, err error) {
@@ -138,13 +172,15 @@ func (d *Data) addGroups(groups map[string]typedRawGroup, order orderedRawData)
 		case nil:
 			group = d.groupByName(key)
 			group.initialized = true
-		case yaml.MapItem:
+		case orderedRawData:
 			groupData := groups[key]
-			value := el.Value.(yaml.MapItem)
-			group, err = d.addGroup(&groupData, &value)
+			child := el
+			group, err = d.addGroup(&groupData, &child)
 			if err != nil {
 				return
 			}
+		default:
+			return nil, errors.New("unexpected type")
 		}
 		groupPointers = append(groupPointers, group)
 	}
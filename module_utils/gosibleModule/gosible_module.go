@@ -47,6 +47,7 @@ type GosibleModule[P Validatable] struct {
 	shell                   *string
 	RunCommandEnvironUpdate map[string]string
 	MetaArgs                *pb.MetaArgs
+	output                  modules.OutputStreamer
 	*wrappers.Return
 	Params          P
 	se              *selinux.Selinux
@@ -139,6 +140,7 @@ func RunCommandDefaultKwargs() *RunCommandKwargs {
 
 func (m *GosibleModule[P]) ParseParams(ctx *modules.RunContext, vars types.Vars) error {
 	m.MetaArgs = ctx.MetaArgs
+	m.output = ctx.Output
 	if err := mapstructure.Decode(vars, m.Params); err != nil {
 		return err
 	}
@@ -214,6 +216,9 @@ func (m *GosibleModule[P]) RunCommand(rawArgs interface{}, kwargs *RunCommandKwa
 	}
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
+	if m.output != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, streamWriter(m.output.Stderr))
+	}
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -334,6 +339,9 @@ func (m *GosibleModule[P]) readOutput(stdoutPipe io.Reader, promptRe *regexp.Reg
 			return nil, err
 		}
 		stdout = append(stdout, buffer[:n]...)
+		if m.output != nil {
+			m.output.Stdout(buffer[:n])
+		}
 		if promptRe != nil {
 			if (*promptRe).Find(stdout) != nil && kwargs.Data == nil {
 				const msg = "a prompt was encountered while running a command, but no input data was specified"
@@ -343,6 +351,21 @@ func (m *GosibleModule[P]) readOutput(stdoutPipe io.Reader, promptRe *regexp.Reg
 	}
 }
 
+// Progress reports the progress of a long-running module. The message is shown live when the output is streamed.
+func (m *GosibleModule[P]) Progress(msg string, a ...interface{}) {
+	if m.output != nil {
+		m.output.Progress(fmt.Sprintf(msg, a...))
+	}
+}
+
+// streamWriter passes everything written to it to an OutputStreamer method.
+type streamWriter func(data []byte)
+
+func (w streamWriter) Write(data []byte) (int, error) {
+	w(data)
+	return len(data), nil
+}
+
 func (m *GosibleModule[P]) SetFsAttributesIfDifferent(params *FileCommonParams, diff *modules.Diff, expand bool) (bool, error) {
 	changedContext, err := m.SetContextIfDifferent(params.Path, params.Se.Context, diff)
 	if err != nil {
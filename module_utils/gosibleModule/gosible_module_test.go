@@ -69,3 +69,35 @@ func checkReturnCorrect(r *RunCommandResult, o *setup, c string, t *testing.T) {
 		t.Fatal("incorrect result", r, o, c)
 	}
 }
+
+type recordingStreamer struct {
+	stdout, stderr bytes.Buffer
+	progress       []string
+}
+
+func (r *recordingStreamer) Stdout(data []byte) { r.stdout.Write(data) }
+func (r *recordingStreamer) Stderr(data []byte) { r.stderr.Write(data) }
+func (r *recordingStreamer) Progress(msg string) {
+	r.progress = append(r.progress, msg)
+}
+
+func TestRunStreamsOutput(t *testing.T) {
+	streamer := &recordingStreamer{}
+	mod := New[Validatable](nil)
+	mod.output = streamer
+
+	kwargs := *RunCommandDefaultKwargs()
+	kwargs.UseUnsafeShell = true
+	mod.Progress("running %s", "echo")
+	r, err := mod.RunCommand("echo out; echo err >&2", &kwargs)
+	if err != nil {
+		t.Fatal("function threw error", err)
+	}
+	checkReturnCorrect(r, &setup{stdout: []byte("out\n"), stderr: []byte("err\n")}, "echo", t)
+	if streamer.stdout.String() != "out\n" || streamer.stderr.String() != "err\n" {
+		t.Fatal("incorrect streamed output", streamer.stdout.String(), streamer.stderr.String())
+	}
+	if len(streamer.progress) != 1 || streamer.progress[0] != "running echo" {
+		t.Fatal("incorrect progress events", streamer.progress)
+	}
+}
@@ -0,0 +1,292 @@
+package pythonModule
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// collectionsPackage is the Python package all collection content is imported from.
+const collectionsPackage = "ansible_collections"
+
+// CollectionModule is a module of an Ansible collection found on the controller.
+type CollectionModule struct {
+	// Fqcn is the fully qualified name of the module, e.g. community.general.ufw.
+	Fqcn string
+	// Path is the location of the module source.
+	Path string
+	// roots are the collections paths the module dependencies are looked up in.
+	roots []string
+
+	filesOnce sync.Once
+	files     []RuntimeFile
+	filesErr  error
+}
+
+// RuntimeFile is a file added to the Python runtime zip.
+type RuntimeFile struct {
+	Name string
+	Data []byte
+}
+
+var collectionModules = struct {
+	sync.RWMutex
+	m map[string]*CollectionModule
+}{m: make(map[string]*CollectionModule)}
+
+// IsCollectionFqcn reports whether the name refers to a module of a collection other than ansible.builtin.
+func IsCollectionFqcn(name string) bool {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 || parts[0] == "ansible" {
+		return false
+	}
+	for _, part := range parts {
+		if !isIdentifier(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// CollectionImportName returns the name the module of the given FQCN is imported with.
+func CollectionImportName(fqcn string) string {
+	parts := strings.SplitN(fqcn, ".", 3)
+	return strings.Join([]string{collectionsPackage, parts[0], parts[1], "plugins", "modules", parts[2]}, ".")
+}
+
+// NewCollectionModule returns the module of an Ansible collection, run by the Python runtime.
+func NewCollectionModule(executorGetter PythonExecutorGetter, fqcn string) *PythonModule {
+	return NewModule(executorGetter, fqcn, CollectionImportName(fqcn))
+}
+
+// LoadCollections finds the modules of the collections installed in the given collections paths and makes them
+// available through FindCollectionModule. Modules found in earlier paths take precedence.
+func LoadCollections(roots []string) ([]*CollectionModule, error) {
+	found, err := findCollectionModules(roots)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionModules.Lock()
+	defer collectionModules.Unlock()
+	for _, m := range found {
+		collectionModules.m[m.Fqcn] = m
+	}
+	return found, nil
+}
+
+// FindCollectionModule returns the collection module loaded by LoadCollections.
+func FindCollectionModule(fqcn string) (*CollectionModule, bool) {
+	collectionModules.RLock()
+	defer collectionModules.RUnlock()
+	m, ok := collectionModules.m[fqcn]
+	return m, ok
+}
+
+func findCollectionModules(roots []string) ([]*CollectionModule, error) {
+	var ret []*CollectionModule
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		pattern := filepath.Join(root, collectionsPackage, "*", "*", "plugins", "modules", "*.py")
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			name := strings.TrimSuffix(filepath.Base(p), ".py")
+			if strings.HasPrefix(name, "_") {
+				continue
+			}
+			collection := filepath.Dir(filepath.Dir(filepath.Dir(p)))
+			fqcn := filepath.Base(filepath.Dir(collection)) + "." + filepath.Base(collection) + "." + name
+			if !IsCollectionFqcn(fqcn) || seen[fqcn] {
+				continue
+			}
+			seen[fqcn] = true
+			ret = append(ret, &CollectionModule{Fqcn: fqcn, Path: p, roots: roots})
+		}
+	}
+	return ret, nil
+}
+
+// RuntimeFiles returns the files needed to run the module: its source, the collection module_utils it imports
+// and the package files of all directories on the way.
+func (m *CollectionModule) RuntimeFiles() ([]RuntimeFile, error) {
+	m.filesOnce.Do(func() {
+		m.files, m.filesErr = m.collectFiles()
+	})
+	return m.files, m.filesErr
+}
+
+func (m *CollectionModule) collectFiles() ([]RuntimeFile, error) {
+	files := make(map[string]string)
+	queue := []string{CollectionImportName(m.Fqcn)}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		zipName, localPath, ok := m.resolve(name)
+		if !ok {
+			if name == CollectionImportName(m.Fqcn) {
+				return nil, fmt.Errorf("module %s not found", m.Fqcn)
+			}
+			continue
+		}
+		if _, ok := files[zipName]; ok {
+			continue
+		}
+		files[zipName] = localPath
+
+		imports, err := pythonImports(localPath, name, strings.HasSuffix(zipName, "__init__.py"))
+		if err != nil {
+			return nil, fmt.Errorf("while reading %s: %w", localPath, err)
+		}
+		queue = append(queue, imports...)
+	}
+
+	for zipName := range files {
+		for dir := filepath.Dir(zipName); dir != "."; dir = filepath.Dir(dir) {
+			initName := filepath.Join(dir, "__init__.py")
+			if _, ok := files[initName]; !ok {
+				files[initName] = ""
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ret := make([]RuntimeFile, 0, len(names))
+	for _, name := range names {
+		f := RuntimeFile{Name: name}
+		if files[name] != "" {
+			data, err := os.ReadFile(files[name])
+			if err != nil {
+				return nil, err
+			}
+			f.Data = data
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+// resolve returns the zip name and the local path of the Python module or package with the given name.
+func (m *CollectionModule) resolve(name string) (string, string, bool) {
+	rel := filepath.Join(strings.Split(name, ".")...)
+	for _, candidate := range []string{rel + ".py", filepath.Join(rel, "__init__.py")} {
+		for _, root := range m.roots {
+			p := filepath.Join(root, candidate)
+			if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+				return candidate, p, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+var (
+	fromImportRe = regexp.MustCompile(`^\s*from\s+(\.*[\w.]*)\s+import\s+(.*)$`)
+	importRe     = regexp.MustCompile(`^\s*import\s+(.*)$`)
+)
+
+// pythonImports returns the names of the collection modules which may be imported by the source at path.
+// Names imported from packages are returned as submodules as well, names which don't exist are skipped later.
+func pythonImports(path string, moduleName string, isPackage bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pkg := moduleName
+	if !isPackage {
+		pkg = moduleName[:strings.LastIndex(moduleName, ".")]
+	}
+
+	var ret []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := stripComment(scanner.Text())
+		if match := fromImportRe.FindStringSubmatch(line); match != nil {
+			from, err := absoluteImport(match[1], pkg)
+			if err != nil || !strings.HasPrefix(from, collectionsPackage+".") {
+				continue
+			}
+			ret = append(ret, from)
+			names := match[2]
+			if strings.HasPrefix(strings.TrimSpace(names), "(") {
+				for !strings.Contains(names, ")") && scanner.Scan() {
+					names += " " + stripComment(scanner.Text())
+				}
+			}
+			for _, name := range importedNames(names) {
+				ret = append(ret, from+"."+name)
+			}
+		} else if match := importRe.FindStringSubmatch(line); match != nil {
+			for _, name := range importedNames(match[1]) {
+				if strings.HasPrefix(name, collectionsPackage+".") {
+					ret = append(ret, name)
+				}
+			}
+		}
+	}
+	return ret, scanner.Err()
+}
+
+// absoluteImport resolves relative imports against the package of the importing module.
+func absoluteImport(name string, pkg string) (string, error) {
+	level := len(name) - len(strings.TrimLeft(name, "."))
+	if level == 0 {
+		return name, nil
+	}
+	parts := strings.Split(pkg, ".")
+	if level-1 >= len(parts) {
+		return "", errors.New("relative import beyond top-level package")
+	}
+	base := strings.Join(parts[:len(parts)-(level-1)], ".")
+	if rest := name[level:]; rest != "" {
+		return base + "." + rest, nil
+	}
+	return base, nil
+}
+
+// importedNames returns the names of an import list, dropping aliases and parentheses.
+func importedNames(list string) []string {
+	list = strings.NewReplacer("(", " ", ")", " ", "\\", " ").Replace(list)
+	var ret []string
+	for _, item := range strings.Split(list, ",") {
+		fields := strings.Fields(item)
+		if len(fields) > 0 && fields[0] != "*" {
+			ret = append(ret, fields[0])
+		}
+	}
+	return ret
+}
+
+func stripComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
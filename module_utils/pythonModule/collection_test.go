@@ -0,0 +1,128 @@
+package pythonModule
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIsCollectionFqcn(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "community.general.ufw", expected: true},
+		{name: "ansible.builtin.apt", expected: false},
+		{name: "apt", expected: false},
+		{name: "community.general", expected: false},
+		{name: "community.general.not-a-module", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if res := IsCollectionFqcn(testCase.name); res != testCase.expected {
+			t.Error("on", testCase.name, "expected", testCase.expected, "got", res)
+		}
+	}
+}
+
+func TestLoadCollections(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeFiles(t, first, map[string]string{
+		"ansible_collections/community/general/plugins/modules/ufw.py":      "",
+		"ansible_collections/community/general/plugins/modules/__init__.py": "",
+	})
+	writeFiles(t, second, map[string]string{
+		"ansible_collections/community/general/plugins/modules/ufw.py":   "",
+		"ansible_collections/community/general/plugins/modules/_old.py":  "",
+		"ansible_collections/my/collection/plugins/modules/something.py": "",
+	})
+
+	found, err := LoadCollections([]string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make(map[string]string)
+	for _, m := range found {
+		paths[m.Fqcn] = m.Path
+	}
+	expected := map[string]string{
+		"community.general.ufw":   filepath.Join(first, "ansible_collections/community/general/plugins/modules/ufw.py"),
+		"my.collection.something": filepath.Join(second, "ansible_collections/my/collection/plugins/modules/something.py"),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Error("on", paths, "expected", expected)
+	}
+	if _, ok := FindCollectionModule("my.collection.something"); !ok {
+		t.Error("on FindCollectionModule", "my.collection.something", "expected it to be found")
+	}
+}
+
+func TestCollectionModuleRuntimeFiles(t *testing.T) {
+	root, other := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"ansible_collections/community/general/plugins/modules/ufw.py": `
+from ansible.module_utils.basic import AnsibleModule
+from ansible_collections.community.general.plugins.module_utils.firewall import (
+    rules,  # comment
+    Chain as C,
+)
+from ..module_utils import helpers
+import ansible_collections.other.collection.plugins.module_utils.shared
+`,
+		"ansible_collections/community/general/plugins/module_utils/firewall/__init__.py": "from .rules import x\n",
+		"ansible_collections/community/general/plugins/module_utils/firewall/rules.py":    "from . import util\n",
+		"ansible_collections/community/general/plugins/module_utils/firewall/util.py":     "",
+		"ansible_collections/community/general/plugins/module_utils/helpers.py":           "",
+		"ansible_collections/community/general/plugins/module_utils/unused.py":            "",
+	})
+	writeFiles(t, other, map[string]string{
+		"ansible_collections/other/collection/plugins/module_utils/shared.py": "",
+	})
+
+	found, err := LoadCollections([]string{root, other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := found[0].RuntimeFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	expected := []string{
+		"ansible_collections/__init__.py",
+		"ansible_collections/community/__init__.py",
+		"ansible_collections/community/general/__init__.py",
+		"ansible_collections/community/general/plugins/__init__.py",
+		"ansible_collections/community/general/plugins/module_utils/__init__.py",
+		"ansible_collections/community/general/plugins/module_utils/firewall/__init__.py",
+		"ansible_collections/community/general/plugins/module_utils/firewall/rules.py",
+		"ansible_collections/community/general/plugins/module_utils/firewall/util.py",
+		"ansible_collections/community/general/plugins/module_utils/helpers.py",
+		"ansible_collections/community/general/plugins/modules/__init__.py",
+		"ansible_collections/community/general/plugins/modules/ufw.py",
+		"ansible_collections/other/__init__.py",
+		"ansible_collections/other/collection/__init__.py",
+		"ansible_collections/other/collection/plugins/__init__.py",
+		"ansible_collections/other/collection/plugins/module_utils/__init__.py",
+		"ansible_collections/other/collection/plugins/module_utils/shared.py",
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Error("on", names, "expected", expected)
+	}
+}
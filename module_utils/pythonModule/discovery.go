@@ -0,0 +1,119 @@
+package pythonModule
+
+import (
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/utils/distro"
+	"github.com/scylladb/gosible/utils/slices"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Interpreter discovery modes, see INTERPRETER_PYTHON.
+const (
+	DiscoveryAuto             = "auto"
+	DiscoveryAutoSilent       = "auto_silent"
+	DiscoveryAutoLegacy       = "auto_legacy"
+	DiscoveryAutoLegacySilent = "auto_legacy_silent"
+)
+
+// DefaultInterpreter is used when no interpreter is given.
+const DefaultInterpreter = "/usr/bin/python3"
+
+const legacyInterpreter = "/usr/bin/python"
+
+// distroMap mirrors INTERPRETER_PYTHON_DISTRO_MAP: the included system Python per distribution and major version.
+var distroMap = map[string]map[int]string{
+	"redhat": {6: "/usr/bin/python", 8: "/usr/libexec/platform-python", 9: "/usr/bin/python3"},
+	"debian": {8: "/usr/bin/python", 10: "/usr/bin/python3"},
+	"fedora": {23: "/usr/bin/python3"},
+	"ubuntu": {14: "/usr/bin/python", 16: "/usr/bin/python3"},
+}
+
+// distroAliases maps distributions to the distroMap entry they share the system Python layout with.
+var distroAliases = map[string]string{
+	"rhel":   "redhat",
+	"centos": "redhat",
+}
+
+// IsDiscoveryMode reports whether the interpreter setting requests automatic discovery.
+func IsDiscoveryMode(interpreter string) bool {
+	switch interpreter {
+	case DiscoveryAuto, DiscoveryAutoSilent, DiscoveryAutoLegacy, DiscoveryAutoLegacySilent:
+		return true
+	}
+	return false
+}
+
+// IsSilentDiscoveryMode reports whether discovery warnings should be suppressed.
+func IsSilentDiscoveryMode(mode string) bool {
+	return strings.HasSuffix(mode, "_silent")
+}
+
+// lookPath is replaced in tests.
+var lookPath = exec.LookPath
+
+// DiscoverInterpreter finds the Python interpreter of the host in the same way Ansible does. The platform Python of
+// known distributions is preferred, otherwise the first interpreter found from the fallback list is used.
+// Returns the interpreter and the warnings to show to the user.
+func DiscoverInterpreter(mode string, fallback []string) (string, []string, error) {
+	return discoverInterpreter(mode, fallback, distro.Id(), distro.Version(false))
+}
+
+func discoverInterpreter(mode string, fallback []string, distroId, distroVersion string) (string, []string, error) {
+	var found []string
+	for _, candidate := range fallback {
+		if p, err := lookPath(candidate); err == nil {
+			found = append(found, p)
+		}
+	}
+	if len(found) == 0 {
+		return "", nil, errors.New("no python interpreters found on host, set ansible_python_interpreter")
+	}
+
+	legacy := mode == DiscoveryAutoLegacy || mode == DiscoveryAutoLegacySilent
+	platformPython := platformInterpreter(distroId, distroVersion)
+	if platformPython == "" {
+		return found[0], []string{fmt.Sprintf("Platform %s %s is using the discovered Python interpreter at %s, "+
+			"but future installation of another Python interpreter could change the meaning of that path", distroId, distroVersion, found[0])}, nil
+	}
+	if legacy && platformPython != legacyInterpreter && slices.Contains(found, legacyInterpreter) {
+		return legacyInterpreter, []string{fmt.Sprintf("Distribution %s %s should use %s, but is using %s for backward compatibility "+
+			"with prior Ansible releases", distroId, distroVersion, platformPython, legacyInterpreter)}, nil
+	}
+	if slices.Contains(found, platformPython) {
+		return platformPython, nil, nil
+	}
+	return found[0], []string{fmt.Sprintf("Distribution %s %s should use %s, but it was not found, using %s instead",
+		distroId, distroVersion, platformPython, found[0])}, nil
+}
+
+// platformInterpreter returns the system Python of the distribution, taken from the closest known lower version.
+func platformInterpreter(distroId, distroVersion string) string {
+	distroId = strings.ToLower(distroId)
+	if alias, ok := distroAliases[distroId]; ok {
+		distroId = alias
+	}
+	versions, ok := distroMap[distroId]
+	if !ok {
+		return ""
+	}
+	major, err := strconv.Atoi(strings.SplitN(distroVersion, ".", 2)[0])
+	if err != nil {
+		return ""
+	}
+
+	known := make([]int, 0, len(versions))
+	for v := range versions {
+		known = append(known, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(known)))
+	for _, v := range known {
+		if v <= major {
+			return versions[v]
+		}
+	}
+	return ""
+}
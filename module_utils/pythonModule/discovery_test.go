@@ -0,0 +1,57 @@
+package pythonModule
+
+import (
+	"errors"
+	"os/exec"
+	"path"
+	"testing"
+)
+
+func TestDiscoverInterpreter(t *testing.T) {
+	defer func() { lookPath = exec.LookPath }()
+
+	fallback := []string{"python3.10", "/usr/bin/python3", "/usr/libexec/platform-python", "/usr/bin/python", "python"}
+	testCases := []struct {
+		mode          string
+		distroId      string
+		distroVersion string
+		installed     []string
+		expected      string
+		warns         bool
+	}{
+		{mode: DiscoveryAuto, distroId: "ubuntu", distroVersion: "22.04", installed: []string{"/usr/bin/python3.10", "/usr/bin/python3"}, expected: "/usr/bin/python3"},
+		{mode: DiscoveryAuto, distroId: "centos", distroVersion: "8", installed: []string{"/usr/bin/python3", "/usr/libexec/platform-python"}, expected: "/usr/libexec/platform-python"},
+		{mode: DiscoveryAuto, distroId: "debian", distroVersion: "9", installed: []string{"/usr/bin/python", "/usr/bin/python3"}, expected: "/usr/bin/python"},
+		{mode: DiscoveryAuto, distroId: "arch", distroVersion: "", installed: []string{"/usr/bin/python3.10", "/usr/bin/python3"}, expected: "/usr/bin/python3.10", warns: true},
+		{mode: DiscoveryAuto, distroId: "ubuntu", distroVersion: "20.04", installed: []string{"/usr/bin/python3.10"}, expected: "/usr/bin/python3.10", warns: true},
+		{mode: DiscoveryAutoLegacy, distroId: "ubuntu", distroVersion: "20.04", installed: []string{"/usr/bin/python3", "/usr/bin/python"}, expected: "/usr/bin/python", warns: true},
+	}
+
+	for _, testCase := range testCases {
+		installed := testCase.installed
+		lookPath = func(file string) (string, error) {
+			for _, p := range installed {
+				if p == file || path.Base(p) == file {
+					return p, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+
+		res, warnings, err := discoverInterpreter(testCase.mode, fallback, testCase.distroId, testCase.distroVersion)
+		if err != nil {
+			t.Fatal("on", testCase, "unexpected error", err)
+		}
+		if res != testCase.expected {
+			t.Error("on", testCase, "expected", testCase.expected, "got", res)
+		}
+		if (len(warnings) > 0) != testCase.warns {
+			t.Error("on", testCase, "unexpected warnings", warnings)
+		}
+	}
+
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if _, _, err := discoverInterpreter(DiscoveryAuto, fallback, "ubuntu", "22.04"); err == nil {
+		t.Error("expected error when no interpreter is installed")
+	}
+}
@@ -4,26 +4,33 @@ import (
 	"bufio"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"time"
 )
 
-func getPrimaryPythonInterpreter() string {
-	// TODO: possibly use python interpreter from meta vars
-	return "/usr/bin/python3"
-}
+// maxResponseSize limits the size of a single response line of the Python runtime.
+const maxResponseSize = 64 << 20
+
+// exitWaitTimeout is the time given to a failing executor to exit, so that its exit status can be reported.
+const exitWaitTimeout = 100 * time.Millisecond
 
 type PythonExecutor struct {
+	key     executorKey
 	cmd     *exec.Cmd
 	stdin   io.WriteCloser
 	scanner *bufio.Scanner
 	invalid bool
 	nextTag uint64
+	// exited is closed when the process exits, exitErr holds the result of waiting for it.
+	exited  chan struct{}
+	exitErr error
 }
 
-func newExecutor(runtimeZipPath string) (*PythonExecutor, error) {
-	cmd := exec.Command(getPrimaryPythonInterpreter(), "-m", "py_runtime.py_runtime")
+func newExecutor(runtimeZipPath string, interpreter string) (*PythonExecutor, error) {
+	cmd := exec.Command(interpreter, "-m", "py_runtime.py_runtime")
 	cmd.Env = os.Environ()
 	if pythonPath, ok := os.LookupEnv("PYTHONPATH"); ok {
 		cmd.Env = append(cmd.Env, "PYTHONPATH="+runtimeZipPath+":"+pythonPath)
@@ -45,19 +52,26 @@ func newExecutor(runtimeZipPath string) (*PythonExecutor, error) {
 	}
 
 	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(nil, maxResponseSize)
 
 	executor := &PythonExecutor{
+		key:     executorKey{interpreter: interpreter, runtimePath: runtimeZipPath},
 		cmd:     cmd,
 		stdin:   stdin,
 		scanner: scanner,
 		invalid: false,
 		nextTag: 1,
+		exited:  make(chan struct{}),
 	}
+	go func() {
+		executor.exitErr = cmd.Wait()
+		close(executor.exited)
+	}()
 
 	req := helloRequest{}
 	rsp := helloResponse{}
 	if err = executor.executeCommand(cmdHello, req, &rsp); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to start python executor %s: %w", interpreter, err)
 	}
 
 	return executor, nil
@@ -68,6 +82,29 @@ func (e *PythonExecutor) invalidate() {
 	_ = e.cmd.Process.Kill()
 }
 
+// alive reports whether the executor may still be used.
+func (e *PythonExecutor) alive() bool {
+	if e.invalid {
+		return false
+	}
+	select {
+	case <-e.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+// crashError adds the exit status of the process to the error, if the process died.
+func (e *PythonExecutor) crashError(err error) error {
+	select {
+	case <-e.exited:
+		return fmt.Errorf("python executor crashed (%v): %w", e.exitErr, err)
+	case <-time.After(exitWaitTimeout):
+		return err
+	}
+}
+
 func (e *PythonExecutor) Close() error {
 	e.invalidate()
 	return nil
@@ -81,6 +118,7 @@ func (e *PythonExecutor) writeLine(data any) error {
 	inJson = append(inJson, '\n')
 	write, err := e.stdin.Write(inJson)
 	if err != nil {
+		err = e.crashError(err)
 		e.invalidate()
 		return err
 	}
@@ -93,11 +131,12 @@ func (e *PythonExecutor) writeLine(data any) error {
 
 func (e *PythonExecutor) readLine(data any) error {
 	if !e.scanner.Scan() {
-		e.invalidate()
 		err := e.scanner.Err()
 		if err == nil {
 			err = errors.New("reached unexpected end of file when reading command output")
 		}
+		err = e.crashError(err)
+		e.invalidate()
 		return err
 	}
 	outJson := e.scanner.Bytes()
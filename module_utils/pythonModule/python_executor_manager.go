@@ -2,50 +2,123 @@ package pythonModule
 
 import (
 	"errors"
-	"github.com/scylladb/gosible/utils/osUtils"
-	"github.com/scylladb/gosible/utils/types"
 	"os"
-	"path"
+	"runtime"
+	"sync"
 )
 
 var ErrNoExecutorRuntime = errors.New("executor runtime is not available")
 
+// executorKey identifies the executors which may serve a request.
+type executorKey struct {
+	interpreter string
+	runtimePath string
+}
+
+// PythonExecutorManager keeps a pool of long-lived Python executors. Every executor runs one module at a time,
+// so the pool starts new executors, up to maxExecutors, to serve concurrent requests. Executors which crashed
+// are dropped from the pool and replaced by new ones on demand.
 type PythonExecutorManager struct {
-	current *PythonExecutor
+	mu           sync.Mutex
+	cond         *sync.Cond
+	idle         map[executorKey][]*PythonExecutor
+	running      int
+	maxExecutors int
+	// start is replaced in tests.
+	start func(key executorKey) (*PythonExecutor, error)
 }
 
 func NewExecutorManager() *PythonExecutorManager {
-	return &PythonExecutorManager{
-		current: nil,
+	m := &PythonExecutorManager{
+		idle:         make(map[executorKey][]*PythonExecutor),
+		maxExecutors: runtime.NumCPU(),
+		start: func(key executorKey) (*PythonExecutor, error) {
+			return newExecutor(key.runtimePath, key.interpreter)
+		},
 	}
+	m.cond = sync.NewCond(&m.mu)
+	return m
 }
 
-func getRuntimePath() (string, error) {
-	dir, err := osUtils.GetBinaryDir()
+// acquire returns an executor which must be given back with release.
+func (m *PythonExecutorManager) acquire(interpreter string, runtimeChecksum string) (*PythonExecutor, error) {
+	if interpreter == "" {
+		interpreter = DefaultInterpreter
+	}
+	runtimePath, err := getRuntimePath(runtimeChecksum)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return path.Join(dir, "py_runtime.zip"), nil
-}
+	if _, err := os.Stat(runtimePath); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoExecutorRuntime
+	}
+	key := executorKey{interpreter: interpreter, runtimePath: runtimePath}
 
-func (m *PythonExecutorManager) getExecutor(_ types.Vars) (*PythonExecutor, error) {
-	if m.current != nil && !m.current.invalid {
-		return m.current, nil
+	m.mu.Lock()
+	for {
+		if executor := m.popIdle(key); executor != nil {
+			m.mu.Unlock()
+			return executor, nil
+		}
+		if m.running < m.maxExecutors {
+			break
+		}
+		if !m.evictIdle() {
+			m.cond.Wait()
+		}
 	}
+	m.running++
+	m.mu.Unlock()
 
-	runtimePath, err := getRuntimePath()
+	executor, err := m.start(key)
 	if err != nil {
+		m.mu.Lock()
+		m.running--
+		m.cond.Signal()
+		m.mu.Unlock()
 		return nil, err
 	}
+	executor.key = key
+	return executor, nil
+}
 
-	if _, err := os.Stat(runtimePath); errors.Is(err, os.ErrNotExist) {
-		return nil, ErrNoExecutorRuntime
+// release returns the executor to the pool. Executors which are no longer usable are dropped.
+func (m *PythonExecutorManager) release(executor *PythonExecutor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if executor.alive() {
+		m.idle[executor.key] = append(m.idle[executor.key], executor)
+	} else {
+		_ = executor.Close()
+		m.running--
 	}
+	m.cond.Signal()
+}
 
-	executor, err := newExecutor(runtimePath)
-	if err != nil {
-		return nil, err
+// popIdle returns a working idle executor, dropping the ones which exited in the meantime.
+func (m *PythonExecutorManager) popIdle(key executorKey) *PythonExecutor {
+	for idle := m.idle[key]; len(idle) > 0; idle = m.idle[key] {
+		executor := idle[len(idle)-1]
+		m.idle[key] = idle[:len(idle)-1]
+		if executor.alive() {
+			return executor
+		}
+		_ = executor.Close()
+		m.running--
+	}
+	return nil
+}
+
+// evictIdle stops an idle executor of another interpreter or runtime, making room for a new one.
+func (m *PythonExecutorManager) evictIdle() bool {
+	for key, idle := range m.idle {
+		if len(idle) == 0 {
+			continue
+		}
+		_ = idle[0].Close()
+		m.idle[key] = idle[1:]
+		m.running--
+		return true
 	}
-	m.current = executor
-	return m.current, nil
+	return false
 }
@@ -0,0 +1,113 @@
+package pythonModule
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func newTestManager(t *testing.T, maxExecutors int) (*PythonExecutorManager, *int) {
+	started := 0
+	m := NewExecutorManager()
+	m.maxExecutors = maxExecutors
+	m.start = func(key executorKey) (*PythonExecutor, error) {
+		cmd := exec.Command("sleep", "60")
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		started++
+		e := &PythonExecutor{key: key, cmd: cmd, exited: make(chan struct{})}
+		go func() {
+			e.exitErr = cmd.Wait()
+			close(e.exited)
+		}()
+		t.Cleanup(func() { _ = e.Close() })
+		return e, nil
+	}
+	return m, &started
+}
+
+func saveTestRuntime(t *testing.T) string {
+	t.Setenv("HOME", t.TempDir())
+	data := []byte("runtime")
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	if err := SaveRuntimeData(data, "bad"); err == nil {
+		t.Fatal("expected checksum mismatch")
+	}
+	if err := SaveRuntimeData(data, checksum); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	return checksum
+}
+
+func TestExecutorPool(t *testing.T) {
+	m, started := newTestManager(t, 2)
+	if _, err := m.acquire("", "missing"); !errors.Is(err, ErrNoExecutorRuntime) {
+		t.Fatal("expected", ErrNoExecutorRuntime, "got", err)
+	}
+	checksum := saveTestRuntime(t)
+
+	first, err := m.acquire("", checksum)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	second, err := m.acquire("", checksum)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if first == second || *started != 2 {
+		t.Fatal("expected two executors, started", *started)
+	}
+	m.release(first)
+	m.release(second)
+
+	reused, err := m.acquire("", checksum)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if *started != 2 {
+		t.Error("expected idle executor to be reused")
+	}
+
+	// An executor which crashed while idle is replaced.
+	m.release(reused)
+	for _, e := range m.idle[reused.key] {
+		_ = e.cmd.Process.Kill()
+		<-e.exited
+	}
+	if _, err = m.acquire("", checksum); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if *started != 3 {
+		t.Error("expected a new executor to be started, started", *started)
+	}
+}
+
+func TestExecutorPoolLimit(t *testing.T) {
+	m, _ := newTestManager(t, 1)
+	checksum := saveTestRuntime(t)
+
+	first, err := m.acquire("", checksum)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	acquired := make(chan *PythonExecutor)
+	go func() {
+		e, _ := m.acquire("", checksum)
+		acquired <- e
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected acquire to wait for a free executor")
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.release(first)
+	if e := <-acquired; e != first {
+		t.Error("expected released executor to be reused")
+	}
+}
@@ -2,12 +2,14 @@ package pythonModule
 
 import (
 	"errors"
+	"fmt"
 	"github.com/scylladb/gosible/modules"
 	"github.com/scylladb/gosible/utils/types"
 )
 
 type PythonExecutorGetter interface {
-	getExecutor(vars types.Vars) (*PythonExecutor, error)
+	acquire(interpreter string, runtimeChecksum string) (*PythonExecutor, error)
+	release(executor *PythonExecutor)
 }
 
 type PythonModule struct {
@@ -35,8 +37,9 @@ func (p PythonModule) Name() string {
 }
 
 func (p PythonModule) Run(ctx *modules.RunContext, vars types.Vars) *modules.Return {
-	if len(ctx.MetaArgs.PyRuntimeZipData) > 0 {
-		if err := SaveRuntimeData(ctx.MetaArgs.PyRuntimeZipData); err != nil {
+	checksum := ctx.MetaArgs.GetPyRuntimeChecksum()
+	if len(ctx.MetaArgs.GetPyRuntimeZipData()) > 0 {
+		if err := SaveRuntimeData(ctx.MetaArgs.PyRuntimeZipData, checksum); err != nil {
 			return makeErrorReturn(err)
 		}
 	}
@@ -46,12 +49,13 @@ func (p PythonModule) Run(ctx *modules.RunContext, vars types.Vars) *modules.Ret
 		Args:       vars,
 	}
 	rsp := executeModuleResponse{}
-	executor, err := p.executorGetter.getExecutor(vars)
+	executor, err := p.executorGetter.acquire(ctx.MetaArgs.GetPythonInterpreter(), checksum)
 	if err != nil {
 		return makeErrorReturn(err)
 	}
+	defer p.executorGetter.release(executor)
 	if err = executor.executeCommand(cmdExecuteModule, req, &rsp); err != nil {
-		return makeErrorReturn(err)
+		return makeErrorReturn(fmt.Errorf("while executing module %s: %w", p.name, err))
 	}
 	if rsp.Exception != nil {
 		return makeErrorReturn(errors.New(*rsp.Exception))
@@ -69,24 +73,3 @@ func makeErrorReturn(err error) *modules.Return {
 		},
 	}
 }
-
-func convertResult(pyRes map[string]interface{}) *modules.Return {
-	res := &modules.Return{}
-	if x, found := pyRes["changed"].(bool); found {
-		res.Changed = x
-		delete(pyRes, "changed")
-	}
-	if x, found := pyRes["failed"].(bool); found {
-		res.Failed = x
-		delete(pyRes, "failed")
-	}
-	if x, found := pyRes["msg"].(string); found {
-		res.Msg = x
-		delete(pyRes, "msg")
-	}
-
-	// TODO: Remaining properties, such as warnings, deprecations, debug logs.
-
-	res.ModuleSpecificReturn = pyRes
-	return res
-}
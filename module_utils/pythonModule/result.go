@@ -0,0 +1,124 @@
+package pythonModule
+
+import (
+	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/utils/types"
+)
+
+// convertResult maps the result of an Ansible module to the Return of Go modules. Keys without a counterpart
+// are kept in ModuleSpecificReturn.
+func convertResult(pyRes map[string]interface{}) *modules.Return {
+	res := &modules.Return{}
+	if pyRes == nil {
+		pyRes = make(map[string]interface{})
+	}
+	popBool(pyRes, "changed", &res.Changed)
+	popBool(pyRes, "failed", &res.Failed)
+	popBool(pyRes, "skipped", &res.Skipped)
+	popString(pyRes, "msg", &res.Msg)
+	popString(pyRes, "backup_file", &res.BackupFile)
+	if x, found := pyRes["rc"].(float64); found {
+		res.Rc = int(x)
+		delete(pyRes, "rc")
+	}
+	if x, found := pyRes["stdout"].(string); found {
+		res.Stdout = []byte(x)
+		delete(pyRes, "stdout")
+	}
+	if x, found := pyRes["stderr"].(string); found {
+		res.Stderr = []byte(x)
+		delete(pyRes, "stderr")
+	}
+	if x, found := pyRes["invocation"]; found {
+		res.Invocation = x
+		delete(pyRes, "invocation")
+	}
+	if x, found := pyRes["results"].([]interface{}); found {
+		res.Results = x
+		delete(pyRes, "results")
+	}
+	if diff, ok := convertDiff(pyRes["diff"]); ok {
+		res.Diff = diff
+		delete(pyRes, "diff")
+	}
+
+	internal := &modules.InternalReturn{}
+	if x, found := pyRes["ansible_facts"].(map[string]interface{}); found {
+		internal.AnsibleFacts = types.Facts(x)
+		delete(pyRes, "ansible_facts")
+	}
+	popString(pyRes, "exception", &internal.Exception)
+	internal.Warnings = popStrings(pyRes, "warnings")
+	internal.Debug = popStrings(pyRes, "debug")
+	if x, found := pyRes["deprecations"].([]interface{}); found {
+		internal.Deprecations = convertDeprecations(x)
+		delete(pyRes, "deprecations")
+	}
+	if internal.AnsibleFacts != nil || internal.Exception != "" || internal.Warnings != nil ||
+		internal.Debug != nil || internal.Deprecations != nil {
+		res.InternalReturn = internal
+	}
+
+	res.ModuleSpecificReturn = pyRes
+	return res
+}
+
+func popBool(pyRes map[string]interface{}, key string, dst *bool) {
+	if x, found := pyRes[key].(bool); found {
+		*dst = x
+		delete(pyRes, key)
+	}
+}
+
+func popString(pyRes map[string]interface{}, key string, dst *string) {
+	if x, found := pyRes[key].(string); found {
+		*dst = x
+		delete(pyRes, key)
+	}
+}
+
+func popStrings(pyRes map[string]interface{}, key string) []string {
+	list, found := pyRes[key].([]interface{})
+	if !found {
+		return nil
+	}
+	delete(pyRes, key)
+	ret := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// convertDiff maps the diff of a module. Modules may return a single diff or a list of them, only the former
+// is supported by Return.
+func convertDiff(raw interface{}) (*modules.Diff, bool) {
+	if list, ok := raw.([]interface{}); ok && len(list) == 1 {
+		raw = list[0]
+	}
+	diff, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return &modules.Diff{Before: diff["before"], After: diff["after"]}, true
+}
+
+func convertDeprecations(list []interface{}) []modules.Deprecation {
+	ret := make([]modules.Deprecation, 0, len(list))
+	for _, item := range list {
+		switch d := item.(type) {
+		case string:
+			ret = append(ret, modules.Deprecation{Msg: d})
+		case map[string]interface{}:
+			deprecation := modules.Deprecation{}
+			deprecation.Msg, _ = d["msg"].(string)
+			deprecation.Version, _ = d["version"].(string)
+			deprecation.Date, _ = d["date"].(string)
+			deprecation.CollectionName, _ = d["collection_name"].(string)
+			ret = append(ret, deprecation)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,71 @@
+package pythonModule
+
+import (
+	"encoding/json"
+	"github.com/scylladb/gosible/modules"
+	"reflect"
+	"testing"
+)
+
+func TestConvertResult(t *testing.T) {
+	const output = `{
+		"changed": true,
+		"rc": 2,
+		"stdout": "out",
+		"stderr": "err",
+		"skipped": false,
+		"invocation": {"module_args": {"name": "vim"}},
+		"ansible_facts": {"pkg_mgr": "apt"},
+		"warnings": ["first", "second"],
+		"deprecations": ["plain", {"msg": "detailed", "version": "2.14", "collection_name": "ansible.builtin"}],
+		"diff": [{"before": "a\n", "after": "b\n"}],
+		"cache_updated": true
+	}`
+	var pyRes map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &pyRes); err != nil {
+		t.Fatal(err)
+	}
+
+	res := convertResult(pyRes)
+	if !res.Changed || res.Failed || res.Skipped {
+		t.Error("on flags", res, "expected only changed to be set")
+	}
+	if res.Rc != 2 || string(res.Stdout) != "out" || string(res.Stderr) != "err" {
+		t.Error("on command output", res.Rc, string(res.Stdout), string(res.Stderr), "expected", 2, "out", "err")
+	}
+	if res.Invocation == nil {
+		t.Error("on invocation", res.Invocation, "expected it to be set")
+	}
+	if res.Diff == nil || res.Diff.Before != "a\n" || res.Diff.After != "b\n" {
+		t.Error("on diff", res.Diff, "expected", &modules.Diff{Before: "a\n", After: "b\n"})
+	}
+	if res.InternalReturn == nil {
+		t.Fatal("on internal return", res.InternalReturn, "expected it to be set")
+	}
+	if res.InternalReturn.AnsibleFacts["pkg_mgr"] != "apt" {
+		t.Error("on facts", res.InternalReturn.AnsibleFacts, "expected", map[string]string{"pkg_mgr": "apt"})
+	}
+	if expected := []string{"first", "second"}; !reflect.DeepEqual(res.InternalReturn.Warnings, expected) {
+		t.Error("on warnings", res.InternalReturn.Warnings, "expected", expected)
+	}
+	expectedDeprecations := []modules.Deprecation{
+		{Msg: "plain"},
+		{Msg: "detailed", Version: "2.14", CollectionName: "ansible.builtin"},
+	}
+	if !reflect.DeepEqual(res.InternalReturn.Deprecations, expectedDeprecations) {
+		t.Error("on deprecations", res.InternalReturn.Deprecations, "expected", expectedDeprecations)
+	}
+	if expected := map[string]interface{}{"cache_updated": true}; !reflect.DeepEqual(res.ModuleSpecificReturn, expected) {
+		t.Error("on module specific return", res.ModuleSpecificReturn, "expected", expected)
+	}
+}
+
+func TestConvertResultWithoutInternalReturn(t *testing.T) {
+	res := convertResult(map[string]interface{}{"failed": true, "msg": "boom"})
+	if !res.Failed || res.Msg != "boom" {
+		t.Error("on", res, "expected failed result with message")
+	}
+	if res.InternalReturn != nil {
+		t.Error("on internal return", res.InternalReturn, "expected nil")
+	}
+}
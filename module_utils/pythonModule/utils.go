@@ -1,11 +1,66 @@
 package pythonModule
 
-import "os"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/scylladb/gosible/utils/osUtils"
+	"os"
+	"path"
+)
 
-func SaveRuntimeData(data []byte) error {
-	path, err := getRuntimePath()
+// RuntimeCacheDir is the directory, relative to the home directory, keeping Python runtimes by their checksum.
+// Runtimes stored there survive reconnects and are shared by all remote executors of the user, they are removed
+// only by the remote garbage collection.
+const RuntimeCacheDir = ".cache/gosible_client/py_runtime"
+
+// getRuntimePath returns the path of the Python runtime with the given checksum. An empty checksum denotes the runtime
+// stored next to the executor binary, as sent by controllers not reporting the checksum.
+func getRuntimePath(checksum string) (string, error) {
+	if checksum == "" {
+		dir, err := osUtils.GetBinaryDir()
+		if err != nil {
+			return "", err
+		}
+		return path.Join(dir, "py_runtime.zip"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(home, RuntimeCacheDir, checksum+".zip"), nil
+}
+
+// SaveRuntimeData verifies the Python runtime against its checksum and atomically stores it.
+func SaveRuntimeData(data []byte, checksum string) error {
+	if checksum != "" {
+		sum := sha256.Sum256(data)
+		if actual := hex.EncodeToString(sum[:]); actual != checksum {
+			return fmt.Errorf("python runtime checksum mismatch, expected %s, got %s", checksum, actual)
+		}
+	}
+	runtimePath, err := getRuntimePath(checksum)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	if err = os.MkdirAll(path.Dir(runtimePath), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(path.Dir(runtimePath), ".py_runtime-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), runtimePath)
 }
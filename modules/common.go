@@ -60,4 +60,13 @@ type Module interface {
 
 type RunContext struct {
 	MetaArgs *proto.MetaArgs // List of meta arguments
+	Output   OutputStreamer  // Receives the output of the module while it runs, nil when the output isn't streamed.
+}
+
+// OutputStreamer receives the output of commands run by a module while the module is still running.
+// Passed data is only valid during the call.
+type OutputStreamer interface {
+	Stdout(data []byte)
+	Stderr(data []byte)
+	Progress(msg string)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/scylladb/gosible/modules/ping"
 	"github.com/scylladb/gosible/modules/pip"
 	"github.com/scylladb/gosible/modules/service"
+	"github.com/scylladb/gosible/modules/setup"
 	"github.com/scylladb/gosible/modules/shell"
 	"github.com/scylladb/gosible/modules/waitFor"
 )
@@ -23,6 +24,7 @@ func Register(reg *modules.ModuleRegistry) {
 	reg.RegisterModuleFn(toModFn(ping.New))
 	reg.RegisterModuleFn(toModFn(waitFor.New))
 	reg.RegisterModuleFn(toModFn(service.New))
+	reg.RegisterModuleFn(toModFn(setup.New))
 }
 
 func toModFn[T modules.Module](fn func() T) func() modules.Module {
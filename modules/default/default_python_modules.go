@@ -5,15 +5,44 @@ import (
 	"github.com/scylladb/gosible/modules"
 )
 
+// executorManager is shared by all Python modules, so that they are served by a single pool of executors.
+var executorManager = pythonModule.NewExecutorManager()
+
 func RegisterPython(reg *modules.ModuleRegistry) {
-	executorManager := pythonModule.NewExecutorManager()
-	reg.RegisterModuleFn(toPyModFn(executorManager, "get_url"))
-	reg.RegisterModuleFn(toPyModFn(executorManager, "setup"))
-	reg.RegisterModuleFn(toPyModFn(executorManager, "apt"))
-	reg.RegisterModuleFn(toPyModFn(executorManager, "apt_repository"))
-	reg.RegisterModuleFn(toPyModFn(executorManager, "apt_key"))
+	reg.RegisterModuleFn(toPyModFn("get_url"))
+	reg.RegisterModuleFn(toPyModFn("setup"))
+	reg.RegisterModuleFn(toPyModFn("apt"))
+	reg.RegisterModuleFn(toPyModFn("apt_repository"))
+	reg.RegisterModuleFn(toPyModFn("apt_key"))
+}
+
+// RegisterCollections registers the modules of the Ansible collections installed in the given collections paths.
+func RegisterCollections(reg *modules.ModuleRegistry, collectionsPaths []string) error {
+	found, err := pythonModule.LoadCollections(collectionsPaths)
+	if err != nil {
+		return err
+	}
+	for _, m := range found {
+		reg.RegisterModuleFn(toCollectionModFn(m.Fqcn))
+	}
+	return nil
 }
 
-func toPyModFn(executorManager *pythonModule.PythonExecutorManager, name string) func() modules.Module {
+// RegisterCollectionFallback makes the registry run any collection module by its FQCN. It is used by the remote
+// executor, which receives the collection content with the Python runtime.
+func RegisterCollectionFallback(reg *modules.ModuleRegistry) {
+	reg.RegisterFallback(func(name string) (modules.Module, bool) {
+		if !pythonModule.IsCollectionFqcn(name) {
+			return nil, false
+		}
+		return pythonModule.NewCollectionModule(executorManager, name), true
+	})
+}
+
+func toPyModFn(name string) func() modules.Module {
 	return func() modules.Module { return pythonModule.NewStandardModule(executorManager, name, "py_") } // TODO: remove prefix
 }
+
+func toCollectionModFn(fqcn string) func() modules.Module {
+	return func() modules.Module { return pythonModule.NewCollectionModule(executorManager, fqcn) }
+}
@@ -1,6 +1,8 @@
 package modules
 
 import (
+	"sort"
+
 	"github.com/scylladb/gosible/utils/fqcn"
 	"github.com/scylladb/gosible/utils/types"
 )
@@ -8,7 +10,8 @@ import (
 type ExecuteCallback func(vars types.Vars) *Return
 
 type ModuleRegistry struct {
-	modules map[string]func() Module
+	modules  map[string]func() Module
+	fallback func(name string) (Module, bool)
 }
 
 func NewRegistry() *ModuleRegistry {
@@ -24,10 +27,28 @@ func (r *ModuleRegistry) RegisterModuleFn(moduleFn func() Module) {
 	}
 }
 
+// RegisterFallback sets the function creating modules which are not registered by name.
+func (r *ModuleRegistry) RegisterFallback(fallback func(name string) (Module, bool)) {
+	r.fallback = fallback
+}
+
 func (r *ModuleRegistry) FindModule(name string) (Module, bool) {
 	module, ok := r.modules[name]
 	if ok {
 		return module(), ok
 	}
+	if r.fallback != nil {
+		return r.fallback(name)
+	}
 	return nil, ok
 }
+
+// Names returns the sorted names of all registered modules, including their internal FQCN aliases.
+func (r *ModuleRegistry) Names() []string {
+	names := make([]string, 0, len(r.modules))
+	for name := range r.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,254 @@
+package setup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/scylladb/gosible/utils/types"
+	"golang.org/x/sys/unix"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+func collectHardware(m *Module) (types.Facts, error) {
+	facts := make(types.Facts)
+	var errs []string
+	for _, collect := range []func(types.Facts) error{collectCPU, collectMemory, collectUptime} {
+		if err := collect(facts); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	facts["ansible_mounts"] = collectMounts(m)
+	if errs != nil {
+		return facts, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return facts, nil
+}
+
+func collectCPU(facts types.Facts) error {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	processor := make([]string, 0)
+	sockets := make(map[string]bool)
+	cores, siblings, vcpus := 0, 0, 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		switch k {
+		case "processor":
+			vcpus++
+			processor = append(processor, v)
+		case "vendor_id", "model name", "Processor", "cpu model":
+			processor = append(processor, v)
+		case "physical id":
+			sockets[v] = true
+		case "cpu cores":
+			cores, _ = strconv.Atoi(v)
+		case "siblings":
+			siblings, _ = strconv.Atoi(v)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	count := len(sockets)
+	if count == 0 {
+		count = 1
+	}
+	if cores == 0 {
+		cores = 1
+	}
+	threadsPerCore := 1
+	if siblings > 0 {
+		threadsPerCore = siblings / cores
+	}
+	if vcpus == 0 {
+		vcpus = count * cores * threadsPerCore
+	}
+	facts["ansible_processor"] = processor
+	facts["ansible_processor_count"] = count
+	facts["ansible_processor_cores"] = cores
+	facts["ansible_processor_threads_per_core"] = threadsPerCore
+	facts["ansible_processor_vcpus"] = vcpus
+	// NumCPU gives the processors usable by the process, like nproc.
+	facts["ansible_processor_nproc"] = runtime.NumCPU()
+	return nil
+}
+
+func collectMemory(facts types.Facts) error {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// The sizes are in kB.
+	mem := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		size, err := strconv.Atoi(fields[1])
+		if err == nil {
+			mem[strings.TrimSuffix(fields[0], ":")] = size / 1024
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	nocacheFree := mem["MemFree"] + mem["Buffers"] + mem["Cached"]
+	facts["ansible_memtotal_mb"] = mem["MemTotal"]
+	facts["ansible_memfree_mb"] = mem["MemFree"]
+	facts["ansible_swaptotal_mb"] = mem["SwapTotal"]
+	facts["ansible_swapfree_mb"] = mem["SwapFree"]
+	facts["ansible_memory_mb"] = map[string]interface{}{
+		"real": map[string]interface{}{
+			"total": mem["MemTotal"],
+			"free":  mem["MemFree"],
+			"used":  mem["MemTotal"] - mem["MemFree"],
+		},
+		"nocache": map[string]interface{}{
+			"free": nocacheFree,
+			"used": mem["MemTotal"] - nocacheFree,
+		},
+		"swap": map[string]interface{}{
+			"total":  mem["SwapTotal"],
+			"free":   mem["SwapFree"],
+			"used":   mem["SwapTotal"] - mem["SwapFree"],
+			"cached": mem["SwapCached"],
+		},
+	}
+	return nil
+}
+
+func collectUptime(facts types.Facts) error {
+	uptime, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(uptime))
+	if len(fields) == 0 {
+		return fmt.Errorf("unexpected /proc/uptime content")
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return err
+	}
+	facts["ansible_uptime_seconds"] = int(seconds)
+	return nil
+}
+
+// collectMounts returns the mounted devices and network filesystems. The sizes of the filesystems are not given if
+// statfs takes longer than gather_timeout, e.g. on an unresponsive NFS.
+func collectMounts(m *Module) []map[string]interface{} {
+	mounts := make([]map[string]interface{}, 0)
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return mounts
+	}
+	defer f.Close()
+
+	uuids := deviceUUIDs()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		device, mountPoint := unescapeMountField(fields[0]), unescapeMountField(fields[1])
+		if !strings.HasPrefix(device, "/") && !strings.Contains(device, ":/") {
+			continue
+		}
+		uuid, ok := uuids[device]
+		if !ok {
+			uuid = "N/A"
+		}
+		mount := map[string]interface{}{
+			"mount":   mountPoint,
+			"device":  device,
+			"fstype":  fields[2],
+			"options": fields[3],
+			"uuid":    uuid,
+		}
+		if stat, err := statfs(mountPoint, m); err == nil {
+			mount["size_total"] = stat.Blocks * uint64(stat.Bsize)
+			mount["size_available"] = stat.Bavail * uint64(stat.Bsize)
+			mount["block_size"] = stat.Bsize
+			mount["block_total"] = stat.Blocks
+			mount["block_available"] = stat.Bavail
+			mount["block_used"] = stat.Blocks - stat.Bfree
+			mount["inode_total"] = stat.Files
+			mount["inode_available"] = stat.Ffree
+			mount["inode_used"] = stat.Files - stat.Ffree
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts
+}
+
+func statfs(mountPoint string, m *Module) (*unix.Statfs_t, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.gatherTimeout())
+	defer cancel()
+	type result struct {
+		stat *unix.Statfs_t
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var stat unix.Statfs_t
+		err := unix.Statfs(mountPoint, &stat)
+		done <- result{&stat, err}
+	}()
+	select {
+	case r := <-done:
+		return r.stat, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// unescapeMountField replaces the octal escapes of /proc/mounts, e.g. \040 for a space.
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if c, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// deviceUUIDs returns the UUIDs of the devices by their paths.
+func deviceUUIDs() map[string]string {
+	uuids := make(map[string]string)
+	links, _ := filepath.Glob("/dev/disk/by-uuid/*")
+	for _, link := range links {
+		if device, err := filepath.EvalSymlinks(link); err == nil {
+			uuids[device] = filepath.Base(link)
+		}
+	}
+	return uuids
+}
@@ -0,0 +1,239 @@
+package setup
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/scylladb/gosible/utils/types"
+	"golang.org/x/sys/unix"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func collectNetwork(*Module) (types.Facts, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	facts := make(types.Facts)
+	details := make(map[string]map[string]interface{})
+	names := make([]string, 0, len(ifaces))
+	allIPv4, allIPv6 := make([]string, 0), make([]string, 0)
+	for _, iface := range ifaces {
+		info, ipv4s, ipv6s := interfaceFacts(iface)
+		for _, ip := range ipv4s {
+			if !strings.HasPrefix(ip, "127.") {
+				allIPv4 = append(allIPv4, ip)
+			}
+		}
+		for _, ip := range ipv6s {
+			if ip != "::1" {
+				allIPv6 = append(allIPv6, ip)
+			}
+		}
+		names = append(names, iface.Name)
+		details[iface.Name] = info
+		facts["ansible_"+factName(iface.Name)] = info
+	}
+	sort.Strings(names)
+	facts["ansible_interfaces"] = names
+	facts["ansible_all_ipv4_addresses"] = allIPv4
+	facts["ansible_all_ipv6_addresses"] = allIPv6
+	facts["ansible_default_ipv4"] = defaultRoute(readDefaultIPv4Route(), details, "ipv4")
+	facts["ansible_default_ipv6"] = defaultRoute(readDefaultIPv6Route(), details, "ipv6")
+	return facts, nil
+}
+
+// factName returns the name of the interface usable as a variable name, like Ansible does.
+func factName(iface string) string {
+	return strings.NewReplacer("-", "_", ":", "_", ".", "_").Replace(iface)
+}
+
+// interfaceFacts returns the facts of the interface, and its IPv4 and IPv6 addresses.
+func interfaceFacts(iface net.Interface) (map[string]interface{}, []string, []string) {
+	info := map[string]interface{}{
+		"device":  iface.Name,
+		"active":  iface.Flags&net.FlagUp != 0,
+		"mtu":     iface.MTU,
+		"type":    interfaceType(iface),
+		"promisc": promiscuous(iface.Name),
+	}
+	if len(iface.HardwareAddr) > 0 {
+		info["macaddress"] = iface.HardwareAddr.String()
+	}
+
+	addrs, _ := iface.Addrs()
+	var ipv4s, ipv6s []string
+	var secondaries []map[string]interface{}
+	ipv6 := make([]map[string]interface{}, 0)
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		prefix, _ := ipNet.Mask.Size()
+		if ip := ipNet.IP.To4(); ip != nil {
+			network := ip.Mask(ipNet.Mask)
+			broadcast := make(net.IP, len(network))
+			for i := range network {
+				broadcast[i] = network[i] | ^ipNet.Mask[len(ipNet.Mask)-len(network)+i]
+			}
+			ipv4 := map[string]interface{}{
+				"address":   ip.String(),
+				"netmask":   net.IP(ipNet.Mask[len(ipNet.Mask)-len(network):]).String(),
+				"network":   network.String(),
+				"broadcast": broadcast.String(),
+				"prefix":    strconv.Itoa(prefix),
+			}
+			if len(ipv4s) == 0 {
+				info["ipv4"] = ipv4
+			} else {
+				secondaries = append(secondaries, ipv4)
+			}
+			ipv4s = append(ipv4s, ip.String())
+			continue
+		}
+		ipv6 = append(ipv6, map[string]interface{}{
+			"address": ipNet.IP.String(),
+			"prefix":  strconv.Itoa(prefix),
+			"scope":   ipv6Scope(ipNet.IP),
+		})
+		ipv6s = append(ipv6s, ipNet.IP.String())
+	}
+	if secondaries != nil {
+		info["ipv4_secondaries"] = secondaries
+	}
+	if len(ipv6) > 0 {
+		info["ipv6"] = ipv6
+	}
+	return info, ipv4s, ipv6s
+}
+
+// promiscuous returns whether the interface is in promiscuous mode, by its IFF_PROMISC flag.
+func promiscuous(name string) bool {
+	flags, err := os.ReadFile("/sys/class/net/" + name + "/flags")
+	if err != nil {
+		return false
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(flags)), 0, 32)
+	return err == nil && value&unix.IFF_PROMISC != 0
+}
+
+func interfaceType(iface net.Interface) string {
+	if iface.Flags&net.FlagLoopback != 0 {
+		return "loopback"
+	}
+	base := "/sys/class/net/" + iface.Name
+	if _, err := os.Stat(base + "/bridge"); err == nil {
+		return "bridge"
+	}
+	if _, err := os.Stat(base + "/bonding"); err == nil {
+		return "bonding"
+	}
+	if len(iface.HardwareAddr) > 0 {
+		return "ether"
+	}
+	return "unknown"
+}
+
+func ipv6Scope(ip net.IP) string {
+	switch {
+	case ip.IsLoopback():
+		return "host"
+	case ip.IsLinkLocalUnicast():
+		return "link"
+	default:
+		return "global"
+	}
+}
+
+// route is the default route of an IP version.
+type route struct {
+	iface   string
+	gateway string
+}
+
+// defaultRoute returns the facts of the default route, which are the details of its interface for the IP version
+// with the interface and the gateway.
+func defaultRoute(r *route, details map[string]map[string]interface{}, version string) map[string]interface{} {
+	facts := make(map[string]interface{})
+	if r == nil {
+		return facts
+	}
+	info, ok := details[r.iface]
+	if !ok {
+		return facts
+	}
+	switch addr := info[version].(type) {
+	case map[string]interface{}:
+		for k, v := range addr {
+			facts[k] = v
+		}
+	case []map[string]interface{}:
+		for _, a := range addr {
+			if a["scope"] == "global" {
+				for k, v := range a {
+					facts[k] = v
+				}
+				break
+			}
+		}
+	}
+	for _, k := range []string{"macaddress", "mtu", "type"} {
+		if v, ok := info[k]; ok {
+			facts[k] = v
+		}
+	}
+	facts["interface"] = r.iface
+	facts["gateway"] = r.gateway
+	return facts
+}
+
+// readDefaultIPv4Route reads the default IPv4 route from /proc/net/route, whose addresses are little endian hex.
+func readDefaultIPv4Route() *route {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != 4 {
+			continue
+		}
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(gateway))
+		return &route{iface: fields[0], gateway: ip.String()}
+	}
+	return nil
+}
+
+// readDefaultIPv6Route reads the default IPv6 route from /proc/net/ipv6_route.
+func readDefaultIPv6Route() *route {
+	f, err := os.Open("/proc/net/ipv6_route")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[0] != strings.Repeat("0", 32) || fields[1] != "00" || fields[9] == "lo" {
+			continue
+		}
+		gateway, err := hex.DecodeString(fields[4])
+		if err != nil || len(gateway) != net.IPv6len {
+			continue
+		}
+		return &route{iface: fields[9], gateway: net.IP(gateway).String()}
+	}
+	return nil
+}
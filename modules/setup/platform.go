@@ -0,0 +1,169 @@
+package setup
+
+import (
+	"bufio"
+	"github.com/Showmax/go-fqdn"
+	"github.com/scylladb/gosible/utils/distro"
+	"github.com/scylladb/gosible/utils/sysInfo"
+	"github.com/scylladb/gosible/utils/types"
+	"golang.org/x/sys/unix"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+func collectPlatform(*Module) (types.Facts, error) {
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		return nil, err
+	}
+	machine := unix.ByteSliceToString(uname.Machine[:])
+	nodename := unix.ByteSliceToString(uname.Nodename[:])
+
+	facts := types.Facts{
+		"ansible_system":                 sysInfo.Platform(),
+		"ansible_kernel":                 unix.ByteSliceToString(uname.Release[:]),
+		"ansible_kernel_version":         unix.ByteSliceToString(uname.Version[:]),
+		"ansible_machine":                machine,
+		"ansible_nodename":               nodename,
+		"ansible_hostname":               strings.Split(nodename, ".")[0],
+		"ansible_architecture":           architecture(machine),
+		"ansible_userspace_bits":         strconv.Itoa(strconv.IntSize),
+		"ansible_userspace_architecture": userspaceArchitecture(machine),
+	}
+	name, err := fqdn.FqdnHostname()
+	if err != nil {
+		name = nodename
+	}
+	facts["ansible_fqdn"] = name
+	if i := strings.Index(name, "."); i >= 0 {
+		facts["ansible_domain"] = name[i+1:]
+	} else {
+		facts["ansible_domain"] = ""
+	}
+	for _, f := range []string{"/var/lib/dbus/machine-id", "/etc/machine-id"} {
+		if id, err := os.ReadFile(f); err == nil {
+			facts["ansible_machine_id"] = strings.TrimSpace(string(id))
+			break
+		}
+	}
+	return facts, nil
+}
+
+var i386Regexp = regexp.MustCompile(`^i[3-6]86$`)
+
+func architecture(machine string) string {
+	if i386Regexp.MatchString(machine) {
+		return "i386"
+	}
+	return machine
+}
+
+// userspaceArchitecture returns the architecture of the userspace, which may be 32-bit on a 64-bit kernel.
+func userspaceArchitecture(machine string) string {
+	if machine == "x86_64" && strconv.IntSize == 32 {
+		return "i386"
+	}
+	return architecture(machine)
+}
+
+// distributionNames map the IDs of the os-release file to the names of the distributions given by Ansible.
+var distributionNames = map[string]string{
+	"almalinux":           "AlmaLinux",
+	"alpine":              "Alpine",
+	"amzn":                "Amazon",
+	"arch":                "Archlinux",
+	"centos":              "CentOS",
+	"debian":              "Debian",
+	"fedora":              "Fedora",
+	"gentoo":              "Gentoo",
+	"linuxmint":           "Linux Mint",
+	"ol":                  "OracleLinux",
+	"opensuse-leap":       "openSUSE Leap",
+	"opensuse-tumbleweed": "openSUSE Tumbleweed",
+	"rhel":                "RedHat",
+	"rocky":               "Rocky",
+	"sles":                "SLES",
+	"ubuntu":              "Ubuntu",
+}
+
+// osFamilies map the distributions to their families, like Ansible's OS_FAMILY_MAP.
+var osFamilies = map[string]string{
+	"AlmaLinux":           "RedHat",
+	"Amazon":              "RedHat",
+	"CentOS":              "RedHat",
+	"Fedora":              "RedHat",
+	"OracleLinux":         "RedHat",
+	"RedHat":              "RedHat",
+	"Rocky":               "RedHat",
+	"Debian":              "Debian",
+	"Linux Mint":          "Debian",
+	"Ubuntu":              "Debian",
+	"SLES":                "Suse",
+	"openSUSE Leap":       "Suse",
+	"openSUSE Tumbleweed": "Suse",
+	"Archlinux":           "Archlinux",
+	"Alpine":              "Alpine",
+	"Gentoo":              "Gentoo",
+}
+
+func collectDistribution(*Module) (types.Facts, error) {
+	if runtime.GOOS != "linux" {
+		platform := sysInfo.Platform()
+		return types.Facts{"ansible_distribution": platform, "ansible_os_family": platform}, nil
+	}
+
+	name := distributionName(distro.Id())
+	version := sysInfo.DistributionVersion()
+	osRelease := readOSRelease()
+	release := osRelease["VERSION_CODENAME"]
+	if release == "" {
+		release = osRelease["UBUNTU_CODENAME"]
+	}
+	if release == "" {
+		release = "NA"
+	}
+	family, ok := osFamilies[name]
+	if !ok {
+		family = name
+	}
+	return types.Facts{
+		"ansible_distribution":               name,
+		"ansible_distribution_version":       version,
+		"ansible_distribution_major_version": strings.Split(version, ".")[0],
+		"ansible_distribution_release":       release,
+		"ansible_os_family":                  family,
+	}, nil
+}
+
+func distributionName(id string) string {
+	if name, ok := distributionNames[id]; ok {
+		return name
+	}
+	if id == "" {
+		return "OtherLinux"
+	}
+	return strings.ToUpper(id[:1]) + id[1:]
+}
+
+// readOSRelease returns the variables of the os-release file, or none if it can't be read.
+func readOSRelease() map[string]string {
+	vars := make(map[string]string)
+	for _, name := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if k, v, ok := strings.Cut(scanner.Text(), "="); ok {
+				vars[k] = strings.Trim(v, `"'`)
+			}
+		}
+		break
+	}
+	return vars
+}
@@ -0,0 +1,214 @@
+package setup
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/module_utils/gosibleModule"
+	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/utils/maps"
+	"github.com/scylladb/gosible/utils/slices"
+	"github.com/scylladb/gosible/utils/types"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Module gathers the facts of the host natively, like Ansible's setup module. The facts are named like in Ansible,
+// e.g. ansible_distribution or ansible_default_ipv4.
+type Module struct {
+	*gosibleModule.GosibleModule[*Params]
+}
+
+type Params struct {
+	// GatherSubset and Filter are given as lists or comma separated strings.
+	GatherSubset  interface{} `mapstructure:"gather_subset"`
+	GatherTimeout int         `mapstructure:"gather_timeout"`
+	Filter        interface{} `mapstructure:"filter"`
+
+	subset  []string
+	filters []string
+}
+
+const defaultGatherTimeout = 10
+
+func (p *Params) Validate() error {
+	var err error
+	if p.subset, err = toStrings(p.GatherSubset, "gather_subset"); err != nil {
+		return err
+	}
+	if len(p.subset) == 0 {
+		p.subset = []string{"all"}
+	}
+	if p.filters, err = toStrings(p.Filter, "filter"); err != nil {
+		return err
+	}
+	if p.GatherTimeout <= 0 {
+		p.GatherTimeout = defaultGatherTimeout
+	}
+	return nil
+}
+
+func toStrings(value interface{}, name string) ([]string, error) {
+	var items []string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		items = strings.Split(v, ",")
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s item is not a string: %v", name, item)
+			}
+			items = append(items, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a list or a string", name)
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+var _ modules.Module = &Module{}
+
+func New() *Module {
+	return &Module{GosibleModule: gosibleModule.New(&Params{})}
+}
+
+func (m *Module) Name() string {
+	return "setup"
+}
+
+// collector returns the facts of a part of the system.
+type collector func(m *Module) (types.Facts, error)
+
+// collectors are named like the fact collectors of Ansible, so that they can be selected by gather_subset.
+var collectors = map[string]collector{
+	"date_time":    collectDateTime,
+	"distribution": collectDistribution,
+	"env":          collectEnv,
+	"hardware":     collectHardware,
+	"network":      collectNetwork,
+	"platform":     collectPlatform,
+	"python":       collectPython,
+	"service_mgr":  collectServiceMgr,
+	"user":         collectUser,
+}
+
+// minimalSubset are the collectors gathered unless excluded by name or by !min.
+var minimalSubset = []string{"date_time", "distribution", "env", "platform", "python", "service_mgr", "user"}
+
+// unsupportedSubsets are the Ansible fact collectors which are not implemented, they are accepted in gather_subset
+// but gather nothing.
+var unsupportedSubsets = []string{"apparmor", "caps", "cmdline", "dns", "facter", "fips", "local", "lsb", "ohai",
+	"pkg_mgr", "selinux", "ssh_pub_keys", "system", "virtual"}
+
+func (m *Module) Run(ctx *modules.RunContext, vars types.Vars) *modules.Return {
+	if err := m.ParseParams(ctx, vars); err != nil {
+		return m.MarkReturnFailed(err)
+	}
+	names, err := selectCollectors(m.Params.subset)
+	if err != nil {
+		return m.MarkReturnFailed(err)
+	}
+
+	facts := make(types.Facts)
+	for _, name := range names {
+		collected, err := collectors[name](m)
+		if err != nil {
+			m.Warn(fmt.Sprintf("error while collecting %s facts: %s", name, err))
+		}
+		for k, v := range collected {
+			facts[k] = v
+		}
+	}
+	facts = filterFacts(facts, m.Params.filters)
+	facts["ansible_gather_subset"] = m.Params.subset
+	facts["ansible_module_setup"] = true
+
+	if err = m.Close(); err != nil {
+		return m.MarkReturnFailed(err)
+	}
+	ret := m.GetReturn()
+	ret.AnsibleFacts = facts
+	return ret
+}
+
+// selectCollectors returns the sorted names of the collectors selected by gather_subset, like Ansible:
+// all and the names of collectors select them, prefixed with ! they exclude them. Without any selected collectors
+// all are gathered, unless !all is given. The minimal subset is always gathered, unless excluded by name or with !min.
+func selectCollectors(subset []string) ([]string, error) {
+	include, exclude := map[string]bool{}, map[string]bool{}
+	excludeAll := false
+	all := maps.Keys(collectors)
+	for _, s := range subset {
+		selected, excluded := include, strings.HasPrefix(s, "!")
+		if excluded {
+			s, selected = s[1:], exclude
+		}
+		switch {
+		case s == "all" && excluded:
+			excludeAll = true
+		case s == "all":
+			for _, name := range all {
+				include[name] = true
+			}
+		case s == "min":
+			for _, name := range minimalSubset {
+				selected[name] = true
+			}
+		case collectors[s] != nil:
+			selected[s] = true
+		case slices.Contains(unsupportedSubsets, s):
+		default:
+			valid := append([]string{"all", "min"}, all...)
+			sort.Strings(valid[2:])
+			return nil, fmt.Errorf("bad subset '%s' given, gather_subset options allowed: %s", s, strings.Join(valid, ", "))
+		}
+	}
+	if len(include) == 0 && !excludeAll {
+		for _, name := range all {
+			include[name] = true
+		}
+	}
+	for _, name := range minimalSubset {
+		include[name] = true
+	}
+
+	var names []string
+	for name := range include {
+		if !exclude[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// filterFacts returns the facts whose names match any of the shell-style patterns, all of them if there are none.
+func filterFacts(facts types.Facts, patterns []string) types.Facts {
+	if len(patterns) == 0 {
+		return facts
+	}
+	filtered := make(types.Facts)
+	for name, value := range facts {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				filtered[name] = value
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func (m *Module) gatherTimeout() time.Duration {
+	return time.Duration(m.Params.GatherTimeout) * time.Second
+}
@@ -0,0 +1,116 @@
+package setup
+
+import (
+	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/utils/types"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSelectCollectors(t *testing.T) {
+	all := []string{"date_time", "distribution", "env", "hardware", "network", "platform", "python", "service_mgr", "user"}
+	testCases := []struct {
+		subset   []string
+		expected []string
+	}{
+		{[]string{"all"}, all},
+		{[]string{"!all"}, minimalSubset},
+		{[]string{"min"}, minimalSubset},
+		{[]string{"network"}, []string{"date_time", "distribution", "env", "network", "platform", "python", "service_mgr", "user"}},
+		{[]string{"!hardware"}, []string{"date_time", "distribution", "env", "network", "platform", "python", "service_mgr", "user"}},
+		{[]string{"!all", "!min"}, nil},
+		{[]string{"!all", "!min", "network"}, []string{"network"}},
+		{[]string{"!all", "!env"}, []string{"date_time", "distribution", "platform", "python", "service_mgr", "user"}},
+		{[]string{"!all", "virtual"}, minimalSubset},
+	}
+	for _, tc := range testCases {
+		names, err := selectCollectors(tc.subset)
+		if err != nil {
+			t.Error("on", tc.subset, err)
+			continue
+		}
+		if !reflect.DeepEqual(names, tc.expected) {
+			t.Error("on", tc.subset, "expected", tc.expected, "got", names)
+		}
+	}
+
+	if _, err := selectCollectors([]string{"facts"}); err == nil {
+		t.Error("expected error for a bad subset")
+	}
+}
+
+func TestFilterFacts(t *testing.T) {
+	facts := types.Facts{"ansible_eth0": 1, "ansible_eth1": 2, "ansible_lo": 3, "ansible_mounts": 4}
+	testCases := []struct {
+		patterns []string
+		expected types.Facts
+	}{
+		{nil, facts},
+		{[]string{"ansible_eth*"}, types.Facts{"ansible_eth0": 1, "ansible_eth1": 2}},
+		{[]string{"ansible_lo", "ansible_mount?"}, types.Facts{"ansible_lo": 3, "ansible_mounts": 4}},
+		{[]string{"ansible_unknown"}, types.Facts{}},
+	}
+	for _, tc := range testCases {
+		if filtered := filterFacts(facts, tc.patterns); !reflect.DeepEqual(filtered, tc.expected) {
+			t.Error("on", tc.patterns, "expected", tc.expected, "got", filtered)
+		}
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	now := time.Date(2022, time.January, 2, 15, 4, 5, 123456000, time.UTC)
+	expected := map[string]interface{}{
+		"year":                "2022",
+		"month":               "01",
+		"weekday":             "Sunday",
+		"weekday_number":      "0",
+		"weeknumber":          "00",
+		"day":                 "02",
+		"hour":                "15",
+		"minute":              "04",
+		"second":              "05",
+		"epoch":               "1641135845",
+		"epoch_int":           "1641135845",
+		"date":                "2022-01-02",
+		"time":                "15:04:05",
+		"iso8601_micro":       "2022-01-02T15:04:05.123456Z",
+		"iso8601":             "2022-01-02T15:04:05Z",
+		"iso8601_basic":       "20220102T150405.123456",
+		"iso8601_basic_short": "20220102T150405",
+		"tz":                  "UTC",
+		"tz_dst":              "UTC",
+		"tz_offset":           "+0000",
+	}
+	if got := dateTime(now); !reflect.DeepEqual(got, expected) {
+		t.Error("expected", expected, "got", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the hardware and network facts are gathered only on Linux")
+	}
+	r := New().Run(&modules.RunContext{}, types.Vars{
+		"gather_subset": "!all,network,hardware",
+		"filter":        []interface{}{"ansible_distribution*", "ansible_[kms]*", "ansible_interfaces", "ansible_lo", "ansible_processor_*"},
+	})
+	if r.Failed {
+		t.Fatal("setup execution failed", r.Msg, r.Exception)
+	}
+	for _, name := range []string{"ansible_distribution", "ansible_kernel", "ansible_machine", "ansible_memtotal_mb",
+		"ansible_mounts", "ansible_interfaces", "ansible_lo", "ansible_processor_vcpus", "ansible_module_setup", "ansible_gather_subset"} {
+		if _, ok := r.AnsibleFacts[name]; !ok {
+			t.Error("expected fact", name)
+		}
+	}
+	for _, name := range []string{"ansible_env", "ansible_user_id", "ansible_date_time"} {
+		if _, ok := r.AnsibleFacts[name]; ok {
+			t.Error("expected fact", name, "to be filtered out")
+		}
+	}
+	if lo := r.AnsibleFacts["ansible_lo"].(map[string]interface{}); lo["type"] != "loopback" {
+		t.Error("expected the lo interface to be a loopback, got", lo)
+	}
+}
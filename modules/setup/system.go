@@ -0,0 +1,191 @@
+package setup
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/scylladb/gosible/module_utils/gosibleModule"
+	"github.com/scylladb/gosible/utils/sysInfo"
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func collectServiceMgr(m *Module) (types.Facts, error) {
+	return types.Facts{"ansible_service_mgr": serviceMgr(m)}, nil
+}
+
+func serviceMgr(m *Module) string {
+	if runtime.GOOS == "darwin" {
+		return "launchd"
+	}
+	if systemd, _ := sysInfo.IsSystemdManaged(m.GosibleModule); systemd {
+		return "systemd"
+	}
+	if comm, err := os.ReadFile("/proc/1/comm"); err == nil {
+		name := strings.TrimSpace(string(comm))
+		switch name {
+		case "systemd", "openrc", "runit", "upstart":
+			return name
+		case "init":
+			if _, err := os.Stat("/sbin/openrc"); err == nil {
+				return "openrc"
+			}
+			if _, err := os.Stat("/etc/init.d"); err == nil {
+				return "sysvinit"
+			}
+		}
+	}
+	return "service"
+}
+
+func collectEnv(*Module) (types.Facts, error) {
+	env := make(map[string]interface{})
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return types.Facts{"ansible_env": env}, nil
+}
+
+func collectUser(*Module) (types.Facts, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	uid, _ := strconv.Atoi(u.Uid)
+	gid, _ := strconv.Atoi(u.Gid)
+	return types.Facts{
+		"ansible_user_id":            u.Username,
+		"ansible_user_uid":           uid,
+		"ansible_user_gid":           gid,
+		"ansible_user_gecos":         u.Name,
+		"ansible_user_dir":           u.HomeDir,
+		"ansible_user_shell":         userShell(u.Username),
+		"ansible_real_user_id":       os.Getuid(),
+		"ansible_effective_user_id":  os.Geteuid(),
+		"ansible_real_group_id":      os.Getgid(),
+		"ansible_effective_group_id": os.Getegid(),
+	}, nil
+}
+
+// userShell returns the login shell of the user from /etc/passwd, as os/user doesn't give it.
+func userShell(name string) string {
+	f, err := os.Open("/etc/passwd")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) == 7 && fields[0] == name {
+			return fields[6]
+		}
+	}
+	return ""
+}
+
+const pythonInfoScript = `import json, platform, sys
+print(json.dumps({"executable": sys.executable, "type": platform.python_implementation(),
+    "version_info": list(sys.version_info)}))`
+
+// collectPython gathers the facts of the Python interpreter used by the Python modules. Hosts without Python have no
+// Python facts.
+func collectPython(m *Module) (types.Facts, error) {
+	interpreter := m.MetaArgs.GetPythonInterpreter()
+	if interpreter == "" {
+		interpreter = "python3"
+	}
+	if !filepath.IsAbs(interpreter) {
+		path, err := m.GetBinPath(interpreter, nil, false)
+		if err != nil || path == "" {
+			return nil, nil
+		}
+		interpreter = path
+	} else if _, err := os.Stat(interpreter); err != nil {
+		return nil, nil
+	}
+	res, err := m.RunCommand([]string{interpreter, "-c", pythonInfoScript}, gosibleModule.RunCommandDefaultKwargs())
+	if err != nil {
+		return nil, err
+	}
+	if res.Rc != 0 {
+		return nil, fmt.Errorf("%s exited with %d: %s", interpreter, res.Rc, strings.TrimSpace(string(res.Stderr)))
+	}
+	var info struct {
+		Executable  string        `json:"executable"`
+		Type        string        `json:"type"`
+		VersionInfo []interface{} `json:"version_info"`
+	}
+	if err = json.Unmarshal(res.Stdout, &info); err != nil {
+		return nil, err
+	}
+	if len(info.VersionInfo) != 5 {
+		return nil, fmt.Errorf("unexpected Python version %v", info.VersionInfo)
+	}
+	version := map[string]interface{}{
+		"major":        info.VersionInfo[0],
+		"minor":        info.VersionInfo[1],
+		"micro":        info.VersionInfo[2],
+		"releaselevel": info.VersionInfo[3],
+		"serial":       info.VersionInfo[4],
+	}
+	return types.Facts{
+		"ansible_python": map[string]interface{}{
+			"executable":   info.Executable,
+			"type":         info.Type,
+			"version":      version,
+			"version_info": info.VersionInfo,
+		},
+		"ansible_python_version": fmt.Sprintf("%v.%v.%v", info.VersionInfo[0], info.VersionInfo[1], info.VersionInfo[2]),
+	}, nil
+}
+
+func collectDateTime(*Module) (types.Facts, error) {
+	return types.Facts{"ansible_date_time": dateTime(time.Now())}, nil
+}
+
+func dateTime(now time.Time) map[string]interface{} {
+	utc := now.UTC()
+	// The week number is like strftime's %W, the weeks start on Monday and the first one on the first Monday.
+	week := (now.YearDay() + 6 - (int(now.Weekday())+6)%7) / 7
+	return map[string]interface{}{
+		"year":                now.Format("2006"),
+		"month":               now.Format("01"),
+		"weekday":             now.Format("Monday"),
+		"weekday_number":      strconv.Itoa(int(now.Weekday())),
+		"weeknumber":          fmt.Sprintf("%02d", week),
+		"day":                 now.Format("02"),
+		"hour":                now.Format("15"),
+		"minute":              now.Format("04"),
+		"second":              now.Format("05"),
+		"epoch":               strconv.FormatInt(now.Unix(), 10),
+		"epoch_int":           strconv.FormatInt(now.Unix(), 10),
+		"date":                now.Format("2006-01-02"),
+		"time":                now.Format("15:04:05"),
+		"iso8601_micro":       utc.Format("2006-01-02T15:04:05.000000Z"),
+		"iso8601":             utc.Format("2006-01-02T15:04:05Z"),
+		"iso8601_basic":       now.Format("20060102T150405.000000"),
+		"iso8601_basic_short": now.Format("20060102T150405"),
+		"tz":                  now.Format("MST"),
+		"tz_dst":              dstZone(now),
+		"tz_offset":           now.Format("-0700"),
+	}
+}
+
+// dstZone returns the name of the daylight saving time zone of the year, the standard zone if there is none.
+func dstZone(now time.Time) string {
+	january, januaryOffset := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location()).Zone()
+	july, julyOffset := time.Date(now.Year(), time.July, 1, 0, 0, 0, 0, now.Location()).Zone()
+	if januaryOffset > julyOffset {
+		return january
+	}
+	return july
+}
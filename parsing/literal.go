@@ -0,0 +1,221 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseLiteral converts a Python literal, as accepted by Python's ast.literal_eval, to a Go value: None, booleans,
+// numbers, quoted strings, lists, tuples and dicts. Tuples become lists, dicts become map[interface{}]interface{}
+// like the maps produced by the YAML parser. Returns an error if s is not a literal.
+func ParseLiteral(s string) (interface{}, error) {
+	p := &literalParser{s: s}
+	p.skipSpace()
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.s[p.pos:], p.pos)
+	}
+	return v, nil
+}
+
+type literalParser struct {
+	s   string
+	pos int
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of literal")
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *literalParser) value() (interface{}, error) {
+	if p.pos >= len(p.s) {
+		return nil, errUnexpectedEnd
+	}
+	switch c := p.s[p.pos]; {
+	case c == '[':
+		return p.sequence(']')
+	case c == '(':
+		return p.sequence(')')
+	case c == '{':
+		return p.dict()
+	case c == '\'' || c == '"':
+		return p.str()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	default:
+		return p.name()
+	}
+}
+
+func (p *literalParser) sequence(closing byte) (interface{}, error) {
+	p.pos++
+	items := make([]interface{}, 0)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, errUnexpectedEnd
+		}
+		if p.s[p.pos] == closing {
+			p.pos++
+			return items, nil
+		}
+		item, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if err = p.separator(closing); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *literalParser) dict() (interface{}, error) {
+	p.pos++
+	dict := make(map[interface{}]interface{})
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, errUnexpectedEnd
+		}
+		if p.s[p.pos] == '}' {
+			p.pos++
+			return dict, nil
+		}
+		key, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		switch key.(type) {
+		case []interface{}, map[interface{}]interface{}:
+			return nil, fmt.Errorf("unhashable dict key at position %d", p.pos)
+		}
+		p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] != ':' {
+			return nil, fmt.Errorf("expected ':' at position %d", p.pos)
+		}
+		p.pos++
+		p.skipSpace()
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		dict[key] = value
+		if err = p.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the ',' after an item. The closing bracket is left to the caller.
+func (p *literalParser) separator(closing byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return errUnexpectedEnd
+	}
+	switch p.s[p.pos] {
+	case ',':
+		p.pos++
+		return nil
+	case closing:
+		return nil
+	}
+	return fmt.Errorf("expected ',' or %q at position %d", closing, p.pos)
+}
+
+func (p *literalParser) str() (interface{}, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.s):
+			b.WriteString(unescape(p.s[p.pos+1]))
+			p.pos += 2
+		case c == '\n':
+			return nil, errors.New("unterminated string")
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, errUnexpectedEnd
+}
+
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case 'r':
+		return "\r"
+	case '0':
+		return "\x00"
+	case '\\', '\'', '"':
+		return string(c)
+	}
+	return "\\" + string(c)
+}
+
+func (p *literalParser) number() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("+-0123456789abcdefoxABCDEFOX._", p.s[p.pos]) >= 0 {
+		// Signs are part of the number only at its start or in an exponent.
+		if c := p.s[p.pos]; (c == '+' || c == '-') && p.pos > start && p.s[p.pos-1] != 'e' && p.s[p.pos-1] != 'E' {
+			break
+		}
+		p.pos++
+	}
+	text := p.s[start:p.pos]
+	if i, err := strconv.ParseInt(text, 0, 64); err == nil && !isLegacyOctal(text) {
+		return int(i), nil
+	}
+	if !strings.ContainsAny(text, ".eE") || strings.ContainsAny(text, "xXoObB") {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	if f, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid number %q", text)
+}
+
+// isLegacyOctal reports whether the number has leading zeros, which Python 3 rejects.
+func isLegacyOctal(text string) bool {
+	text = strings.TrimLeft(text, "+-")
+	return len(text) > 1 && text[0] == '0' && strings.Trim(text, "0_") != "" && unicode.IsDigit(rune(text[1]))
+}
+
+func (p *literalParser) name() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.s) && (unicode.IsLetter(rune(p.s[p.pos])) || p.s[p.pos] == '_') {
+		p.pos++
+	}
+	switch name := p.s[start:p.pos]; name {
+	case "None":
+		return nil, nil
+	case "True":
+		return true, nil
+	case "False":
+		return false, nil
+	case "":
+		return nil, fmt.Errorf("unexpected %q at position %d", p.s[start], start)
+	default:
+		return nil, fmt.Errorf("%s is not a literal", name)
+	}
+}
@@ -0,0 +1,56 @@
+package parsing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLiteral(t *testing.T) {
+	testCases := []struct {
+		s        string
+		expected interface{}
+		err      bool
+	}{
+		{s: "42", expected: 42},
+		{s: "-7", expected: -7},
+		{s: "0x1f", expected: 31},
+		{s: "1_000", expected: 1000},
+		{s: "00", expected: 0},
+		{s: "007", err: true},
+		{s: "3.14", expected: 3.14},
+		{s: "1e3", expected: 1000.0},
+		{s: "True", expected: true},
+		{s: "False", expected: false},
+		{s: "None", expected: nil},
+		{s: "true", err: true},
+		{s: "'quoted'", expected: "quoted"},
+		{s: `"with \"escapes\"\n"`, expected: "with \"escapes\"\n"},
+		{s: "[1, 'a', [True]]", expected: []interface{}{1, "a", []interface{}{true}}},
+		{s: "(1, 2,)", expected: []interface{}{1, 2}},
+		{s: "[]", expected: []interface{}{}},
+		{s: "{'a': 1, 2: [None]}", expected: map[interface{}]interface{}{"a": 1, 2: []interface{}{nil}}},
+		{s: " [1] ", expected: []interface{}{1}},
+		{s: "54.86.186.22", err: true},
+		{s: "centos", err: true},
+		{s: "-o StrictHostKeyChecking=no", err: true},
+		{s: "[1, 2", err: true},
+		{s: "{[1]: 2}", err: true},
+		{s: "'a' 'b'", err: true},
+		{s: "", err: true},
+	}
+
+	for _, testCase := range testCases {
+		res, err := ParseLiteral(testCase.s)
+		if testCase.err {
+			if err == nil {
+				t.Error("on", testCase.s, "expected error, got", res)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error("on", testCase.s, "unexpected error", err)
+		} else if !reflect.DeepEqual(res, testCase.expected) {
+			t.Errorf("on %q expected %#v, got %#v", testCase.s, testCase.expected, res)
+		}
+	}
+}
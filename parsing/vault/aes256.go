@@ -0,0 +1,105 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// The AES256 vault cipher: keys are derived with PBKDF2-SHA256 from the password and a random salt, the padded
+// plaintext is encrypted with AES-256-CTR and authenticated with HMAC-SHA256. The body is the hex encoded salt,
+// HMAC and ciphertext, separated by newlines.
+const (
+	saltLength    = 32
+	keyLength     = 32
+	ivLength      = 16
+	kdfIterations = 10000
+)
+
+var errHmacMismatch = errors.New("HMAC verification failed")
+
+func deriveKeys(password, salt []byte) (cipherKey, hmacKey, iv []byte) {
+	derived := pbkdf2.Key(password, salt, kdfIterations, 2*keyLength+ivLength, sha256.New)
+	return derived[:keyLength], derived[keyLength : 2*keyLength], derived[2*keyLength:]
+}
+
+func encryptAES256(plaintext, password []byte) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	cipherKey, hmacKey, iv := deriveKeys(password, salt)
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	padded := pkcs7Pad(plaintext, aes.BlockSize)
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, padded)
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+
+	return bytes.Join([][]byte{
+		[]byte(hex.EncodeToString(salt)),
+		[]byte(hex.EncodeToString(mac.Sum(nil))),
+		[]byte(hex.EncodeToString(ciphertext)),
+	}, []byte("\n")), nil
+}
+
+func decryptAES256(body, password []byte) ([]byte, error) {
+	parts := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+	if len(parts) != 3 {
+		return nil, errors.New("vault format error: invalid AES256 body")
+	}
+	salt, err := hex.DecodeString(string(parts[0]))
+	if err != nil {
+		return nil, err
+	}
+	expectedMac, err := hex.DecodeString(string(parts[1]))
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := hex.DecodeString(string(parts[2]))
+	if err != nil {
+		return nil, err
+	}
+	cipherKey, hmacKey, iv := deriveKeys(password, salt)
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+	if !hmac.Equal(mac.Sum(nil), expectedMac) {
+		return nil, errHmacMismatch
+	}
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	padded := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, iv).XORKeyStream(padded, ciphertext)
+	return pkcs7Unpad(padded, aes.BlockSize)
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+	return append(append([]byte{}, data...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, errors.New("vault format error: invalid padding")
+	}
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) || !bytes.Equal(data[len(data)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, errors.New("vault format error: invalid padding")
+	}
+	return data[:len(data)-n], nil
+}
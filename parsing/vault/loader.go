@@ -0,0 +1,83 @@
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	yamlv3 "gopkg.in/yaml.v3"
+	"sync"
+)
+
+// loaded holds the secrets given on the command line, used to decrypt the files and values read by the parsers.
+var loaded = struct {
+	sync.RWMutex
+	secrets []Secret
+}{}
+
+// SetSecrets sets the secrets used by DecryptFile and DecryptYAML.
+func SetSecrets(secrets []Secret) {
+	loaded.Lock()
+	defer loaded.Unlock()
+	loaded.secrets = secrets
+}
+
+// Secrets returns the secrets set by SetSecrets.
+func Secrets() []Secret {
+	loaded.RLock()
+	defer loaded.RUnlock()
+	return loaded.secrets
+}
+
+// DecryptFile returns the plaintext of vault encrypted file contents. Other data is returned as is.
+func DecryptFile(data []byte, path string) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	plaintext, _, err := Decrypt(data, Secrets())
+	if err != nil {
+		return nil, fmt.Errorf("while decrypting %s: %w", path, err)
+	}
+	return plaintext, nil
+}
+
+// vaultTag is the YAML tag of inline vault encrypted values.
+const vaultTag = "!vault"
+
+// DecryptYAML replaces the `!vault` tagged scalars of the YAML document with their plaintext. Other strings are left
+// as they are, even if they look like vault envelopes, as in Ansible.
+func DecryptYAML(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(vaultTag)) {
+		return data, nil
+	}
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		// Invalid documents are reported by their parsers.
+		return data, nil
+	}
+	found, err := decryptNode(&doc)
+	if err != nil || !found {
+		return data, err
+	}
+	return yamlv3.Marshal(&doc)
+}
+
+// decryptNode decrypts the tagged scalars of the node and its children, and reports whether there were any.
+func decryptNode(node *yamlv3.Node) (bool, error) {
+	if node.Kind == yamlv3.ScalarNode && node.Tag == vaultTag {
+		plaintext, _, err := Decrypt([]byte(node.Value), Secrets())
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		// The plaintext is always a string, even if it looks like a number.
+		node.Value, node.Tag, node.Style = string(plaintext), "!!str", yamlv3.DoubleQuotedStyle
+		return true, nil
+	}
+	found := false
+	for _, child := range node.Content {
+		ok, err := decryptNode(child)
+		if err != nil {
+			return false, err
+		}
+		found = found || ok
+	}
+	return found, nil
+}
@@ -0,0 +1,150 @@
+// Package vault implements Ansible Vault, which encrypts whole files and single values with the AES256 cipher.
+package vault
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// HeaderPrefix starts every vault envelope.
+const HeaderPrefix = "$ANSIBLE_VAULT"
+
+// DefaultID is the vault ID of secrets given without a label.
+const DefaultID = "default"
+
+const (
+	cipherName = "AES256"
+	version11  = "1.1"
+	version12  = "1.2"
+	lineWidth  = 80
+	headerSep  = ";"
+)
+
+var ErrNoSecrets = errors.New("attempting to decrypt but no vault secrets found")
+
+// Secret is a password used to encrypt and decrypt vault data, identified by its vault ID.
+type Secret struct {
+	ID       string
+	Password []byte
+}
+
+// IsEncrypted reports whether the data is a vault envelope.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte(HeaderPrefix+headerSep))
+}
+
+// Encrypt encrypts the plaintext with the secret. Secrets with a vault ID other than the default one produce
+// the 1.2 format, which records the ID in the header.
+func Encrypt(plaintext []byte, secret Secret) ([]byte, error) {
+	if len(secret.Password) == 0 {
+		return nil, errors.New("the vault password can't be empty")
+	}
+	body, err := encryptAES256(plaintext, secret.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{HeaderPrefix, version11, cipherName}
+	if secret.ID != "" && secret.ID != DefaultID {
+		header = []string{HeaderPrefix, version12, cipherName, secret.ID}
+	}
+	var buf bytes.Buffer
+	buf.WriteString(strings.Join(header, headerSep))
+	buf.WriteByte('\n')
+	hexBody := hex.EncodeToString(body)
+	for len(hexBody) > 0 {
+		n := lineWidth
+		if len(hexBody) < n {
+			n = len(hexBody)
+		}
+		buf.WriteString(hexBody[:n])
+		buf.WriteByte('\n')
+		hexBody = hexBody[n:]
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts the vault envelope with the first secret able to do it. Secrets matching the vault ID recorded
+// in the envelope are tried first. Returns the plaintext and the vault ID of the secret used.
+func Decrypt(data []byte, secrets []Secret) ([]byte, string, error) {
+	envelope, err := parseEnvelope(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(secrets) == 0 {
+		return nil, "", ErrNoSecrets
+	}
+
+	for _, secret := range orderSecrets(secrets, envelope.id) {
+		plaintext, err := decryptAES256(envelope.body, secret.Password)
+		if err == nil {
+			return plaintext, secret.ID, nil
+		}
+		if !errors.Is(err, errHmacMismatch) {
+			return nil, "", err
+		}
+	}
+	return nil, "", errors.New("decryption failed (no vault secrets were found that could decrypt)")
+}
+
+type envelope struct {
+	version string
+	id      string
+	body    []byte
+}
+
+func parseEnvelope(data []byte) (*envelope, error) {
+	data = bytes.TrimSpace(data)
+	headerEnd := bytes.IndexByte(data, '\n')
+	if headerEnd < 0 {
+		return nil, errors.New("vault data has no body")
+	}
+	header := strings.Split(strings.TrimSpace(string(data[:headerEnd])), headerSep)
+	if len(header) < 3 || header[0] != HeaderPrefix {
+		return nil, errors.New("input is not vault encrypted data")
+	}
+	e := &envelope{version: strings.TrimSpace(header[1])}
+	switch e.version {
+	case version11:
+	case version12:
+		if len(header) < 4 {
+			return nil, errors.New("vault format 1.2 requires a vault ID")
+		}
+		e.id = strings.TrimSpace(header[3])
+	default:
+		return nil, fmt.Errorf("unsupported vault format version %s", e.version)
+	}
+	if strings.TrimSpace(header[2]) != cipherName {
+		return nil, fmt.Errorf("unsupported vault cipher %s", header[2])
+	}
+
+	hexBody := strings.Join(strings.Fields(string(data[headerEnd+1:])), "")
+	body, err := hex.DecodeString(hexBody)
+	if err != nil {
+		return nil, fmt.Errorf("vault format error: %w", err)
+	}
+	e.body = body
+	return e, nil
+}
+
+// orderSecrets puts the secrets matching the vault ID first.
+func orderSecrets(secrets []Secret, id string) []Secret {
+	if id == "" {
+		return secrets
+	}
+	ordered := make([]Secret, 0, len(secrets))
+	for _, s := range secrets {
+		if s.ID == id {
+			ordered = append(ordered, s)
+		}
+	}
+	for _, s := range secrets {
+		if s.ID != id {
+			ordered = append(ordered, s)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,127 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// referenceVault was produced with a random salt by a script using Python's hashlib and hmac, and openssl enc
+// -aes-256-ctr, following the Ansible Vault 1.1 format with the password "gosible".
+const referenceVault = `$ANSIBLE_VAULT;1.1;AES256
+39333735313766313634333235323638653737346136306664643336376565613464323864353330
+6538643136646661376638323636386534303331326535380a363934646437306230336637396464
+36656230373238396564346265636439346537613939303464323631373463323230393834396630
+3037303865653836350a303834663864363335343866323961303030346437333132333534353239
+3263
+`
+
+func TestDecryptReference(t *testing.T) {
+	plaintext, id, err := Decrypt([]byte(referenceVault), []Secret{{ID: DefaultID, Password: []byte("gosible")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "secret: value\n" || id != DefaultID {
+		t.Error("on", string(plaintext), id, "expected", "secret: value\n", DefaultID)
+	}
+
+	if _, _, err = Decrypt([]byte(referenceVault), []Secret{{ID: DefaultID, Password: []byte("wrong")}}); err == nil {
+		t.Error("on", "wrong password", "expected error")
+	}
+	if _, _, err = Decrypt([]byte(referenceVault), nil); err != ErrNoSecrets {
+		t.Error("on", "no secrets", "expected", ErrNoSecrets, "got", err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	secrets := []Secret{{ID: "dev", Password: []byte("dev-pass")}, {ID: "prod", Password: []byte("prod-pass")}}
+	testCases := []struct {
+		secret Secret
+		header string
+	}{
+		{secret: Secret{ID: DefaultID, Password: []byte("dev-pass")}, header: "$ANSIBLE_VAULT;1.1;AES256\n"},
+		{secret: secrets[1], header: "$ANSIBLE_VAULT;1.2;AES256;prod\n"},
+	}
+
+	for _, testCase := range testCases {
+		for _, plaintext := range []string{"", "short", strings.Repeat("sixteen bytes!!!", 4)} {
+			encrypted, err := Encrypt([]byte(plaintext), testCase.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(encrypted, []byte(testCase.header)) {
+				t.Error("on", string(encrypted), "expected header", testCase.header)
+			}
+			for _, line := range strings.Split(string(encrypted), "\n") {
+				if len(line) > lineWidth {
+					t.Error("on", line, "expected lines no longer than", lineWidth)
+				}
+			}
+			decrypted, id, err := Decrypt(encrypted, secrets)
+			if err != nil {
+				t.Error("on", testCase.secret.ID, plaintext, "unexpected error", err)
+			} else if string(decrypted) != plaintext || id != secrets[0].ID && id != testCase.secret.ID {
+				t.Error("on", testCase.secret.ID, "expected", plaintext, "got", string(decrypted), id)
+			}
+		}
+	}
+}
+
+func TestDecryptYAML(t *testing.T) {
+	secret := Secret{ID: DefaultID, Password: []byte("gosible")}
+	SetSecrets([]Secret{secret})
+	defer SetSecrets(nil)
+
+	encrypted, err := Encrypt([]byte("s3cr3t"), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, err := Encrypt([]byte("1234"), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := "vars:\n  password: !vault |\n" + indent(string(encrypted), "    ") +
+		"  list:\n    - !vault |\n" + indent(string(encrypted), "      ") +
+		"  pin: !vault |\n" + indent(string(number), "    ") +
+		"  untagged: |\n" + indent(referenceVault, "    ") +
+		"  plain: text\n"
+	decrypted, err := DecryptYAML([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed yaml.MapSlice
+	if err = yaml.Unmarshal(decrypted, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	// Only tagged values are decrypted, like in Ansible.
+	expected := yaml.MapSlice{{Key: "vars", Value: yaml.MapSlice{
+		{Key: "password", Value: "s3cr3t"},
+		{Key: "list", Value: []interface{}{"s3cr3t"}},
+		{Key: "pin", Value: "1234"},
+		{Key: "untagged", Value: referenceVault},
+		{Key: "plain", Value: "text"},
+	}}}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Error("on", parsed, "expected", expected)
+	}
+
+	plain := []byte("vars:\n  untagged: |\n" + indent(referenceVault, "    "))
+	if res, err := DecryptYAML(plain); err != nil || !bytes.Equal(res, plain) {
+		t.Error("on", "untagged document", "expected it unchanged, got", string(res), err)
+	}
+	SetSecrets(nil)
+	if _, err = DecryptYAML([]byte(document)); !errors.Is(err, ErrNoSecrets) {
+		t.Error("on", "no secrets", "expected", ErrNoSecrets, "got", err)
+	}
+}
+
+func indent(s, prefix string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		b.WriteString(prefix + line + "\n")
+	}
+	return b.String()
+}
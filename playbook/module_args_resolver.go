@@ -138,7 +138,7 @@ func normalizeNewStyleArgs(action string, value interface{}) (types.Vars, error)
 		}
 	case string:
 		// form is like: copy: src=a dest=b
-		for k, v := range parsing.ParseKeyValuePairsString(v, isFreeformAction(action)) {
+		for k, v := range parsing.ParseKeyValuePairsString(v, IsFreeformAction(action)) {
 			args[k] = v
 		}
 	case nil:
@@ -149,7 +149,9 @@ func normalizeNewStyleArgs(action string, value interface{}) (types.Vars, error)
 	return args, nil
 }
 
-func isFreeformAction(action string) bool {
+// IsFreeformAction reports whether the action takes free-form arguments, e.g. the command of the command module.
+// These actions require arguments.
+func IsFreeformAction(action string) bool {
 	for _, a := range moduleRequireArgs {
 		if a == action {
 			return true
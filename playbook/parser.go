@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/scylladb/gosible/config"
 	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/parsing/vault"
 	playbookTypes "github.com/scylladb/gosible/playbook/types"
 	"github.com/scylladb/gosible/utils/types"
 	"gopkg.in/yaml.v2"
@@ -28,11 +29,13 @@ type parser struct {
 }
 
 type rawPlay struct {
-	Name     string
-	Hosts    string
-	Strategy string
-	Tasks    []yaml.MapSlice
-	Vars     yaml.MapSlice
+	Name         string
+	Hosts        string
+	Strategy     string
+	Tasks        []yaml.MapSlice
+	Vars         yaml.MapSlice
+	GatherFacts  *bool       `yaml:"gather_facts"`
+	GatherSubset interface{} `yaml:"gather_subset"`
 }
 
 func (p *parser) parseYAML(filename string) (*playbookTypes.Playbook, error) {
@@ -40,6 +43,12 @@ func (p *parser) parseYAML(filename string) (*playbookTypes.Playbook, error) {
 	if err != nil {
 		return nil, err
 	}
+	if dat, err = vault.DecryptFile(dat, filename); err != nil {
+		return nil, err
+	}
+	if dat, err = vault.DecryptYAML(dat); err != nil {
+		return nil, fmt.Errorf("while decrypting %s: %w", filename, err)
+	}
 	var plays []*rawPlay
 	err = yaml.Unmarshal(dat, &plays)
 	if err != nil {
@@ -68,18 +77,52 @@ func (p *parser) parseRawPlays(rawPlays []*rawPlay) (*playbookTypes.Playbook, er
 		if rawPlay.Strategy != "linear" && rawPlay.Strategy != "free" {
 			return nil, fmt.Errorf("strategy must be 'linear' or 'free'")
 		}
+		gatherSubset, err := parseGatherSubset(rawPlay.GatherSubset)
+		if err != nil {
+			return nil, err
+		}
 		playbook.Plays = append(playbook.Plays, &playbookTypes.Play{
 			Name:          strings.TrimSpace(rawPlay.Name),
 			HostsPattern:  rawPlay.Hosts,
 			Tasks:         tasks,
 			VarsTemplates: varsTemplates,
 			StrategyKey:   rawPlay.Strategy,
+			GatherFacts:   rawPlay.GatherFacts,
+			GatherSubset:  gatherSubset,
 		})
 	}
 
 	return &playbook, nil
 }
 
+// parseGatherSubset parses gather_subset, given as a list or as a comma separated string.
+func parseGatherSubset(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		var subset []string
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				subset = append(subset, s)
+			}
+		}
+		return subset, nil
+	case []interface{}:
+		subset := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("gather_subset item is not a string: %v", item)
+			}
+			subset = append(subset, s)
+		}
+		return subset, nil
+	default:
+		return nil, fmt.Errorf("gather_subset is not a list or a string")
+	}
+}
+
 func (p *parser) parseRawTasks(rawTasks []yaml.MapSlice) ([]*playbookTypes.Task, error) {
 	var tasks []*playbookTypes.Task
 	for _, rawTask := range rawTasks {
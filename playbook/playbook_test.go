@@ -81,3 +81,33 @@ func TestParseLoops(t *testing.T) {
 		t.Fatal("Expected loop template to be '{{ lookup('sequence', 'end=42 start=2 step=2') }}', got", play.Tasks[1].Loop.Template)
 	}
 }
+
+func TestParseGatherFacts(t *testing.T) {
+	mods := modules.NewRegistry()
+
+	pbook, err := Parse("tests/assets/gatherFacts.yml", mods)
+	if err != nil {
+		t.Fatal("Parsing error was not expected\n", err)
+	}
+	if len(pbook.Plays) != 3 {
+		t.Fatal("Expected 3 plays, got", len(pbook.Plays))
+	}
+
+	yes, no := true, false
+	expected := []struct {
+		gatherFacts  *bool
+		gatherSubset []string
+	}{
+		{nil, nil},
+		{&yes, []string{"!all", "network"}},
+		{&no, []string{"hardware"}},
+	}
+	for i, play := range pbook.Plays {
+		if !reflect.DeepEqual(play.GatherFacts, expected[i].gatherFacts) {
+			t.Error("on", play.Name, "expected gather_facts", expected[i].gatherFacts, "got", play.GatherFacts)
+		}
+		if !reflect.DeepEqual(play.GatherSubset, expected[i].gatherSubset) {
+			t.Error("on", play.Name, "expected gather_subset", expected[i].gatherSubset, "got", play.GatherSubset)
+		}
+	}
+}
@@ -20,6 +20,9 @@ type Play struct {
 	VarsTemplates types.Vars
 	Tasks         []*Task
 	StrategyKey   string
+	// GatherFacts is nil when gather_facts is not given, the facts are then gathered as configured by DEFAULT_GATHERING.
+	GatherFacts  *bool
+	GatherSubset []string
 	// TODO add roles support
 	// TODO add blocks support
 	// TODO add notify support
@@ -0,0 +1,186 @@
+package copyFile
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/plugins/action"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const Name = "copy"
+
+func New() *Action {
+	return &Action{action.New(&Params{FileParams: FileParams{Force: true}})}
+}
+
+type Action struct {
+	*action.Base[*Params]
+}
+
+func (a *Action) Run(ctx context.Context, actionCtx *plugins.ActionContext) *plugins.Return {
+	if err := a.ParseParams(actionCtx.Args); err != nil {
+		return a.MarkReturnFailed(err)
+	}
+
+	var content []byte
+	if a.Params.Content != nil {
+		content = []byte(*a.Params.Content)
+	} else {
+		src, err := action.FindNeedle(actionCtx.VarsEnv, "files", a.Params.Src)
+		if err != nil {
+			return a.MarkReturnFailed(err)
+		}
+		info, err := os.Stat(src)
+		if err != nil {
+			return a.MarkReturnFailed(err)
+		}
+		if info.IsDir() {
+			return a.MarkReturnFailed(fmt.Errorf("src %s is a directory, copying directories is not supported", src))
+		}
+		if content, err = os.ReadFile(src); err != nil {
+			return a.MarkReturnFailed(err)
+		}
+	}
+
+	ret, err := PutContent(ctx, actionCtx, content, filepath.Base(a.Params.Src), &a.Params.FileParams)
+	if err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	return a.UpdateReturn(ret)
+}
+
+type Params struct {
+	FileParams `mapstructure:",squash"`
+	Src        string `mapstructure:"src"`
+	// Content is nil when not given, an empty content creates an empty file.
+	Content *string `mapstructure:"content"`
+}
+
+func (p *Params) Validate() error {
+	if (p.Src == "") == (p.Content == nil) {
+		return errors.New("exactly one of src and content is required")
+	}
+	return p.FileParams.Validate()
+}
+
+// FileParams are the parameters of the actions writing a file on the host.
+type FileParams struct {
+	Dest  string      `mapstructure:"dest"`
+	Mode  interface{} `mapstructure:"mode"`
+	Owner string      `mapstructure:"owner"`
+	Group string      `mapstructure:"group"`
+	// Force replaces the file if its content differs, otherwise the file is written only if it doesn't exist.
+	Force bool `mapstructure:"force"`
+}
+
+func (p *FileParams) Validate() error {
+	if p.Dest == "" {
+		return errors.New("dest is required")
+	}
+	_, err := p.mode()
+	return err
+}
+
+// mode returns the permissions given either as a number or as an octal string, like "0644".
+func (p *FileParams) mode() (*uint32, error) {
+	var mode uint64
+	switch m := p.Mode.(type) {
+	case nil:
+		return nil, nil
+	case int:
+		mode = uint64(m)
+	case string:
+		var err error
+		if mode, err = strconv.ParseUint(m, 8, 32); err != nil {
+			return nil, fmt.Errorf("invalid mode %q, expected an octal number", m)
+		}
+	default:
+		return nil, fmt.Errorf("invalid mode %v", m)
+	}
+	if mode > 07777 {
+		return nil, fmt.Errorf("invalid mode %o", mode)
+	}
+	ret := uint32(mode)
+	return &ret, nil
+}
+
+// Return is the module specific return of the actions writing a file on the host.
+type Return struct {
+	Dest     string
+	Checksum string
+	Size     int64
+	Mode     string
+	Owner    string
+	Group    string
+}
+
+// PutContent writes the content to the file on the host with the attributes given by the params. The file is left
+// untouched if it already has them. If dest is a directory, the file named name is written in it.
+func PutContent(ctx context.Context, actionCtx *plugins.ActionContext, content []byte, name string, params *FileParams) (*plugins.Return, error) {
+	conn := actionCtx.Connection
+	if !conn.Agent.HasFeature(remote.FeatureFileTransfer) {
+		return nil, fmt.Errorf("remote executor version %s doesn't support file transfer", conn.Agent.Version)
+	}
+	mode, err := params.mode()
+	if err != nil {
+		return nil, err
+	}
+
+	dest := params.Dest
+	info, err := remote.StatFile(ctx, conn.RemoteExecutorClient, dest, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir {
+		if name == "" || name == "." {
+			return nil, fmt.Errorf("dest %s is a directory", dest)
+		}
+		dest = filepath.Join(dest, name)
+		if info, err = remote.StatFile(ctx, conn.RemoteExecutorClient, dest, true, true); err != nil {
+			return nil, err
+		}
+	}
+
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+	if info.Exists && (!params.Force || info.Checksum == checksum && hasAttributes(info, mode, params)) {
+		return &plugins.Return{ModuleSpecificReturn: newReturn(info)}, nil
+	}
+
+	attrs := &pb.FileAttributes{Path: dest, Mode: mode, Owner: params.Owner, Group: params.Group}
+	if info, err = remote.PutFile(ctx, conn.RemoteExecutorClient, bytes.NewReader(content), attrs); err != nil {
+		return nil, err
+	}
+	info.Checksum = checksum
+	return &plugins.Return{Changed: true, ModuleSpecificReturn: newReturn(info)}, nil
+}
+
+func hasAttributes(info *pb.FileInfo, mode *uint32, params *FileParams) bool {
+	if mode != nil && info.Mode != *mode {
+		return false
+	}
+	if params.Owner != "" && params.Owner != info.Owner && params.Owner != strconv.Itoa(int(info.Uid)) {
+		return false
+	}
+	return params.Group == "" || params.Group == info.Group || params.Group == strconv.Itoa(int(info.Gid))
+}
+
+func newReturn(info *pb.FileInfo) *Return {
+	return &Return{
+		Dest:     info.Path,
+		Checksum: info.Checksum,
+		Size:     info.Size,
+		Mode:     fmt.Sprintf("%04o", info.Mode),
+		Owner:    info.Owner,
+		Group:    info.Group,
+	}
+}
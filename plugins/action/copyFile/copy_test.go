@@ -0,0 +1,114 @@
+package copyFile
+
+import (
+	"context"
+	"errors"
+	"github.com/scylladb/gosible/connection"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"github.com/scylladb/gosible/testUtils"
+	"github.com/scylladb/gosible/utils/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	conn := &plugins.ConnectionContext{
+		RemoteExecutorClient: testUtils.NewFileTransferClient(t),
+		Host:                 &inventory.Host{Name: "host"},
+		Agent:                remote.NewAgentInfo(runtime.GOOS, runtime.GOARCH, nil),
+	}
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "dest")
+	src := filepath.Join(dir, "src.conf")
+	if err := os.WriteFile(src, []byte("from src"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	filesDest := filepath.Join(t.TempDir(), "dest")
+	if err := os.Mkdir(filepath.Join(dir, "files"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "files", "app.conf"), []byte("from files"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		args     types.Vars
+		failed   bool
+		changed  bool
+		path     string
+		expected string
+		mode     os.FileMode
+	}{
+		{"new file", types.Vars{"content": "a", "dest": dest, "mode": "0600"}, false, true, dest, "a", 0600},
+		{"same content", types.Vars{"content": "a", "dest": dest, "mode": "0600"}, false, false, dest, "a", 0600},
+		{"mode only", types.Vars{"content": "a", "dest": dest, "mode": 0640}, false, true, dest, "a", 0640},
+		{"kept mode", types.Vars{"content": "b", "dest": dest}, false, true, dest, "b", 0640},
+		{"no force", types.Vars{"content": "c", "dest": dest, "force": false}, false, false, dest, "b", 0640},
+		{"empty content", types.Vars{"content": "", "dest": dest}, false, true, dest, "", 0640},
+		{"src in files", types.Vars{"src": "app.conf", "dest": filesDest}, false, true, filesDest, "from files", 0644},
+		{"src in playbook dir", types.Vars{"src": "src.conf", "dest": filesDest}, false, true, filesDest, "from src", 0644},
+		{"missing relative src", types.Vars{"src": "missing.conf", "dest": dest}, true, false, "", "", 0},
+		{"missing parent", types.Vars{"src": src, "dest": dir + "/missing/dest"}, true, false, "", "", 0},
+		{"src into existing directory", types.Vars{"src": src, "dest": t.TempDir()}, false, true, "", "from src", 0644},
+		{"src and content", types.Vars{"src": src, "content": "a", "dest": dest}, true, false, "", "", 0},
+		{"bad mode", types.Vars{"content": "a", "dest": dest, "mode": "rw"}, true, false, "", "", 0},
+	}
+	for _, tc := range testCases {
+		actionCtx := &plugins.ActionContext{Connection: conn, Args: tc.args, VarsEnv: types.Vars{"playbook_dir": dir}}
+		r := New().Run(context.Background(), actionCtx)
+		if r.Failed != tc.failed {
+			t.Error("on", tc.name, "expected failed", tc.failed, "got", r.Failed, r.Exception)
+			continue
+		}
+		if r.Changed != tc.changed {
+			t.Error("on", tc.name, "expected changed", tc.changed, "got", r.Changed)
+		}
+		if tc.failed {
+			continue
+		}
+		path := tc.path
+		if path == "" {
+			path = filepath.Join(tc.args["dest"].(string), "src.conf")
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || string(content) != tc.expected {
+			t.Error("on", tc.name, "expected content", tc.expected, "got", string(content), err)
+		}
+		if st, err := os.Stat(path); err != nil || st.Mode().Perm() != tc.mode {
+			t.Error("on", tc.name, "expected mode", tc.mode, "got", st, err)
+		}
+	}
+}
+
+func TestCopyUnreachable(t *testing.T) {
+	dialer := func(context.Context, string) (net.Conn, error) { return nil, errors.New("connection refused") }
+	grpcConn, err := grpc.Dial("", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcConn.Close()
+	conn := &plugins.ConnectionContext{
+		RemoteExecutorClient: pb.NewGosibleClientClient(grpcConn),
+		Host:                 &inventory.Host{Name: "host"},
+		Agent:                remote.NewAgentInfo(runtime.GOOS, runtime.GOARCH, nil),
+	}
+
+	action := New()
+	r := action.Run(context.Background(), &plugins.ActionContext{Connection: conn, Args: types.Vars{"content": "a", "dest": "/tmp/dest"}})
+	if !r.Failed {
+		t.Fatal("expected copy to fail")
+	}
+	var failed plugins.FailedAction = action
+	if !connection.IsUnreachable(failed.Err()) {
+		t.Error("expected", failed.Err(), "to make the host unreachable")
+	}
+}
@@ -0,0 +1,131 @@
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/plugins/action"
+	"github.com/scylladb/gosible/remote"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const Name = "fetch"
+
+func New() *Action {
+	return &Action{action.New(&Params{FailOnMissing: true})}
+}
+
+type Action struct {
+	*action.Base[*Params]
+}
+
+// Run copies the file from the host to dest on the controller, by default to dest/<host>/<src>.
+func (a *Action) Run(ctx context.Context, actionCtx *plugins.ActionContext) *plugins.Return {
+	if err := a.ParseParams(actionCtx.Args); err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	conn := actionCtx.Connection
+	if !conn.Agent.HasFeature(remote.FeatureFileTransfer) {
+		return a.MarkReturnFailed(fmt.Errorf("remote executor version %s doesn't support file transfer", conn.Agent.Version))
+	}
+
+	info, err := remote.StatFile(ctx, conn.RemoteExecutorClient, a.Params.Src, true, true)
+	if err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	if !info.Exists || info.IsDir {
+		msg := "the remote file does not exist"
+		if info.IsDir {
+			msg = "remote file is a directory, fetch cannot work on directories"
+		}
+		if a.Params.FailOnMissing {
+			return a.MarkReturnFailed(errors.New(msg))
+		}
+		return a.UpdateReturn(&plugins.Return{Msg: msg + ", not transferring, ignored"})
+	}
+
+	dest := a.destPath(conn.Host.Name)
+	ret := &plugins.Return{ModuleSpecificReturn: &Return{Dest: dest, Checksum: info.Checksum, RemoteChecksum: info.Checksum}}
+	if sum, err := localChecksum(dest); err == nil && sum == info.Checksum {
+		return a.UpdateReturn(ret)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	if err = getFile(ctx, actionCtx, a.Params.Src, dest); err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	ret.Changed = true
+	return a.UpdateReturn(ret)
+}
+
+func (a *Action) destPath(host string) string {
+	if a.Params.Flat {
+		if strings.HasSuffix(a.Params.Dest, "/") {
+			return filepath.Join(a.Params.Dest, filepath.Base(a.Params.Src))
+		}
+		return a.Params.Dest
+	}
+	return filepath.Join(a.Params.Dest, host, a.Params.Src)
+}
+
+// getFile writes the file from the host to a temporary file next to dest and renames it, so that dest is replaced
+// only by intact content.
+func getFile(ctx context.Context, actionCtx *plugins.ActionContext, src, dest string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".gosible-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = remote.GetFile(ctx, actionCtx.Connection.RemoteExecutorClient, src, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+func localChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type Params struct {
+	Src  string `mapstructure:"src"`
+	Dest string `mapstructure:"dest"`
+	// Flat writes the file to dest itself, or into dest if it ends with a slash, instead of dest/<host>/<src>.
+	Flat          bool `mapstructure:"flat"`
+	FailOnMissing bool `mapstructure:"fail_on_missing"`
+}
+
+func (p *Params) Validate() error {
+	if p.Src == "" || p.Dest == "" {
+		return errors.New("src and dest are required")
+	}
+	return nil
+}
+
+type Return struct {
+	Dest           string
+	Checksum       string
+	RemoteChecksum string
+}
@@ -0,0 +1,59 @@
+package fetch
+
+import (
+	"context"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	"github.com/scylladb/gosible/testUtils"
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	conn := &plugins.ConnectionContext{
+		RemoteExecutorClient: testUtils.NewFileTransferClient(t),
+		Host:                 &inventory.Host{Name: "host"},
+		Agent:                remote.NewAgentInfo(runtime.GOOS, runtime.GOARCH, nil),
+	}
+	src := filepath.Join(t.TempDir(), "log")
+	if err := os.WriteFile(src, []byte("fetched"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+
+	testCases := []struct {
+		name    string
+		args    types.Vars
+		failed  bool
+		changed bool
+		path    string
+	}{
+		{"by host", types.Vars{"src": src, "dest": dest}, false, true, filepath.Join(dest, "host", src)},
+		{"unchanged", types.Vars{"src": src, "dest": dest}, false, false, filepath.Join(dest, "host", src)},
+		{"flat", types.Vars{"src": src, "dest": dest + "/log.txt", "flat": true}, false, true, filepath.Join(dest, "log.txt")},
+		{"flat into directory", types.Vars{"src": src, "dest": dest + "/", "flat": true}, false, true, filepath.Join(dest, "log")},
+		{"missing", types.Vars{"src": src + ".missing", "dest": dest}, true, false, ""},
+		{"missing ignored", types.Vars{"src": src + ".missing", "dest": dest, "fail_on_missing": false}, false, false, ""},
+		{"directory", types.Vars{"src": filepath.Dir(src), "dest": dest}, true, false, ""},
+	}
+	for _, tc := range testCases {
+		r := New().Run(context.Background(), &plugins.ActionContext{Connection: conn, Args: tc.args})
+		if r.Failed != tc.failed {
+			t.Error("on", tc.name, "expected failed", tc.failed, "got", r.Failed, r.Exception)
+			continue
+		}
+		if r.Changed != tc.changed {
+			t.Error("on", tc.name, "expected changed", tc.changed, "got", r.Changed)
+		}
+		if tc.path == "" {
+			continue
+		}
+		if content, err := os.ReadFile(tc.path); err != nil || string(content) != "fetched" {
+			t.Error("on", tc.name, "unexpected content", string(content), err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package action
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindNeedle returns the path of a file given to an action, like src of copy. Relative paths are looked up, like in
+// Ansible, in the subdirectory dirname of playbook_dir, e.g. files or templates, and then in playbook_dir itself.
+func FindNeedle(varsEnv types.Vars, dirname, needle string) (string, error) {
+	if filepath.IsAbs(needle) {
+		return needle, nil
+	}
+	baseDir, ok := varsEnv["playbook_dir"].(string)
+	if !ok || baseDir == "" {
+		baseDir = "."
+	}
+	candidates := []string{filepath.Join(baseDir, dirname, needle), filepath.Join(baseDir, needle)}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("could not find or access '%s', searched in: %s", needle, strings.Join(candidates, ", "))
+}
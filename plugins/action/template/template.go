@@ -0,0 +1,63 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/plugins/action"
+	"github.com/scylladb/gosible/plugins/action/copyFile"
+	"github.com/scylladb/gosible/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const Name = "template"
+
+func New() *Action {
+	return &Action{action.New(&Params{FileParams: copyFile.FileParams{Force: true}})}
+}
+
+type Action struct {
+	*action.Base[*Params]
+}
+
+// Run renders the template on the controller and writes the result to the host.
+func (a *Action) Run(ctx context.Context, actionCtx *plugins.ActionContext) *plugins.Return {
+	if err := a.ParseParams(actionCtx.Args); err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	src, err := action.FindNeedle(actionCtx.VarsEnv, "templates", a.Params.Src)
+	if err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	source, err := os.ReadFile(src)
+	if err != nil {
+		return a.MarkReturnFailed(err)
+	}
+
+	options := template.NewOptions().SetConvertData(false).SetCache(false).SetFailOnUndefined(true)
+	rendered, err := template.TemplateToString(string(source), actionCtx.VarsEnv, options)
+	if err != nil {
+		return a.MarkReturnFailed(err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(a.Params.Src), ".j2")
+	ret, err := copyFile.PutContent(ctx, actionCtx, []byte(rendered.(string)), name, &a.Params.FileParams)
+	if err != nil {
+		return a.MarkReturnFailed(err)
+	}
+	return a.UpdateReturn(ret)
+}
+
+type Params struct {
+	copyFile.FileParams `mapstructure:",squash"`
+	Src                 string `mapstructure:"src"`
+}
+
+func (p *Params) Validate() error {
+	if p.Src == "" {
+		return errors.New("src is required")
+	}
+	return p.FileParams.Validate()
+}
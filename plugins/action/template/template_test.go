@@ -0,0 +1,62 @@
+package template
+
+import (
+	"context"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/remote"
+	"github.com/scylladb/gosible/testUtils"
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	conn := &plugins.ConnectionContext{
+		RemoteExecutorClient: testUtils.NewFileTransferClient(t),
+		Host:                 &inventory.Host{Name: "host"},
+		Agent:                remote.NewAgentInfo(runtime.GOOS, runtime.GOARCH, nil),
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "templates", "app.conf.j2")
+	if err := os.Mkdir(filepath.Dir(src), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(src, []byte("port={{ port }}\nhost={{ inventory_hostname }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+
+	testCases := []struct {
+		name    string
+		src     string
+		failed  bool
+		changed bool
+	}{
+		{"rendered from templates", "app.conf.j2", false, true},
+		{"unchanged", src, false, false},
+		{"missing src", "missing.j2", true, false},
+	}
+	for _, tc := range testCases {
+		r := New().Run(context.Background(), &plugins.ActionContext{
+			Connection: conn,
+			Args:       types.Vars{"src": tc.src, "dest": dest},
+			VarsEnv:    types.Vars{"port": 9042, "inventory_hostname": "host", "playbook_dir": dir},
+		})
+		if r.Failed != tc.failed {
+			t.Error("on", tc.name, "expected failed", tc.failed, "got", r.Failed, r.Exception)
+			continue
+		}
+		if r.Changed != tc.changed {
+			t.Error("on", tc.name, "expected changed", tc.changed, "got", r.Changed)
+		}
+	}
+
+	// The .j2 suffix is dropped when writing into a directory.
+	content, err := os.ReadFile(filepath.Join(dest, "app.conf"))
+	if err != nil || string(content) != "port=9042\nhost=host\n" {
+		t.Error("unexpected rendered file", string(content), err)
+	}
+}
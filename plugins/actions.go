@@ -9,6 +9,12 @@ type Action interface {
 	Run(context.Context, *ActionContext) *Return
 }
 
+// FailedAction is implemented by the actions keeping the error their run failed with, so that the executor can tell
+// the host becoming unreachable apart from a failure of the task.
+type FailedAction interface {
+	Err() error
+}
+
 type ActionFn func() Action
 
 var actions = map[string]ActionFn{}
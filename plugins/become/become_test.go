@@ -0,0 +1,116 @@
+package become
+
+import (
+	"github.com/scylladb/gosible/plugins/become/repository"
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"strings"
+	"testing"
+)
+
+type testShell struct{}
+
+func (testShell) Echo() string              { return "echo" }
+func (testShell) CommandSep() string        { return ";" }
+func (testShell) Executable() string        { return "/bin/sh" }
+func (testShell) Exists(path string) string { return "test -e " + path }
+
+var _ shell.Shell = testShell{}
+
+type becomeCommandBuilder interface {
+	BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error)
+}
+
+func TestBuildBecomeCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		plugin   becomeCommandBuilder
+		prefix   string
+		contains []string
+		excludes []string
+	}{
+		{name: "sudo", plugin: NewSudo(&types.BecomeArgs{User: "root"}), prefix: "sudo -H -S -n  -u root /bin/sh -c "},
+		{name: "sudo with password", plugin: NewSudo(&types.BecomeArgs{User: "root", Password: "pass", Flags: "-H -Sn --non-interactive"}),
+			prefix: "sudo -H -S -p \"[sudo via ansible, key="},
+		{name: "doas", plugin: NewDoas(&types.BecomeArgs{User: "become"}), prefix: "doas  -n -u become /bin/sh -c 'echo BECOME-SUCCESS-"},
+		{name: "doas with password", plugin: NewDoas(&types.BecomeArgs{User: "become", Password: "pass"}), prefix: "doas  -u become /bin/sh -c 'echo BECOME-SUCCESS-"},
+		{name: "dzdo", plugin: NewDzdo(&types.BecomeArgs{User: "root"}), prefix: "dzdo -H -S -n -u root /bin/sh -c "},
+		{name: "dzdo with password", plugin: NewDzdo(&types.BecomeArgs{User: "root", Password: "pass"}), prefix: "dzdo -H -S  -p \"[dzdo via ansible, key=",
+			excludes: []string{"-n"}},
+		{name: "pbrun", plugin: NewPbrun(&types.BecomeArgs{User: "root", Flags: "-l"}), prefix: "pbrun -l -u root 'echo BECOME-SUCCESS-"},
+		{name: "machinectl", plugin: NewMachinectl(&types.BecomeArgs{User: "become"}), prefix: "machinectl -q shell  become@ /bin/sh -c "},
+	}
+
+	for _, testCase := range testCases {
+		cmd, _, err := testCase.plugin.BuildBecomeCommand("id", testShell{})
+		if err != nil {
+			t.Error("on", testCase.name, "unexpected error", err)
+			continue
+		}
+		if !strings.HasPrefix(cmd, testCase.prefix) {
+			t.Error("on", testCase.name, cmd, "expected prefix", testCase.prefix)
+		}
+		if !strings.Contains(cmd, "; id") {
+			t.Error("on", testCase.name, cmd, "expected to contain the command")
+		}
+		for _, s := range testCase.excludes {
+			if strings.Contains(cmd, s) {
+				t.Error("on", testCase.name, cmd, "expected not to contain", s)
+			}
+		}
+	}
+}
+
+func TestCheckPasswordPrompt(t *testing.T) {
+	testCases := []struct {
+		name     string
+		check    func([]byte) bool
+		output   string
+		expected bool
+	}{
+		{name: "doas", check: NewDoas(&types.BecomeArgs{}).CheckPasswordPrompt, output: "doas (sshtest@managed) password: ", expected: true},
+		{name: "doas other", check: NewDoas(&types.BecomeArgs{}).CheckPasswordPrompt, output: "BECOME-SUCCESS-abc", expected: false},
+		{name: "pbrun", check: NewPbrun(&types.BecomeArgs{}).CheckPasswordPrompt, output: "Password:", expected: true},
+		{name: "machinectl", check: NewMachinectl(&types.BecomeArgs{}).CheckPasswordPrompt, output: "Password: ", expected: true},
+		{name: "machinectl incorrect", check: NewMachinectl(&types.BecomeArgs{}).CheckIncorrectPassword,
+			output: "\x1B[0;1;31m==== AUTHENTICATION FAILED ====\x1B[0m", expected: true},
+		{name: "doas incorrect", check: NewDoas(&types.BecomeArgs{}).CheckIncorrectPassword, output: "doas: Permission denied", expected: true},
+		{name: "doas missing", check: NewDoas(&types.BecomeArgs{}).CheckMissingPassword, output: "doas: Authorization required", expected: true},
+		{name: "dzdo incorrect", check: NewDzdo(&types.BecomeArgs{}).CheckIncorrectPassword, output: "Sorry, try again.", expected: true},
+	}
+
+	for _, testCase := range testCases {
+		if res := testCase.check([]byte(testCase.output)); res != testCase.expected {
+			t.Error("on", testCase.name, "expected", testCase.expected, "got", res)
+		}
+	}
+}
+
+func TestMachinectlCheckSuccess(t *testing.T) {
+	m := NewMachinectl(&types.BecomeArgs{User: "become"})
+	if _, _, err := m.BuildBecomeCommand("id", testShell{}); err != nil {
+		t.Fatal(err)
+	}
+	output := []byte("\x1B[0;1;39m" + m.success + "\x1B[0m\n")
+	if !m.CheckSuccess(output) {
+		t.Error("on", string(output), "expected success")
+	}
+}
+
+func TestRequireTty(t *testing.T) {
+	args := &types.BecomeArgs{User: "become"}
+	testCases := []struct {
+		name     string
+		plugin   repository.BecomePlugin
+		expected bool
+	}{
+		{name: "sudo", plugin: NewSudo(args), expected: false},
+		{name: "su", plugin: NewSu(args), expected: false},
+		{name: "machinectl", plugin: NewMachinectl(args), expected: true},
+	}
+	for _, testCase := range testCases {
+		if testCase.plugin.RequireTty() != testCase.expected {
+			t.Error("on", testCase.name, "expected RequireTty", testCase.expected)
+		}
+	}
+}
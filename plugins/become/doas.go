@@ -0,0 +1,45 @@
+package become
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"regexp"
+	"strings"
+)
+
+type Doas struct {
+	Base
+}
+
+var doasPromptRe = regexp.MustCompile(`^(doas \(|Password:)`)
+
+func (d *Doas) CheckPasswordPrompt(output []byte) bool {
+	return doasPromptRe.Match(output)
+}
+
+func (d *Doas) BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error) {
+	d.BuildBecomeCmdHook()
+	if cmd == "" {
+		return cmd, 0, nil
+	}
+	// doas prompts are detected by CheckPasswordPrompt, the prompt is set just to expect one.
+	d.prompt = "doas ("
+
+	flags := d.getFlags("")
+	if pass, ok := d.GetOption("become_pass").(string); (!ok || pass == "") && !strings.Contains(flags, "-n") {
+		flags += " -n"
+	}
+	return fmt.Sprintf("%s %s %s %s -c %s", d.getExe(), flags, d.getUserArg(), shell.Executable(), d.buildSuccessCommand(cmd, shell, true)), 1, nil
+}
+
+func NewDoas(args *types.BecomeArgs) *Doas {
+	return &Doas{
+		Base{
+			args:    args,
+			name:    "doas",
+			fail:    []string{"Permission denied"},
+			missing: []string{"Authorization required"},
+		},
+	}
+}
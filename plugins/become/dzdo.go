@@ -0,0 +1,39 @@
+package become
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"strings"
+)
+
+// Dzdo runs commands with Centrify's dzdo, which is used like sudo.
+type Dzdo struct {
+	Base
+}
+
+const dzdoFlags = "-H -S -n"
+
+func (d *Dzdo) BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error) {
+	d.BuildBecomeCmdHook()
+	if cmd == "" {
+		return cmd, 0, nil
+	}
+	flags := d.getFlags(dzdoFlags)
+	if pass, ok := d.GetOption("become_pass").(string); ok && pass != "" {
+		d.prompt = fmt.Sprintf("[dzdo via ansible, key=%s] password:", d.id)
+		flags = fmt.Sprintf("%s -p \"%s\"", strings.ReplaceAll(flags, "-n", ""), d.prompt)
+	}
+	r := strings.Join([]string{d.getExe(), flags, d.getUserArg(), d.buildSuccessCommand(cmd, shell, false)}, " ")
+	return r, len(d.success) + 1, nil
+}
+
+func NewDzdo(args *types.BecomeArgs) *Dzdo {
+	return &Dzdo{
+		Base{
+			args: args,
+			name: "dzdo",
+			fail: []string{"Sorry, try again."},
+		},
+	}
+}
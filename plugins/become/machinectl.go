@@ -0,0 +1,53 @@
+package become
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"regexp"
+)
+
+// Machinectl runs commands in a systemd user session of become_user.
+type Machinectl struct {
+	Base
+}
+
+// ansiColorCodesRe matches the color codes polkit surrounds its messages with.
+var ansiColorCodesRe = regexp.MustCompile(`\x1B\[[0-9;]+m`)
+
+func removeAnsiCodes(output []byte) []byte {
+	return ansiColorCodesRe.ReplaceAll(output, nil)
+}
+
+func (m *Machinectl) CheckSuccess(output []byte) bool {
+	return m.Base.CheckSuccess(removeAnsiCodes(output))
+}
+
+func (m *Machinectl) CheckIncorrectPassword(output []byte) bool {
+	return m.Base.CheckIncorrectPassword(removeAnsiCodes(output))
+}
+
+func (m *Machinectl) CheckMissingPassword(output []byte) bool {
+	return m.Base.CheckMissingPassword(removeAnsiCodes(output))
+}
+
+func (m *Machinectl) BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error) {
+	m.BuildBecomeCmdHook()
+	if cmd == "" {
+		return cmd, 0, nil
+	}
+	user, _ := m.GetOption("become_user").(string)
+	return fmt.Sprintf("%s -q shell %s %s@ %s", m.getExe(), m.getFlags(""), user, m.buildSuccessCommand(cmd, shell, false)), 1, nil
+}
+
+func NewMachinectl(args *types.BecomeArgs) *Machinectl {
+	return &Machinectl{
+		Base{
+			args:       args,
+			name:       "machinectl",
+			prompt:     "Password: ",
+			fail:       []string{"==== AUTHENTICATION FAILED ===="},
+			requireTty: true,
+		},
+	}
+}
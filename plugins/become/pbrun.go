@@ -0,0 +1,33 @@
+package become
+
+import (
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"strings"
+)
+
+// Pbrun runs commands with PowerBroker's pbrun.
+type Pbrun struct {
+	Base
+}
+
+func (p *Pbrun) BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error) {
+	p.BuildBecomeCmdHook()
+	if cmd == "" {
+		return cmd, 0, nil
+	}
+	// pbrun runs the command with the shell itself, unless wrap_exe is set.
+	wrapExe, _ := p.GetOption("wrap_exe").(bool)
+	r := strings.Join([]string{p.getExe(), p.getFlags(""), p.getUserArg(), p.buildSuccessCommand(cmd, shell, !wrapExe)}, " ")
+	return r, 1, nil
+}
+
+func NewPbrun(args *types.BecomeArgs) *Pbrun {
+	return &Pbrun{
+		Base{
+			args:   args,
+			name:   "pbrun",
+			prompt: "Password:",
+		},
+	}
+}
@@ -16,7 +16,7 @@ type Base struct {
 	name       string
 	fail       []string // Message to detect prompted password being wrong.
 	missing    []string // Message to detect prompted password missing.
-	RequireTty bool
+	requireTty bool     // The command must run in a terminal.
 	prompt     string
 	success    string
 }
@@ -37,6 +37,34 @@ func (b *Base) GetOption(name string) interface{} {
 	}
 }
 
+// getExe returns become_exe, defaulting to the plugin name.
+func (b *Base) getExe() string {
+	if exe, ok := b.GetOption("become_exe").(string); ok && exe != "" {
+		return exe
+	}
+	return b.name
+}
+
+// getFlags returns become_flags, defaulting to the flags used by the plugin in Ansible.
+func (b *Base) getFlags(defaultFlags string) string {
+	if flags, ok := b.GetOption("become_flags").(string); ok && flags != "" {
+		return flags
+	}
+	return defaultFlags
+}
+
+// getUserArg returns the -u argument selecting become_user, if it is set.
+func (b *Base) getUserArg() string {
+	if user, ok := b.GetOption("become_user").(string); ok && user != "" {
+		return fmt.Sprintf("-u %s", user)
+	}
+	return ""
+}
+
+func (b *Base) RequireTty() bool {
+	return b.requireTty
+}
+
 func (b *Base) ExpectPrompt() bool {
 	return b.prompt != "" && b.GetOption("become_pass") != nil
 }
@@ -10,6 +10,8 @@ type BecomeBuildBecomeCommand func(cmd string, shell shell.Shell) (string, error
 type BecomePlugin interface {
 	BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error)
 	ExpectPrompt() bool
+	// RequireTty reports whether the become command only works in a terminal.
+	RequireTty() bool
 	CheckSuccess(output []byte) bool
 	CheckPasswordPrompt(output []byte) bool
 	CheckIncorrectPassword(output []byte) bool
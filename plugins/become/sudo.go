@@ -14,16 +14,17 @@ type Sudo struct {
 	Base
 }
 
+// sudoFlags are the default flags of sudo. -n makes sudo fail instead of waiting for a password which is not given.
+const sudoFlags = "-H -S -n"
+
+var sudoNonInteractiveRe = regexp.MustCompile(`^(-\w*)n(\w*.*)`)
+
 func (s *Sudo) BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, error) {
 	s.BuildBecomeCmdHook()
 	if cmd == "" {
 		return cmd, 0, nil
 	}
-	becomeCmd, ok := s.GetOption("become_exe").(string)
-	if !ok || becomeCmd == "" {
-		becomeCmd = s.name
-	}
-	flags, ok := s.GetOption("become_flags").(string)
+	flags := s.getFlags(sudoFlags)
 	prompt := ""
 	pass, ok := s.GetOption("become_pass").(string)
 	if ok && pass != "" {
@@ -32,26 +33,20 @@ func (s *Sudo) BuildBecomeCommand(cmd string, shell shell.Shell) (string, int, e
 		if err != nil {
 			return "", 0, err
 		}
-		reflag := make([]string, len(splitFlags))
-		re := regexp.MustCompile(`^(-\w*)n(\w*.*)`)
+		reflag := make([]string, 0, len(splitFlags))
 		for _, flag := range splitFlags {
 			if flag == "-n" || flag == "--non-interactive" {
 				continue
 			}
-			if strings.HasPrefix(flag, "--") {
-				re.ReplaceAllString(flag, "$1$2")
+			if !strings.HasPrefix(flag, "--") {
+				flag = sudoNonInteractiveRe.ReplaceAllString(flag, "$1$2")
 			}
 			reflag = append(reflag, flag)
 		}
 		flags = shellescape.QuoteCommand(reflag)
 		prompt = fmt.Sprintf("-p \"%s\"", s.prompt)
 	}
-	user, ok := s.GetOption("become_user").(string)
-	if ok && user != "" {
-		user = fmt.Sprintf("-u %s", user)
-	}
-	flags = fmt.Sprintf("-S %s", flags)
-	r := strings.Join([]string{becomeCmd, flags, prompt, user, s.buildSuccessCommand(cmd, shell, false)}, " ")
+	r := strings.Join([]string{s.getExe(), flags, prompt, s.getUserArg(), s.buildSuccessCommand(cmd, shell, false)}, " ")
 	return r, len(s.success) + 1, nil
 }
 
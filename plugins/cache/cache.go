@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/utils/fqcn"
+	"github.com/scylladb/gosible/utils/slices"
+	"github.com/scylladb/gosible/utils/types"
+	"time"
+)
+
+// Cache stores vars by key, like Ansible's cache plugins. It keeps the facts of hosts, keyed by host name,
+// and may keep them between runs.
+type Cache interface {
+	// Get returns the vars stored with the key, unless they expired.
+	Get(key string) (types.Vars, bool)
+	Set(key string, value types.Vars) error
+	Delete(key string) error
+	// Keys returns the keys of the vars which did not expire.
+	Keys() ([]string, error)
+	Flush() error
+}
+
+// Options configure a cache plugin, see CACHE_PLUGIN_CONNECTION, CACHE_PLUGIN_PREFIX and CACHE_PLUGIN_TIMEOUT.
+type Options struct {
+	// Connection is the location of the cache, e.g. the directory of the jsonfile plugin.
+	Connection string
+	// Prefix is prepended to the keys in the storage.
+	Prefix string
+	// Timeout is the time after which the stored vars expire, they never expire if it is 0.
+	Timeout time.Duration
+}
+
+type Factory func(options Options) (Cache, error)
+
+var plugins = map[string]Factory{
+	"memory":   newMemory,
+	"jsonfile": newJSONFile,
+}
+
+// RegisterCachePlugin makes the cache plugin available by the name in CACHE_PLUGIN.
+func RegisterCachePlugin(name string, factory Factory) {
+	plugins[name] = factory
+}
+
+// New returns the cache plugin with the name, which may be given as an ansible.builtin FQCN.
+func New(name string, options Options) (Cache, error) {
+	for n, factory := range plugins {
+		if slices.Contains(fqcn.ToInternalFcqns(n), name) {
+			return factory(options)
+		}
+	}
+	return nil, fmt.Errorf("unable to load the cache plugin %s", name)
+}
+
+// FromConfig returns the cache plugin selected by CACHE_PLUGIN, configured by the other CACHE_PLUGIN_* settings.
+func FromConfig() (Cache, error) {
+	settings := config.Manager().Settings
+	return New(settings.CACHE_PLUGIN, Options{
+		Connection: settings.CACHE_PLUGIN_CONNECTION,
+		Prefix:     settings.CACHE_PLUGIN_PREFIX,
+		Timeout:    time.Duration(settings.CACHE_PLUGIN_TIMEOUT) * time.Second,
+	})
+}
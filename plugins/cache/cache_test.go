@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func testCache(t *testing.T, name string, c Cache) {
+	facts := types.Vars{"ansible_hostname": "web1", "ansible_processor_cores": 4.0}
+	if _, ok := c.Get("web1"); ok {
+		t.Error("on", name, "expected no vars before they are set")
+	}
+	if err := c.Set("web1", facts); err != nil {
+		t.Fatal("on", name, err)
+	}
+	if err := c.Set("web2", types.Vars{}); err != nil {
+		t.Fatal("on", name, err)
+	}
+	if got, ok := c.Get("web1"); !ok || !reflect.DeepEqual(got, facts) {
+		t.Error("on", name, "expected", facts, "got", got)
+	}
+
+	keys, err := c.Keys()
+	sort.Strings(keys)
+	if err != nil || !reflect.DeepEqual(keys, []string{"web1", "web2"}) {
+		t.Error("on", name, "expected keys web1 and web2, got", keys, err)
+	}
+
+	if err = c.Delete("web1"); err != nil {
+		t.Error("on", name, err)
+	}
+	if _, ok := c.Get("web1"); ok {
+		t.Error("on", name, "expected no vars after they are deleted")
+	}
+	if err = c.Delete("unknown"); err != nil {
+		t.Error("on", name, "expected no error deleting an unknown key, got", err)
+	}
+
+	if err = c.Flush(); err != nil {
+		t.Error("on", name, err)
+	}
+	if keys, err = c.Keys(); err != nil || len(keys) != 0 {
+		t.Error("on", name, "expected no keys after flush, got", keys, err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	c, err := New("memory", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCache(t, "memory", c)
+}
+
+func TestJSONFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "facts")
+	options := Options{Connection: dir, Prefix: "gosible_", Timeout: time.Hour}
+	c, err := New("ansible.builtin.jsonfile", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCache(t, "jsonfile", c)
+
+	facts := types.Vars{"ansible_distribution": "Ubuntu", "ansible_local": map[string]interface{}{}}
+	if err = c.Set("db1", facts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "gosible_db1")); err != nil {
+		t.Error("expected the cache file of db1", err)
+	}
+
+	// Another run reads the vars from the files.
+	next, err := New("jsonfile", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := next.Get("db1"); !ok || !reflect.DeepEqual(got, facts) {
+		t.Error("expected", facts, "got", got)
+	}
+
+	// Vars stored longer than the timeout ago expire.
+	old := time.Now().Add(-2 * time.Hour)
+	if err = os.Chtimes(filepath.Join(dir, "gosible_db1"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	expired, err := New("jsonfile", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := expired.Get("db1"); ok {
+		t.Error("expected the vars to expire, got", got)
+	}
+	if keys, err := expired.Keys(); err != nil || len(keys) != 0 {
+		t.Error("expected no keys of expired vars, got", keys, err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New("redis", Options{}); err == nil {
+		t.Error("expected error for an unknown plugin")
+	}
+	if _, err := New("jsonfile", Options{}); err == nil {
+		t.Error("expected error for the jsonfile plugin without a connection")
+	}
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/scylladb/gosible/utils/display"
+	pathUtils "github.com/scylladb/gosible/utils/path"
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jsonFile keeps the vars of each key in a JSON file named <prefix><key> in the connection directory,
+// like Ansible's jsonfile cache plugin. Vars read or written are also kept in memory.
+type jsonFile struct {
+	dir     string
+	prefix  string
+	timeout time.Duration
+
+	data map[string]cachedVars
+	lock sync.Mutex
+}
+
+type cachedVars struct {
+	vars     types.Vars
+	storedAt time.Time
+}
+
+func newJSONFile(options Options) (Cache, error) {
+	if options.Connection == "" {
+		return nil, errors.New("the jsonfile cache plugin requires CACHE_PLUGIN_CONNECTION to be set to a writable directory path")
+	}
+	dir := pathUtils.UnfrackPath(options.Connection, pathUtils.UnfrackOptions{})
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error in the jsonfile cache plugin while trying to create the cache dir %s: %w", dir, err)
+	}
+	return &jsonFile{
+		dir:     dir,
+		prefix:  options.Prefix,
+		timeout: options.Timeout,
+		data:    make(map[string]cachedVars),
+	}, nil
+}
+
+func (c *jsonFile) path(key string) string {
+	return filepath.Join(c.dir, c.prefix+key)
+}
+
+func (c *jsonFile) expired(storedAt time.Time) bool {
+	return c.timeout > 0 && time.Since(storedAt) > c.timeout
+}
+
+func (c *jsonFile) Get(key string) (types.Vars, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, ok := c.data[key]; ok && !c.expired(cached.storedAt) {
+		return cached.vars, true
+	}
+	info, err := os.Stat(c.path(key))
+	if err != nil || c.expired(info.ModTime()) {
+		return nil, false
+	}
+	dat, err := os.ReadFile(c.path(key))
+	if err != nil {
+		display.Warning(display.WarnOptions{}, "error reading the jsonfile cache file %s: %s", c.path(key), err)
+		return nil, false
+	}
+	var vars types.Vars
+	if err = json.Unmarshal(dat, &vars); err != nil {
+		display.Warning(display.WarnOptions{}, "error decoding the jsonfile cache file %s: %s", c.path(key), err)
+		return nil, false
+	}
+	c.data[key] = cachedVars{vars: vars, storedAt: info.ModTime()}
+	return vars, true
+}
+
+func (c *jsonFile) Set(key string, value types.Vars) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	dat, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error encoding the cached vars of %s: %w", key, err)
+	}
+	// The file is replaced at once, so that concurrent runs never read partial data.
+	tmp, err := os.CreateTemp(c.dir, ".tmp-"+c.prefix+key)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(dat); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return err
+	}
+	c.data[key] = cachedVars{vars: value, storedAt: time.Now()}
+	return nil
+}
+
+func (c *jsonFile) Delete(key string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.data, key)
+	if err := os.Remove(c.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (c *jsonFile) Keys() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasPrefix(name, c.prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || c.expired(info.ModTime()) {
+			continue
+		}
+		keys = append(keys, strings.TrimPrefix(name, c.prefix))
+	}
+	return keys, nil
+}
+
+func (c *jsonFile) Flush() error {
+	keys, err := c.Keys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err = c.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"github.com/scylladb/gosible/utils/maps"
+	"github.com/scylladb/gosible/utils/types"
+	"sync"
+)
+
+// memory keeps the vars for the duration of the run, like Ansible's memory cache plugin it ignores the timeout.
+type memory struct {
+	data map[string]types.Vars
+	lock sync.RWMutex
+}
+
+func newMemory(Options) (Cache, error) {
+	return &memory{data: make(map[string]types.Vars)}, nil
+}
+
+func (m *memory) Get(key string) (types.Vars, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	value, ok := m.data[key]
+	return value, ok
+}
+
+func (m *memory) Set(key string, value types.Vars) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.data[key] = value
+	return nil
+}
+
+func (m *memory) Delete(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+func (m *memory) Keys() ([]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return maps.Keys(m.data), nil
+}
+
+func (m *memory) Flush() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.data = make(map[string]types.Vars)
+	return nil
+}
@@ -1,37 +1,39 @@
 package plugins
 
 import (
-	"fmt"
 	"github.com/scylladb/gosible/connection"
 	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/remote"
 	pb "github.com/scylladb/gosible/remote/proto"
 	"github.com/scylladb/gosible/utils/shell"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 // ConnectionContext is responsible for keeping all connection specific data, such as the actual connection (eg. SSH),
 // connected host information, as well as instantiated plugins for the given host.
 // In our architecture, we want to keep a connection to each host therefore the name.
+// The Connection may be shared by several contexts of the same host, it is owned and closed by the connection manager.
 type ConnectionContext struct {
 	Connection           connection.CommandExecutor
 	RemoteExecutorConn   *grpc.ClientConn
 	RemoteExecutorClient pb.GosibleClientClient
 	Host                 *inventory.Host
+	// Agent holds the version and capabilities reported by the remote executor.
+	Agent *remote.AgentInfo
 }
 
 func (c *ConnectionContext) Shell() shell.Shell {
 	return c.Connection.Shell()
 }
 
+// Close closes the connection to the remote executor. The underlying Connection is left open.
 func (c *ConnectionContext) Close() error {
-	reErr := c.RemoteExecutorConn.Close()
-	cErr := c.Connection.Close()
+	return c.RemoteExecutorConn.Close()
+}
 
-	if reErr == nil {
-		return cErr
-	}
-	if cErr == nil {
-		return reErr
-	}
-	return fmt.Errorf("%s\n%w", reErr, cErr)
+// IsHealthy reports whether the connection to the remote executor may still be used.
+func (c *ConnectionContext) IsHealthy() bool {
+	state := c.RemoteExecutorConn.GetState()
+	return state != connectivity.Shutdown && state != connectivity.TransientFailure
 }
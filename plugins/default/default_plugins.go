@@ -2,9 +2,12 @@ package defaultPlugins
 
 import (
 	"github.com/scylladb/gosible/plugins"
+	"github.com/scylladb/gosible/plugins/action/copyFile"
 	"github.com/scylladb/gosible/plugins/action/debug"
 	"github.com/scylladb/gosible/plugins/action/example"
+	"github.com/scylladb/gosible/plugins/action/fetch"
 	"github.com/scylladb/gosible/plugins/action/setFact"
+	"github.com/scylladb/gosible/plugins/action/template"
 	"github.com/scylladb/gosible/plugins/action/waitForConnection"
 	"github.com/scylladb/gosible/plugins/become"
 	"github.com/scylladb/gosible/plugins/become/repository"
@@ -17,6 +20,9 @@ func Register() {
 	plugins.RegisterAction(debug.Name, toActionFn(debug.New))
 	plugins.RegisterAction(waitForConnection.Name, toActionFn(waitForConnection.New))
 	plugins.RegisterAction(setFact.Name, toActionFn(setFact.New))
+	plugins.RegisterAction(copyFile.Name, toActionFn(copyFile.New))
+	plugins.RegisterAction(fetch.Name, toActionFn(fetch.New))
+	plugins.RegisterAction(template.Name, toActionFn(template.New))
 
 	RegisterBecomePlugins()
 
@@ -30,6 +36,10 @@ func toActionFn[T plugins.Action](fn func() T) func() plugins.Action {
 func RegisterBecomePlugins() {
 	repository.RegisterBecomePlugin("su", toPluginFn(become.NewSu))
 	repository.RegisterBecomePlugin("sudo", toPluginFn(become.NewSudo))
+	repository.RegisterBecomePlugin("doas", toPluginFn(become.NewDoas))
+	repository.RegisterBecomePlugin("dzdo", toPluginFn(become.NewDzdo))
+	repository.RegisterBecomePlugin("pbrun", toPluginFn(become.NewPbrun))
+	repository.RegisterBecomePlugin("machinectl", toPluginFn(become.NewMachinectl))
 }
 
 func toPluginFn[T repository.BecomePlugin](fn func(*types.BecomeArgs) T) repository.BecomePluginConstructor {
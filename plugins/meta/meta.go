@@ -33,16 +33,17 @@ func endBatch(hosts map[string]*inventory.Host, t *playbookTypes.Task, play *pla
 }
 
 func resetConnection(_ map[string]*inventory.Host, _ *playbookTypes.Task, _ *playbookTypes.Play, mgrs map[string]*conn.Manager, _ *varsPkg.Manager) error {
-	for _, c := range mgrs {
-		if err := c.Close(); err != nil {
-			return err
-		}
+	f := func(c *conn.Manager) error { return c.Reset() }
+	if errors := parallel.ForAll(maps.Values(mgrs), f); errors.IsError() {
+		return fmt.Errorf("while resetting connections, %w", errors.Combine())
 	}
 	return nil
 }
 
-func refreshInventory(hosts map[string]*inventory.Host, t *playbookTypes.Task, play *playbookTypes.Play, mgrs map[string]*conn.Manager, manager *varsPkg.Manager) error {
-	// TODO implement when ansible implements dynamic inventories
+func refreshInventory(_ map[string]*inventory.Host, _ *playbookTypes.Task, _ *playbookTypes.Play, _ map[string]*conn.Manager, manager *varsPkg.Manager) error {
+	if err := manager.RefreshInventory(); err != nil {
+		return fmt.Errorf("while refreshing inventory, %w", err)
+	}
 	return nil
 }
 
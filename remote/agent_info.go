@@ -0,0 +1,111 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"github.com/scylladb/gosible/utils/display"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Version is the build version of gosible, shared by the controller and the remote executor.
+// Set at build time with -ldflags "-X github.com/scylladb/gosible/remote.Version=...".
+var Version = "dev"
+
+// ProtocolVersion must be increased on every incompatible change of the remote executor protocol.
+const ProtocolVersion uint32 = 1
+
+// Features supported by the remote executor, reported in the Hello reply.
+const (
+	FeatureExecuteModuleStream = "execute_module_stream"
+	FeatureFileTransfer        = "file_transfer"
+	FeaturePythonDiscovery     = "python_discovery"
+	FeatureCollectionModules   = "collection_modules"
+)
+
+// Features lists the features of the remote executor built from this source tree.
+var Features = []string{FeatureExecuteModuleStream, FeatureFileTransfer, FeaturePythonDiscovery, FeatureCollectionModules}
+
+// AgentInfo describes the remote executor running on a host.
+type AgentInfo struct {
+	Version         string
+	ProtocolVersion uint32
+	OS              string
+	Arch            string
+	modules         map[string]struct{}
+	features        map[string]struct{}
+}
+
+func newAgentInfo(reply *pb.HelloReply) *AgentInfo {
+	info := &AgentInfo{
+		Version:         reply.Version,
+		ProtocolVersion: reply.ProtocolVersion,
+		OS:              reply.Os,
+		Arch:            reply.Arch,
+		modules:         make(map[string]struct{}, len(reply.Modules)),
+		features:        make(map[string]struct{}, len(reply.Features)),
+	}
+	for _, m := range reply.Modules {
+		info.modules[m] = struct{}{}
+	}
+	for _, f := range reply.Features {
+		info.features[f] = struct{}{}
+	}
+	return info
+}
+
+// NewAgentInfo returns the information reported by a remote executor built from this source tree, running on
+// the given platform and supporting the given modules.
+func NewAgentInfo(os, arch string, modules []string) *AgentInfo {
+	return newAgentInfo(&pb.HelloReply{
+		Version:         Version,
+		ProtocolVersion: ProtocolVersion,
+		Os:              os,
+		Arch:            arch,
+		Modules:         modules,
+		Features:        Features,
+	})
+}
+
+func (a *AgentInfo) HasModule(name string) bool {
+	_, ok := a.modules[name]
+	return ok
+}
+
+func (a *AgentInfo) HasFeature(name string) bool {
+	_, ok := a.features[name]
+	return ok
+}
+
+// Hello performs the handshake with the remote executor and checks that it is compatible with the controller.
+func Hello(ctx context.Context, client pb.GosibleClientClient) (*AgentInfo, error) {
+	reply, err := client.Hello(ctx, &pb.HelloRequest{ControllerVersion: Version, ProtocolVersion: ProtocolVersion})
+	if status.Code(err) == codes.Unimplemented {
+		// Executors built before the handshake was introduced don't speak the current protocol.
+		return nil, fmt.Errorf("remote executor is older than controller version %s and doesn't support the handshake; "+
+			"rebuild the remote executor together with gosible (make build) so that %s matches the controller",
+			Version, ClientFileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("handshake with remote executor failed: %w", err)
+	}
+	info := newAgentInfo(reply)
+	if err = info.checkCompatible(); err != nil {
+		return nil, err
+	}
+	display.Debug(nil, "Remote executor version %s (protocol %d) running on %s/%s", info.Version, info.ProtocolVersion, info.OS, info.Arch)
+	return info, nil
+}
+
+func (a *AgentInfo) checkCompatible() error {
+	if a.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("remote executor version %s speaks protocol %d, but controller version %s requires protocol %d; "+
+			"rebuild the remote executor together with gosible (make build) so that %s matches the controller",
+			a.Version, a.ProtocolVersion, Version, ProtocolVersion, ClientFileName)
+	}
+	if a.Version != Version {
+		display.Warning(display.WarnOptions{}, "Remote executor version %s differs from controller version %s", a.Version, Version)
+	}
+	return nil
+}
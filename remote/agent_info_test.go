@@ -0,0 +1,62 @@
+package remote
+
+import (
+	"context"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+)
+
+type helloClient struct {
+	pb.GosibleClientClient
+	reply *pb.HelloReply
+	err   error
+}
+
+func (c *helloClient) Hello(context.Context, *pb.HelloRequest, ...grpc.CallOption) (*pb.HelloReply, error) {
+	return c.reply, c.err
+}
+
+func TestHello(t *testing.T) {
+	client := &helloClient{reply: &pb.HelloReply{
+		Version:         Version,
+		ProtocolVersion: ProtocolVersion,
+		Modules:         []string{"ping", "ansible.builtin.ping"},
+		Features:        []string{FeatureExecuteModuleStream},
+		Os:              "linux",
+		Arch:            "amd64",
+	}}
+	info, err := Hello(context.Background(), client)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !info.HasModule("ping") || info.HasModule("command") {
+		t.Error("unexpected modules", info.modules)
+	}
+	if !info.HasFeature(FeatureExecuteModuleStream) {
+		t.Error("expected feature", FeatureExecuteModuleStream)
+	}
+	if info.OS != "linux" || info.Arch != "amd64" {
+		t.Error("unexpected platform", info.OS, info.Arch)
+	}
+}
+
+func TestHelloMismatch(t *testing.T) {
+	clients := []*helloClient{
+		{reply: &pb.HelloReply{Version: "old", ProtocolVersion: ProtocolVersion + 1}},
+		{reply: &pb.HelloReply{Version: "unknown"}},
+	}
+	for _, client := range clients {
+		if _, err := Hello(context.Background(), client); err == nil {
+			t.Error("expected error on", client.reply, client.err)
+		}
+	}
+
+	_, err := Hello(context.Background(), &helloClient{err: status.Error(codes.Unimplemented, "unknown method Hello")})
+	if err == nil || !strings.Contains(err.Error(), "make build") {
+		t.Error("expected an error asking to rebuild an old remote executor, got", err)
+	}
+}
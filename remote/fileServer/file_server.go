@@ -0,0 +1,259 @@
+// Package fileServer implements the file transfer RPCs of the remote executor.
+package fileServer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	sl "github.com/opencontainers/selinux/go-selinux"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// Server serves the file transfer RPCs on the host's local filesystem.
+type Server struct{}
+
+// PutFile receives a file and atomically replaces the file at the path given in the first message.
+func (s *Server) PutFile(stream pb.GosibleClient_PutFileServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	attrs := req.GetAttributes()
+	if attrs == nil || attrs.Path == "" {
+		return errors.New("first PutFile message must hold the file attributes")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(attrs.Path), "."+filepath.Base(attrs.Path)+".gosible-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = receiveFile(stream, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = applyAttributes(tmp.Name(), attrs); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), attrs.Path); err != nil {
+		return err
+	}
+	return stream.SendAndClose(statFile(attrs.Path, false, false))
+}
+
+func receiveFile(stream pb.GosibleClient_PutFileServer, w io.Writer) error {
+	h := sha256.New()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return errors.New("PutFile stream ended without a checksum")
+		}
+		if err != nil {
+			return err
+		}
+		switch part := req.Part.(type) {
+		case *pb.PutFileRequest_Data:
+			if _, err = io.MultiWriter(w, h).Write(part.Data); err != nil {
+				return err
+			}
+		case *pb.PutFileRequest_Checksum:
+			if sum := hex.EncodeToString(h.Sum(nil)); sum != part.Checksum {
+				return fmt.Errorf("checksum mismatch, expected %s, got %s", part.Checksum, sum)
+			}
+			return nil
+		default:
+			return errors.New("unexpected PutFile message")
+		}
+	}
+}
+
+// applyAttributes sets the attributes of the temporary file. Attributes which are not set are copied from
+// the file being replaced, if there is one.
+func applyAttributes(tmpPath string, attrs *pb.FileAttributes) error {
+	existing, err := os.Lstat(attrs.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	mode := fs.FileMode(0644)
+	if attrs.Mode != nil {
+		mode = fs.FileMode(*attrs.Mode)
+	} else if existing != nil {
+		mode = existing.Mode().Perm()
+	}
+	if err = os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+
+	uid, gid := -1, -1
+	if st, ok := statT(existing); ok {
+		uid, gid = int(st.Uid), int(st.Gid)
+	}
+	if attrs.Owner != "" {
+		if uid, err = lookupUid(attrs.Owner); err != nil {
+			return err
+		}
+	}
+	if attrs.Group != "" {
+		if gid, err = lookupGid(attrs.Group); err != nil {
+			return err
+		}
+	}
+	if tmpInfo, err := os.Lstat(tmpPath); err == nil {
+		// Changing the owner to the current one needs no privileges, but is skipped anyway.
+		if st, ok := statT(tmpInfo); ok && uid == int(st.Uid) && gid == int(st.Gid) {
+			uid, gid = -1, -1
+		}
+	}
+	if uid != -1 || gid != -1 {
+		if err = os.Lchown(tmpPath, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	if !sl.GetEnabled() {
+		return nil
+	}
+	label := attrs.SeContext
+	if label == "" && existing != nil {
+		label, _ = sl.LfileLabel(attrs.Path)
+	}
+	if label != "" {
+		return sl.LsetFileLabel(tmpPath, label)
+	}
+	return nil
+}
+
+func lookupUid(owner string) (int, error) {
+	if uid, err := strconv.Atoi(owner); err == nil {
+		return uid, nil
+	}
+	u, err := user.Lookup(owner)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupGid(group string) (int, error) {
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+func statT(info fs.FileInfo) (*syscall.Stat_t, bool) {
+	if info == nil {
+		return nil, false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return st, ok
+}
+
+// GetFile sends the file, its information first and its checksum last.
+func (s *Server) GetFile(req *pb.GetFileRequest, stream pb.GosibleClient_GetFileServer) error {
+	f, err := os.Open(req.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info := statFile(req.Path, true, false)
+	if err = stream.Send(&pb.GetFileReply{Part: &pb.GetFileReply_Info{Info: info}}); err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	buf := make([]byte, remote.FileChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			if err := stream.Send(&pb.GetFileReply{Part: &pb.GetFileReply_Data{Data: buf[:n]}}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return stream.Send(&pb.GetFileReply{Part: &pb.GetFileReply_Checksum{Checksum: hex.EncodeToString(h.Sum(nil))}})
+}
+
+// StatFile returns information about the file.
+func (s *Server) StatFile(_ context.Context, req *pb.StatFileRequest) (*pb.FileInfo, error) {
+	return statFile(req.Path, req.FollowSymlinks, req.Checksum), nil
+}
+
+func statFile(path string, followSymlinks, checksum bool) *pb.FileInfo {
+	ret := &pb.FileInfo{Path: path}
+	stat := os.Lstat
+	if followSymlinks {
+		stat = os.Stat
+	}
+	info, err := stat(path)
+	if err != nil {
+		return ret
+	}
+
+	ret.Exists = true
+	ret.IsDir = info.IsDir()
+	ret.IsLink = info.Mode()&fs.ModeSymlink != 0
+	ret.Size = info.Size()
+	ret.Mode = uint32(info.Mode().Perm())
+	ret.Mtime = info.ModTime().Unix()
+	if ret.IsLink {
+		ret.LinkTarget, _ = os.Readlink(path)
+	}
+	if st, ok := statT(info); ok {
+		ret.Uid, ret.Gid = st.Uid, st.Gid
+		if u, err := user.LookupId(strconv.Itoa(int(st.Uid))); err == nil {
+			ret.Owner = u.Username
+		}
+		if g, err := user.LookupGroupId(strconv.Itoa(int(st.Gid))); err == nil {
+			ret.Group = g.Name
+		}
+	}
+	if sl.GetEnabled() {
+		ret.SeContext, _ = sl.LfileLabel(path)
+	}
+	if checksum && info.Mode().IsRegular() {
+		ret.Checksum, _ = fileChecksum(path)
+	}
+	return ret
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,107 @@
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"io"
+)
+
+// FileChunkSize is the size of data sent in a single file transfer message.
+const FileChunkSize = 256 << 10
+
+// PutFile writes the data read from r to the file on the host, setting the given attributes.
+// The file is replaced atomically and only if the data was received intact.
+func PutFile(ctx context.Context, client pb.GosibleClientClient, r io.Reader, attrs *pb.FileAttributes) (*pb.FileInfo, error) {
+	stream, err := client.PutFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.Send(&pb.PutFileRequest{Part: &pb.PutFileRequest_Attributes{Attributes: attrs}}); err != nil {
+		return nil, putFileError(stream, err)
+	}
+
+	h := sha256.New()
+	buf := make([]byte, FileChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			if err := stream.Send(&pb.PutFileRequest{Part: &pb.PutFileRequest_Data{Data: buf[:n]}}); err != nil {
+				return nil, putFileError(stream, err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = stream.CloseSend()
+			return nil, err
+		}
+	}
+
+	checksum := hex.EncodeToString(h.Sum(nil))
+	if err = stream.Send(&pb.PutFileRequest{Part: &pb.PutFileRequest_Checksum{Checksum: checksum}}); err != nil {
+		return nil, putFileError(stream, err)
+	}
+	info, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to put file %s: %w", attrs.Path, err)
+	}
+	return info, nil
+}
+
+// putFileError returns the error reported by the server when sending fails, as Send returns just io.EOF then.
+func putFileError(stream pb.GosibleClient_PutFileClient, err error) error {
+	if err == io.EOF {
+		_, err = stream.CloseAndRecv()
+	}
+	return fmt.Errorf("failed to put file: %w", err)
+}
+
+// GetFile reads the file from the host into w and verifies its checksum.
+func GetFile(ctx context.Context, client pb.GosibleClientClient, path string, w io.Writer) (*pb.FileInfo, error) {
+	stream, err := client.GetFile(ctx, &pb.GetFileRequest{Path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	var info *pb.FileInfo
+	h := sha256.New()
+	for {
+		reply, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("failed to get file %s: stream ended without a checksum", path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file %s: %w", path, err)
+		}
+
+		switch part := reply.Part.(type) {
+		case *pb.GetFileReply_Info:
+			info = part.Info
+		case *pb.GetFileReply_Data:
+			h.Write(part.Data)
+			if _, err = w.Write(part.Data); err != nil {
+				return nil, err
+			}
+		case *pb.GetFileReply_Checksum:
+			if sum := hex.EncodeToString(h.Sum(nil)); sum != part.Checksum {
+				return nil, fmt.Errorf("checksum mismatch of file %s, expected %s, got %s", path, part.Checksum, sum)
+			}
+			if info == nil {
+				return nil, errors.New("file information missing in GetFile reply")
+			}
+			info.Checksum = part.Checksum
+			return info, nil
+		}
+	}
+}
+
+// StatFile returns information about the file on the host.
+func StatFile(ctx context.Context, client pb.GosibleClientClient, path string, followSymlinks, checksum bool) (*pb.FileInfo, error) {
+	return client.StatFile(ctx, &pb.StatFileRequest{Path: path, FollowSymlinks: followSymlinks, Checksum: checksum})
+}
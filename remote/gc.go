@@ -0,0 +1,95 @@
+package remote
+
+import (
+	"github.com/scylladb/gosible/connection"
+	"github.com/scylladb/gosible/module_utils/pythonModule"
+	"github.com/scylladb/gosible/utils/display"
+	"regexp"
+	"strings"
+)
+
+var cacheEntryRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// runtimeCacheDir is the remote directory keeping the Python runtimes stored by the remote executors.
+const runtimeCacheDir = "$HOME/" + pythonModule.RuntimeCacheDir
+
+// GarbageCollect removes the remote executor binaries and the Python runtimes cached on the host, except for the ones
+// of the local build unless all is set. Returns the names of the removed cache entries, which are only listed if
+// dryRun is set.
+func GarbageCollect(conn connection.CommandExecutor, all, dryRun bool) ([]string, error) {
+	stale, err := collectBinaries(conn, all, dryRun)
+	if err != nil {
+		return stale, err
+	}
+	runtimes, err := collectPythonRuntimes(conn, all, dryRun)
+	return append(stale, runtimes...), err
+}
+
+func collectBinaries(conn connection.CommandExecutor, all, dryRun bool) ([]string, error) {
+	keep := make(map[string]bool)
+	if !all {
+		binaries, err := localBinaries()
+		if err != nil {
+			return nil, err
+		}
+		for _, sha := range binaries {
+			keep[sha] = true
+		}
+	}
+
+	values, err := runScript(conn, "for D in \""+cacheDir+"\"/*/; do [ -d \"$D\" ] && echo \"Entry=$(basename \"$D\")\"; done; true", nil)
+	if err != nil {
+		return nil, err
+	}
+	stale := staleEntries(values["Entry"], keep)
+	if len(stale) == 0 || dryRun {
+		return stale, nil
+	}
+
+	display.Debug(nil, "Removing cached remote executors: %s", strings.Join(stale, ", "))
+	_, err = runScript(conn, "cd \""+cacheDir+"\" && rm -rf -- "+strings.Join(stale, " "), nil)
+	return stale, err
+}
+
+// staleEntries returns the cache entries which are not kept. Entries not named after a sha256 hash are left alone.
+func staleEntries(entries []string, keep map[string]bool) []string {
+	var stale []string
+	for _, entry := range entries {
+		if cacheEntryRegex.MatchString(entry) && !keep[entry] {
+			stale = append(stale, entry)
+		}
+	}
+	return stale
+}
+
+// collectPythonRuntimes removes the Python runtimes, named after their checksums, other than the local one.
+func collectPythonRuntimes(conn connection.CommandExecutor, all, dryRun bool) ([]string, error) {
+	keep := make(map[string]bool)
+	if !all {
+		if sha := PythonRuntimeChecksum(); sha != "" {
+			keep[sha] = true
+		}
+	}
+
+	values, err := runScript(conn, "for F in \""+runtimeCacheDir+"\"/*.zip; do [ -f \"$F\" ] && echo \"Entry=$(basename \"$F\" .zip)\"; done; true", nil)
+	if err != nil {
+		return nil, err
+	}
+	stale := staleEntries(values["Entry"], keep)
+	if len(stale) == 0 {
+		return nil, nil
+	}
+	files := make([]string, 0, len(stale))
+	for _, sha := range stale {
+		files = append(files, sha+".zip")
+	}
+	if !dryRun {
+		display.Debug(nil, "Removing cached Python runtimes: %s", strings.Join(files, ", "))
+		_, err = runScript(conn, "cd \""+runtimeCacheDir+"\" && rm -f -- "+strings.Join(files, " "), nil)
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, "py_runtime/"+f)
+	}
+	return names, err
+}
@@ -0,0 +1,60 @@
+package remote
+
+import (
+	"github.com/scylladb/gosible/module_utils/pythonModule"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStaleEntries(t *testing.T) {
+	current := strings.Repeat("a", 64)
+	old := strings.Repeat("b", 64)
+	entries := []string{current, old, "not-a-hash", strings.Repeat("c", 63)}
+
+	res := staleEntries(entries, map[string]bool{current: true})
+	if !reflect.DeepEqual(res, []string{old}) {
+		t.Error("expected", []string{old}, "got", res)
+	}
+
+	res = staleEntries(entries, nil)
+	if !reflect.DeepEqual(res, []string{current, old}) {
+		t.Error("expected", []string{current, old}, "got", res)
+	}
+}
+
+func TestCollectPythonRuntimes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, pythonModule.RuntimeCacheDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := strings.Repeat("b", 64)
+	for _, name := range []string{old + ".zip", "not-a-hash.zip", ".py_runtime-123"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"py_runtime/" + old + ".zip"}
+	for _, dryRun := range []bool{true, false} {
+		removed, err := collectPythonRuntimes(localExecutor{}, true, dryRun)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(removed, expected) {
+			t.Error("on dry run", dryRun, "expected", expected, "got", removed)
+		}
+		if _, err = os.Stat(filepath.Join(dir, old+".zip")); dryRun == os.IsNotExist(err) {
+			t.Error("on dry run", dryRun, "unexpected runtime state", err)
+		}
+	}
+	for _, name := range []string{"not-a-hash.zip", ".py_runtime-123"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error("expected", name, "to be kept", err)
+		}
+	}
+}
@@ -12,10 +12,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"io"
-	"log"
 	"net"
 	"os"
-	"path"
 	"strings"
 	"time"
 )
@@ -25,12 +23,7 @@ var grpcDialTimeout = time.Second * 3
 // Execute executes remote gosible subprogram over provided connection.
 // Should be run as a goroutine as it waits for end of execution.
 func Execute(conn connection.Connection, becomeArgs *types.BecomeArgs) (*grpc.ClientConn, error) {
-	binaryPath, err := getBinaryPath()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get binary path: %v", err)
-	}
-
-	remotePath, err := sendToRemote(binaryPath, conn, becomeArgs)
+	remotePath, err := sendToRemote(conn, becomeArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send binary to remote: %v", err)
 	}
@@ -56,20 +49,14 @@ func execute(path string, conn connection.InteractiveCommandExecutor, becomeArgs
 	return grpc.DialContext(grpcCtx, "", grpc.WithBlock(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(sshDialer))
 }
 
-func sendToRemote(path string, conn connection.SendExecuteConnection, becomeArgs *types.BecomeArgs) (string, error) {
+func sendToRemote(conn connection.SendExecuteConnection, becomeArgs *types.BecomeArgs) (string, error) {
 	if becomeArgs.User != "" {
-		return sendToRemoteLegacy(path, conn)
+		return sendToRemoteLegacy(conn)
 	}
 
 	display.Debug(nil, "Fetching minimum required information about the remote host")
 
-	f, err := os.Open(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to open binary: %v", err)
-	}
-	defer f.Close()
-
-	si, err := gatherSystemInfo(path, conn)
+	si, err := gatherSystemInfo(conn)
 	if err != nil {
 		return "", err
 	}
@@ -78,28 +65,32 @@ func sendToRemote(path string, conn connection.SendExecuteConnection, becomeArgs
 		return si.gosibleBinPath, nil
 	}
 
-	display.Debug(nil, "Sending the gosible subprogram binary to host")
-	return si.gosibleTmpBinPath, conn.SendFile(f, si.gosibleTmpBinPath, "0555")
+	display.Debug(nil, "Sending the gosible subprogram binary for %s to host", si.platform)
+	return uploadBinary(conn, si.localBinPath, si.sha, si.gosibleDir)
 }
 
-func sendToRemoteLegacy(path string, conn connection.SendExecuteConnection) (string, error) {
+func sendToRemoteLegacy(conn connection.SendExecuteConnection) (string, error) {
 	// TODO this function should check if binary already exists on host.
-	display.Debug(nil, "Sending the gosible subprogram binary to host")
-
-	f, err := os.Open(path)
+	platform, err := detectPlatform(conn)
 	if err != nil {
-		return "", fmt.Errorf("failed to open binary: %v", err)
+		return "", err
+	}
+	binaryPath, err := getBinaryPathFor(platform)
+	if err != nil {
+		return "", err
+	}
+	sha, err := hashes.get(binaryPath)
+	if err != nil {
+		return "", err
 	}
-	defer f.Close()
 
 	dir, err := getDirPathLegacy(conn)
 	if err != nil {
 		return "", err
 	}
 
-	remotePath := dir + "/" + ClientFileName
-
-	return remotePath, conn.SendFile(f, remotePath, "0555")
+	display.Debug(nil, "Sending the gosible subprogram binary for %s to host", platform)
+	return uploadBinary(conn, binaryPath, sha, dir)
 }
 
 func getDirPathLegacy(conn connection.CommandExecutor) (string, error) {
@@ -115,24 +106,16 @@ func getGosiblePath() (string, error) {
 	return osUtils.GetBinaryDir()
 }
 
-func getBinaryPath() (string, error) {
-	gosiblePath, err := getGosiblePath()
-	if err != nil {
-		return "", err
-	}
-	return path.Join(gosiblePath, "remote", "gosible_client"), nil
-}
-
 func getHashStr(path string) (string, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 	defer f.Close()
 
 	h := sha256.New()
 	if _, err := io.Copy(h, f); err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
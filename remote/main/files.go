@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	pb "github.com/scylladb/gosible/remote/proto"
+)
+
+func (s *server) PutFile(stream pb.GosibleClient_PutFileServer) error {
+	return s.files.PutFile(stream)
+}
+
+func (s *server) GetFile(req *pb.GetFileRequest, stream pb.GosibleClient_GetFileServer) error {
+	return s.files.GetFile(req, stream)
+}
+
+func (s *server) StatFile(ctx context.Context, req *pb.StatFileRequest) (*pb.FileInfo, error) {
+	return s.files.StatFile(ctx, req)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/remote"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestClient(t *testing.T) pb.GosibleClientClient {
+	listener := bufconn.Listen(1 << 20)
+	grpcS := grpc.NewServer()
+	registerServer(grpcS, newServer(modules.NewRegistry()))
+	go func() { _ = grpcS.Serve(listener) }()
+	t.Cleanup(grpcS.Stop)
+
+	dialer := func(context.Context, string) (net.Conn, error) { return listener.Dial() }
+	conn, err := grpc.Dial("", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewGosibleClientClient(conn)
+}
+
+func TestFileTransfer(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "file")
+	content := bytes.Repeat([]byte("gosible"), remote.FileChunkSize/3)
+
+	mode := uint32(0600)
+	info, err := remote.PutFile(ctx, client, bytes.NewReader(content), &pb.FileAttributes{Path: path, Mode: &mode})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !info.Exists || info.Size != int64(len(content)) || info.Mode != mode {
+		t.Error("unexpected file info", info)
+	}
+
+	// The mode of the replaced file is kept.
+	if _, err = remote.PutFile(ctx, client, bytes.NewReader(content[:10]), &pb.FileAttributes{Path: path}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if st, err := os.Stat(path); err != nil || st.Mode().Perm() != 0600 || st.Size() != 10 {
+		t.Error("unexpected file", st, err)
+	}
+
+	var buf bytes.Buffer
+	info, err = remote.GetFile(ctx, client, path, &buf)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !bytes.Equal(buf.Bytes(), content[:10]) || info.Checksum == "" {
+		t.Error("unexpected content", buf.String(), info)
+	}
+
+	checksum := info.Checksum
+	info, err = remote.StatFile(ctx, client, path, false, true)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if info.Checksum != checksum {
+		t.Error("expected checksum", checksum, "got", info.Checksum)
+	}
+
+	info, err = remote.StatFile(ctx, client, path+".missing", false, false)
+	if err != nil || info.Exists {
+		t.Error("expected missing file", info, err)
+	}
+
+	if _, err = remote.GetFile(ctx, client, path+".missing", &buf); err == nil {
+		t.Error("expected error on missing file")
+	}
+}
@@ -27,5 +27,6 @@ func main() {
 	mods := modules.NewRegistry()
 	defaultModules.Register(mods)
 	defaultModules.RegisterPython(mods)
+	defaultModules.RegisterCollectionFallback(mods)
 	setupRpcServer(mods)
 }
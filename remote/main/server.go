@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"github.com/scylladb/gosible/module_utils/pythonModule"
 	"github.com/scylladb/gosible/modules"
+	"github.com/scylladb/gosible/remote"
+	"github.com/scylladb/gosible/remote/fileServer"
 	pb "github.com/scylladb/gosible/remote/proto"
 	"github.com/scylladb/gosible/utils/stdIoConn"
 	"github.com/scylladb/gosible/utils/types"
@@ -12,15 +15,52 @@ import (
 	"google.golang.org/grpc/reflection"
 	"log"
 	"os"
+	"runtime"
 )
 
 type server struct {
 	pb.UnimplementedGosibleClientServer
 
 	modules *modules.ModuleRegistry
+	files   fileServer.Server
+}
+
+func (s *server) Hello(_ context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+	if req.ProtocolVersion != remote.ProtocolVersion {
+		log.Printf("controller version %s uses protocol %d, executor supports %d", req.ControllerVersion, req.ProtocolVersion, remote.ProtocolVersion)
+	}
+	return &pb.HelloReply{
+		Version:         remote.Version,
+		ProtocolVersion: remote.ProtocolVersion,
+		Modules:         s.modules.Names(),
+		Features:        remote.Features,
+		Os:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+	}, nil
+}
+
+func (s *server) DiscoverPythonInterpreter(_ context.Context, req *pb.DiscoverPythonInterpreterRequest) (*pb.DiscoverPythonInterpreterReply, error) {
+	interpreter, warnings, err := pythonModule.DiscoverInterpreter(req.Mode, req.Fallback)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DiscoverPythonInterpreterReply{Interpreter: interpreter, Warnings: warnings}, nil
 }
 
 func (s *server) ExecuteModule(_ context.Context, req *pb.ExecuteModuleRequest) (*pb.ExecuteModuleReply, error) {
+	return s.runModule(req, nil)
+}
+
+func (s *server) ExecuteModuleStream(req *pb.ExecuteModuleRequest, stream pb.GosibleClient_ExecuteModuleStreamServer) error {
+	output := &streamOutput{stream: stream}
+	reply, err := s.runModule(req, output)
+	if err != nil {
+		return err
+	}
+	return output.send(&pb.ExecuteModuleEvent{Event: &pb.ExecuteModuleEvent_Reply{Reply: reply}})
+}
+
+func (s *server) runModule(req *pb.ExecuteModuleRequest, output modules.OutputStreamer) (*pb.ExecuteModuleReply, error) {
 	action, ok := s.modules.FindModule(req.ModuleName)
 	if !ok {
 		return nil, errors.New("module not found")
@@ -32,6 +72,7 @@ func (s *server) ExecuteModule(_ context.Context, req *pb.ExecuteModuleRequest)
 	}
 	ctx := &modules.RunContext{
 		MetaArgs: req.MetaArgs,
+		Output:   output,
 	}
 	result := action.Run(ctx, vars)
 	resultJson, err := json.Marshal(&result)
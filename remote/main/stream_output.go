@@ -0,0 +1,41 @@
+package main
+
+import (
+	pb "github.com/scylladb/gosible/remote/proto"
+	"sync"
+)
+
+// streamOutput sends the output of a running module to the controller as ExecuteModuleStream events.
+type streamOutput struct {
+	stream pb.GosibleClient_ExecuteModuleStreamServer
+	// lock serializes sending, stdout and stderr are written from different goroutines.
+	lock sync.Mutex
+	// err is the first sending error. Further output is dropped, the module keeps running.
+	err error
+}
+
+func (o *streamOutput) Stdout(data []byte) {
+	o.sendOutput(pb.OutputChunk_STDOUT, data)
+}
+
+func (o *streamOutput) Stderr(data []byte) {
+	o.sendOutput(pb.OutputChunk_STDERR, data)
+}
+
+func (o *streamOutput) Progress(msg string) {
+	_ = o.send(&pb.ExecuteModuleEvent{Event: &pb.ExecuteModuleEvent_Progress{Progress: &pb.ProgressEvent{Message: msg}}})
+}
+
+func (o *streamOutput) sendOutput(stream pb.OutputChunk_Stream, data []byte) {
+	chunk := &pb.OutputChunk{Stream: stream, Data: data}
+	_ = o.send(&pb.ExecuteModuleEvent{Event: &pb.ExecuteModuleEvent_Output{Output: chunk}})
+}
+
+func (o *streamOutput) send(event *pb.ExecuteModuleEvent) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.err == nil {
+		o.err = o.stream.Send(event)
+	}
+	return o.err
+}
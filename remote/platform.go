@@ -0,0 +1,120 @@
+package remote
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Platform is the GOOS/GOARCH pair the remote executor binary is built for.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// SupportedPlatforms lists the platforms the remote executor binaries are built for.
+var SupportedPlatforms = []Platform{
+	{OS: "linux", Arch: "amd64"},
+	{OS: "linux", Arch: "arm64"},
+	{OS: "linux", Arch: "ppc64le"},
+	{OS: "linux", Arch: "s390x"},
+}
+
+// defaultPlatform is the platform of the binary named just ClientFileName, built before multi-arch support.
+var defaultPlatform = Platform{OS: "linux", Arch: "amd64"}
+
+// unameArchs maps `uname -m` to GOARCH. armv8l is a 32-bit userland running on an ARMv8 kernel, so it needs
+// the 32-bit arm binary.
+var unameArchs = map[string]string{
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"aarch64": "arm64",
+	"arm64":   "arm64",
+	"armv7l":  "arm",
+	"armv8l":  "arm",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// parsePlatform converts the output of `uname -s` and `uname -m` to the Go platform.
+func parsePlatform(kernel, machine string) (Platform, error) {
+	kernel = strings.TrimSpace(kernel)
+	machine = strings.TrimSpace(machine)
+	arch, ok := unameArchs[machine]
+	if !ok {
+		return Platform{}, fmt.Errorf("unsupported remote architecture %q", machine)
+	}
+	return Platform{OS: strings.ToLower(kernel), Arch: arch}, nil
+}
+
+// clientBinaryName returns the file name of the remote executor built for the platform.
+func clientBinaryName(p Platform) string {
+	return ClientFileName + "_" + p.OS + "_" + p.Arch
+}
+
+// getBinaryPathFor returns the path of the local remote executor binary for the platform.
+func getBinaryPathFor(p Platform) (string, error) {
+	gosiblePath, err := getGosiblePath()
+	if err != nil {
+		return "", err
+	}
+	binPath := path.Join(gosiblePath, "remote", clientBinaryName(p))
+	if _, err = os.Stat(binPath); err == nil {
+		return binPath, nil
+	}
+	if p == defaultPlatform {
+		binPath = path.Join(gosiblePath, "remote", ClientFileName)
+		if _, err = os.Stat(binPath); err == nil {
+			return binPath, nil
+		}
+	}
+	return "", fmt.Errorf("no remote executor binary for %s, build it with `make build` (expected %s)", p, binPath)
+}
+
+// localBinaries returns the hashes of the remote executor binaries available locally, keyed by platform.
+func localBinaries() (map[Platform]string, error) {
+	ret := make(map[Platform]string)
+	for _, p := range SupportedPlatforms {
+		binPath, err := getBinaryPathFor(p)
+		if err != nil {
+			continue
+		}
+		sha, err := hashes.get(binPath)
+		if err != nil {
+			return nil, err
+		}
+		ret[p] = sha
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no remote executor binaries found, build them with `make build`")
+	}
+	return ret, nil
+}
+
+// hashCache keeps the hashes of the local binaries, so that they are read only once.
+type hashCache struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+var hashes = &hashCache{hashes: make(map[string]string)}
+
+func (c *hashCache) get(binPath string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sha, ok := c.hashes[binPath]; ok {
+		return sha, nil
+	}
+	sha, err := getHashStr(binPath)
+	if err != nil {
+		return "", err
+	}
+	c.hashes[binPath] = sha
+	return sha, nil
+}
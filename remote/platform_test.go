@@ -0,0 +1,63 @@
+package remote
+
+import "testing"
+
+func TestParsePlatform(t *testing.T) {
+	testCases := []struct {
+		kernel   string
+		machine  string
+		expected Platform
+	}{
+		{kernel: "Linux", machine: "x86_64", expected: Platform{OS: "linux", Arch: "amd64"}},
+		{kernel: "Linux", machine: "aarch64\n", expected: Platform{OS: "linux", Arch: "arm64"}},
+		{kernel: "Linux", machine: "armv8l", expected: Platform{OS: "linux", Arch: "arm"}},
+		{kernel: "Linux", machine: "ppc64le", expected: Platform{OS: "linux", Arch: "ppc64le"}},
+		{kernel: "Linux", machine: "s390x", expected: Platform{OS: "linux", Arch: "s390x"}},
+		{kernel: "Darwin", machine: "arm64", expected: Platform{OS: "darwin", Arch: "arm64"}},
+	}
+
+	for _, testCase := range testCases {
+		res, err := parsePlatform(testCase.kernel, testCase.machine)
+		if err != nil {
+			t.Fatal("on", testCase.kernel, testCase.machine, "unexpected error", err)
+		}
+		if res != testCase.expected {
+			t.Error("on", testCase.kernel, testCase.machine, "expected", testCase.expected, "got", res)
+		}
+	}
+
+	if _, err := parsePlatform("Linux", "mips"); err == nil {
+		t.Error("expected error on unsupported architecture")
+	}
+}
+
+func TestParseSystemInfo(t *testing.T) {
+	binaries := map[Platform]string{
+		{OS: "linux", Arch: "amd64"}: "aaa",
+		{OS: "linux", Arch: "arm64"}: "bbb",
+	}
+	output := "Cache=/home/user/.cache/gosible_client\nKernel=Linux\nMachine=aarch64\nHasRunner=aaa\nHasRunner=bbb\n"
+
+	si, err := parseSystemInfo(output, binaries)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if si.platform != (Platform{OS: "linux", Arch: "arm64"}) {
+		t.Error("unexpected platform", si.platform)
+	}
+	if si.gosibleDir != "/home/user/.cache/gosible_client/bbb" || !si.gosibleBinExists {
+		t.Error("unexpected directory", si.gosibleDir, si.gosibleBinExists)
+	}
+
+	si, err = parseSystemInfo("Cache=/root/.cache/gosible_client\nKernel=Linux\nMachine=x86_64\n", binaries)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if si.gosibleBinExists || si.sha != "aaa" || si.gosibleBinPath != "/root/.cache/gosible_client/aaa/"+ClientFileName {
+		t.Error("unexpected result", si)
+	}
+
+	if _, err = parseSystemInfo("Cache=/root\nKernel=Linux\nMachine=s390x\n", binaries); err == nil {
+		t.Error("expected error on missing binary")
+	}
+}
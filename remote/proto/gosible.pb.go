@@ -20,33 +20,963 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OutputChunk_Stream int32
+
+const (
+	OutputChunk_STDOUT OutputChunk_Stream = 0
+	OutputChunk_STDERR OutputChunk_Stream = 1
+)
+
+// Enum value maps for OutputChunk_Stream.
+var (
+	OutputChunk_Stream_name = map[int32]string{
+		0: "STDOUT",
+		1: "STDERR",
+	}
+	OutputChunk_Stream_value = map[string]int32{
+		"STDOUT": 0,
+		"STDERR": 1,
+	}
+)
+
+func (x OutputChunk_Stream) Enum() *OutputChunk_Stream {
+	p := new(OutputChunk_Stream)
+	*p = x
+	return p
+}
+
+func (x OutputChunk_Stream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputChunk_Stream) Descriptor() protoreflect.EnumDescriptor {
+	return file_remote_proto_gosible_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputChunk_Stream) Type() protoreflect.EnumType {
+	return &file_remote_proto_gosible_proto_enumTypes[0]
+}
+
+func (x OutputChunk_Stream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputChunk_Stream.Descriptor instead.
+func (OutputChunk_Stream) EnumDescriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type HelloRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ControllerVersion string `protobuf:"bytes,1,opt,name=controllerVersion,proto3" json:"controllerVersion,omitempty"`
+	ProtocolVersion   uint32 `protobuf:"varint,2,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+}
+
+func (x *HelloRequest) Reset() {
+	*x = HelloRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HelloRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelloRequest) ProtoMessage() {}
+
+func (x *HelloRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelloRequest.ProtoReflect.Descriptor instead.
+func (*HelloRequest) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HelloRequest) GetControllerVersion() string {
+	if x != nil {
+		return x.ControllerVersion
+	}
+	return ""
+}
+
+func (x *HelloRequest) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+type HelloReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version         string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	ProtocolVersion uint32   `protobuf:"varint,2,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	Modules         []string `protobuf:"bytes,3,rep,name=modules,proto3" json:"modules,omitempty"`
+	Features        []string `protobuf:"bytes,4,rep,name=features,proto3" json:"features,omitempty"`
+	Os              string   `protobuf:"bytes,5,opt,name=os,proto3" json:"os,omitempty"`
+	Arch            string   `protobuf:"bytes,6,opt,name=arch,proto3" json:"arch,omitempty"`
+}
+
+func (x *HelloReply) Reset() {
+	*x = HelloReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HelloReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelloReply) ProtoMessage() {}
+
+func (x *HelloReply) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelloReply.ProtoReflect.Descriptor instead.
+func (*HelloReply) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HelloReply) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *HelloReply) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *HelloReply) GetModules() []string {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
+func (x *HelloReply) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *HelloReply) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *HelloReply) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
 type ExecuteModuleRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ModuleName string    `protobuf:"bytes,1,opt,name=moduleName,proto3" json:"moduleName,omitempty"`
-	VarsJson   []byte    `protobuf:"bytes,2,opt,name=varsJson,proto3" json:"varsJson,omitempty"`
-	MetaArgs   *MetaArgs `protobuf:"bytes,3,opt,name=metaArgs,proto3" json:"metaArgs,omitempty"`
+	ModuleName string    `protobuf:"bytes,1,opt,name=moduleName,proto3" json:"moduleName,omitempty"`
+	VarsJson   []byte    `protobuf:"bytes,2,opt,name=varsJson,proto3" json:"varsJson,omitempty"`
+	MetaArgs   *MetaArgs `protobuf:"bytes,3,opt,name=metaArgs,proto3" json:"metaArgs,omitempty"`
+}
+
+func (x *ExecuteModuleRequest) Reset() {
+	*x = ExecuteModuleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteModuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteModuleRequest) ProtoMessage() {}
+
+func (x *ExecuteModuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteModuleRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteModuleRequest) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ExecuteModuleRequest) GetModuleName() string {
+	if x != nil {
+		return x.ModuleName
+	}
+	return ""
+}
+
+func (x *ExecuteModuleRequest) GetVarsJson() []byte {
+	if x != nil {
+		return x.VarsJson
+	}
+	return nil
+}
+
+func (x *ExecuteModuleRequest) GetMetaArgs() *MetaArgs {
+	if x != nil {
+		return x.MetaArgs
+	}
+	return nil
+}
+
+type ExecuteModuleReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReturnValueJson []byte `protobuf:"bytes,1,opt,name=returnValueJson,proto3" json:"returnValueJson,omitempty"`
+}
+
+func (x *ExecuteModuleReply) Reset() {
+	*x = ExecuteModuleReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteModuleReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteModuleReply) ProtoMessage() {}
+
+func (x *ExecuteModuleReply) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteModuleReply.ProtoReflect.Descriptor instead.
+func (*ExecuteModuleReply) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExecuteModuleReply) GetReturnValueJson() []byte {
+	if x != nil {
+		return x.ReturnValueJson
+	}
+	return nil
+}
+
+type MetaArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PythonInterpreter string `protobuf:"bytes,1,opt,name=pythonInterpreter,proto3" json:"pythonInterpreter,omitempty"`
+	PyRuntimeZipData  []byte `protobuf:"bytes,2,opt,name=pyRuntimeZipData,proto3" json:"pyRuntimeZipData,omitempty"`
+	PyRuntimeChecksum string `protobuf:"bytes,3,opt,name=pyRuntimeChecksum,proto3" json:"pyRuntimeChecksum,omitempty"`
+}
+
+func (x *MetaArgs) Reset() {
+	*x = MetaArgs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetaArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetaArgs) ProtoMessage() {}
+
+func (x *MetaArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetaArgs.ProtoReflect.Descriptor instead.
+func (*MetaArgs) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MetaArgs) GetPythonInterpreter() string {
+	if x != nil {
+		return x.PythonInterpreter
+	}
+	return ""
+}
+
+func (x *MetaArgs) GetPyRuntimeZipData() []byte {
+	if x != nil {
+		return x.PyRuntimeZipData
+	}
+	return nil
+}
+
+func (x *MetaArgs) GetPyRuntimeChecksum() string {
+	if x != nil {
+		return x.PyRuntimeChecksum
+	}
+	return ""
+}
+
+type OutputChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stream OutputChunk_Stream `protobuf:"varint,1,opt,name=stream,proto3,enum=gosible.proto.OutputChunk_Stream" json:"stream,omitempty"`
+	Data   []byte             `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *OutputChunk) Reset() {
+	*x = OutputChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutputChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputChunk) ProtoMessage() {}
+
+func (x *OutputChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputChunk.ProtoReflect.Descriptor instead.
+func (*OutputChunk) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OutputChunk) GetStream() OutputChunk_Stream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputChunk_STDOUT
+}
+
+func (x *OutputChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ProgressEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ProgressEvent) Reset() {
+	*x = ProgressEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProgressEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgressEvent) ProtoMessage() {}
+
+func (x *ProgressEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgressEvent.ProtoReflect.Descriptor instead.
+func (*ProgressEvent) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProgressEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ExecuteModuleEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*ExecuteModuleEvent_Output
+	//	*ExecuteModuleEvent_Progress
+	//	*ExecuteModuleEvent_Reply
+	Event isExecuteModuleEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *ExecuteModuleEvent) Reset() {
+	*x = ExecuteModuleEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteModuleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteModuleEvent) ProtoMessage() {}
+
+func (x *ExecuteModuleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteModuleEvent.ProtoReflect.Descriptor instead.
+func (*ExecuteModuleEvent) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{7}
+}
+
+func (m *ExecuteModuleEvent) GetEvent() isExecuteModuleEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *ExecuteModuleEvent) GetOutput() *OutputChunk {
+	if x, ok := x.GetEvent().(*ExecuteModuleEvent_Output); ok {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *ExecuteModuleEvent) GetProgress() *ProgressEvent {
+	if x, ok := x.GetEvent().(*ExecuteModuleEvent_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *ExecuteModuleEvent) GetReply() *ExecuteModuleReply {
+	if x, ok := x.GetEvent().(*ExecuteModuleEvent_Reply); ok {
+		return x.Reply
+	}
+	return nil
+}
+
+type isExecuteModuleEvent_Event interface {
+	isExecuteModuleEvent_Event()
+}
+
+type ExecuteModuleEvent_Output struct {
+	Output *OutputChunk `protobuf:"bytes,1,opt,name=output,proto3,oneof"`
+}
+
+type ExecuteModuleEvent_Progress struct {
+	Progress *ProgressEvent `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+type ExecuteModuleEvent_Reply struct {
+	Reply *ExecuteModuleReply `protobuf:"bytes,3,opt,name=reply,proto3,oneof"`
+}
+
+func (*ExecuteModuleEvent_Output) isExecuteModuleEvent_Event() {}
+
+func (*ExecuteModuleEvent_Progress) isExecuteModuleEvent_Event() {}
+
+func (*ExecuteModuleEvent_Reply) isExecuteModuleEvent_Event() {}
+
+type FileAttributes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path      string  `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode      *uint32 `protobuf:"varint,2,opt,name=mode,proto3,oneof" json:"mode,omitempty"`
+	Owner     string  `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Group     string  `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+	SeContext string  `protobuf:"bytes,5,opt,name=seContext,proto3" json:"seContext,omitempty"`
+}
+
+func (x *FileAttributes) Reset() {
+	*x = FileAttributes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileAttributes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileAttributes) ProtoMessage() {}
+
+func (x *FileAttributes) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileAttributes.ProtoReflect.Descriptor instead.
+func (*FileAttributes) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FileAttributes) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileAttributes) GetMode() uint32 {
+	if x != nil && x.Mode != nil {
+		return *x.Mode
+	}
+	return 0
+}
+
+func (x *FileAttributes) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *FileAttributes) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *FileAttributes) GetSeContext() string {
+	if x != nil {
+		return x.SeContext
+	}
+	return ""
+}
+
+type FileInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path       string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Exists     bool   `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	IsDir      bool   `protobuf:"varint,3,opt,name=isDir,proto3" json:"isDir,omitempty"`
+	IsLink     bool   `protobuf:"varint,4,opt,name=isLink,proto3" json:"isLink,omitempty"`
+	LinkTarget string `protobuf:"bytes,5,opt,name=linkTarget,proto3" json:"linkTarget,omitempty"`
+	Size       int64  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	Mode       uint32 `protobuf:"varint,7,opt,name=mode,proto3" json:"mode,omitempty"`
+	Uid        uint32 `protobuf:"varint,8,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid        uint32 `protobuf:"varint,9,opt,name=gid,proto3" json:"gid,omitempty"`
+	Owner      string `protobuf:"bytes,10,opt,name=owner,proto3" json:"owner,omitempty"`
+	Group      string `protobuf:"bytes,11,opt,name=group,proto3" json:"group,omitempty"`
+	Mtime      int64  `protobuf:"varint,12,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	SeContext  string `protobuf:"bytes,13,opt,name=seContext,proto3" json:"seContext,omitempty"`
+	Checksum   string `protobuf:"bytes,14,opt,name=checksum,proto3" json:"checksum,omitempty"`
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FileInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileInfo) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+func (x *FileInfo) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *FileInfo) GetIsLink() bool {
+	if x != nil {
+		return x.IsLink
+	}
+	return false
+}
+
+func (x *FileInfo) GetLinkTarget() string {
+	if x != nil {
+		return x.LinkTarget
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *FileInfo) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *FileInfo) GetGid() uint32 {
+	if x != nil {
+		return x.Gid
+	}
+	return 0
+}
+
+func (x *FileInfo) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *FileInfo) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *FileInfo) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+func (x *FileInfo) GetSeContext() string {
+	if x != nil {
+		return x.SeContext
+	}
+	return ""
+}
+
+func (x *FileInfo) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+type PutFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Part:
+	//	*PutFileRequest_Attributes
+	//	*PutFileRequest_Data
+	//	*PutFileRequest_Checksum
+	Part isPutFileRequest_Part `protobuf_oneof:"part"`
+}
+
+func (x *PutFileRequest) Reset() {
+	*x = PutFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PutFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutFileRequest) ProtoMessage() {}
+
+func (x *PutFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutFileRequest.ProtoReflect.Descriptor instead.
+func (*PutFileRequest) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{10}
+}
+
+func (m *PutFileRequest) GetPart() isPutFileRequest_Part {
+	if m != nil {
+		return m.Part
+	}
+	return nil
+}
+
+func (x *PutFileRequest) GetAttributes() *FileAttributes {
+	if x, ok := x.GetPart().(*PutFileRequest_Attributes); ok {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *PutFileRequest) GetData() []byte {
+	if x, ok := x.GetPart().(*PutFileRequest_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PutFileRequest) GetChecksum() string {
+	if x, ok := x.GetPart().(*PutFileRequest_Checksum); ok {
+		return x.Checksum
+	}
+	return ""
+}
+
+type isPutFileRequest_Part interface {
+	isPutFileRequest_Part()
+}
+
+type PutFileRequest_Attributes struct {
+	Attributes *FileAttributes `protobuf:"bytes,1,opt,name=attributes,proto3,oneof"`
+}
+
+type PutFileRequest_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+type PutFileRequest_Checksum struct {
+	Checksum string `protobuf:"bytes,3,opt,name=checksum,proto3,oneof"`
+}
+
+func (*PutFileRequest_Attributes) isPutFileRequest_Part() {}
+
+func (*PutFileRequest_Data) isPutFileRequest_Part() {}
+
+func (*PutFileRequest_Checksum) isPutFileRequest_Part() {}
+
+type GetFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *GetFileRequest) Reset() {
+	*x = GetFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileRequest) ProtoMessage() {}
+
+func (x *GetFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileRequest.ProtoReflect.Descriptor instead.
+func (*GetFileRequest) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type GetFileReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Part:
+	//	*GetFileReply_Info
+	//	*GetFileReply_Data
+	//	*GetFileReply_Checksum
+	Part isGetFileReply_Part `protobuf_oneof:"part"`
 }
 
-func (x *ExecuteModuleRequest) Reset() {
-	*x = ExecuteModuleRequest{}
+func (x *GetFileReply) Reset() {
+	*x = GetFileReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_gosible_proto_msgTypes[0]
+		mi := &file_remote_proto_gosible_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ExecuteModuleRequest) String() string {
+func (x *GetFileReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteModuleRequest) ProtoMessage() {}
+func (*GetFileReply) ProtoMessage() {}
 
-func (x *ExecuteModuleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_gosible_proto_msgTypes[0]
+func (x *GetFileReply) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,57 +987,150 @@ func (x *ExecuteModuleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteModuleRequest.ProtoReflect.Descriptor instead.
-func (*ExecuteModuleRequest) Descriptor() ([]byte, []int) {
-	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use GetFileReply.ProtoReflect.Descriptor instead.
+func (*GetFileReply) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ExecuteModuleRequest) GetModuleName() string {
+func (m *GetFileReply) GetPart() isGetFileReply_Part {
+	if m != nil {
+		return m.Part
+	}
+	return nil
+}
+
+func (x *GetFileReply) GetInfo() *FileInfo {
+	if x, ok := x.GetPart().(*GetFileReply_Info); ok {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *GetFileReply) GetData() []byte {
+	if x, ok := x.GetPart().(*GetFileReply_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetFileReply) GetChecksum() string {
+	if x, ok := x.GetPart().(*GetFileReply_Checksum); ok {
+		return x.Checksum
+	}
+	return ""
+}
+
+type isGetFileReply_Part interface {
+	isGetFileReply_Part()
+}
+
+type GetFileReply_Info struct {
+	Info *FileInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type GetFileReply_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
+}
+
+type GetFileReply_Checksum struct {
+	Checksum string `protobuf:"bytes,3,opt,name=checksum,proto3,oneof"`
+}
+
+func (*GetFileReply_Info) isGetFileReply_Part() {}
+
+func (*GetFileReply_Data) isGetFileReply_Part() {}
+
+func (*GetFileReply_Checksum) isGetFileReply_Part() {}
+
+type StatFileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path           string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	FollowSymlinks bool   `protobuf:"varint,2,opt,name=followSymlinks,proto3" json:"followSymlinks,omitempty"`
+	Checksum       bool   `protobuf:"varint,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+}
+
+func (x *StatFileRequest) Reset() {
+	*x = StatFileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_remote_proto_gosible_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatFileRequest) ProtoMessage() {}
+
+func (x *StatFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatFileRequest.ProtoReflect.Descriptor instead.
+func (*StatFileRequest) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StatFileRequest) GetPath() string {
 	if x != nil {
-		return x.ModuleName
+		return x.Path
 	}
 	return ""
 }
 
-func (x *ExecuteModuleRequest) GetVarsJson() []byte {
+func (x *StatFileRequest) GetFollowSymlinks() bool {
 	if x != nil {
-		return x.VarsJson
+		return x.FollowSymlinks
 	}
-	return nil
+	return false
 }
 
-func (x *ExecuteModuleRequest) GetMetaArgs() *MetaArgs {
+func (x *StatFileRequest) GetChecksum() bool {
 	if x != nil {
-		return x.MetaArgs
+		return x.Checksum
 	}
-	return nil
+	return false
 }
 
-type ExecuteModuleReply struct {
+type DiscoverPythonInterpreterRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ReturnValueJson []byte `protobuf:"bytes,1,opt,name=returnValueJson,proto3" json:"returnValueJson,omitempty"`
+	Mode     string   `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	Fallback []string `protobuf:"bytes,2,rep,name=fallback,proto3" json:"fallback,omitempty"`
 }
 
-func (x *ExecuteModuleReply) Reset() {
-	*x = ExecuteModuleReply{}
+func (x *DiscoverPythonInterpreterRequest) Reset() {
+	*x = DiscoverPythonInterpreterRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_gosible_proto_msgTypes[1]
+		mi := &file_remote_proto_gosible_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ExecuteModuleReply) String() string {
+func (x *DiscoverPythonInterpreterRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteModuleReply) ProtoMessage() {}
+func (*DiscoverPythonInterpreterRequest) ProtoMessage() {}
 
-func (x *ExecuteModuleReply) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_gosible_proto_msgTypes[1]
+func (x *DiscoverPythonInterpreterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -118,44 +1141,51 @@ func (x *ExecuteModuleReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteModuleReply.ProtoReflect.Descriptor instead.
-func (*ExecuteModuleReply) Descriptor() ([]byte, []int) {
-	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use DiscoverPythonInterpreterRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverPythonInterpreterRequest) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ExecuteModuleReply) GetReturnValueJson() []byte {
+func (x *DiscoverPythonInterpreterRequest) GetMode() string {
 	if x != nil {
-		return x.ReturnValueJson
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *DiscoverPythonInterpreterRequest) GetFallback() []string {
+	if x != nil {
+		return x.Fallback
 	}
 	return nil
 }
 
-type MetaArgs struct {
+type DiscoverPythonInterpreterReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PythonInterpreter string `protobuf:"bytes,1,opt,name=pythonInterpreter,proto3" json:"pythonInterpreter,omitempty"`
-	PyRuntimeZipData  []byte `protobuf:"bytes,2,opt,name=pyRuntimeZipData,proto3" json:"pyRuntimeZipData,omitempty"`
+	Interpreter string   `protobuf:"bytes,1,opt,name=interpreter,proto3" json:"interpreter,omitempty"`
+	Warnings    []string `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
-func (x *MetaArgs) Reset() {
-	*x = MetaArgs{}
+func (x *DiscoverPythonInterpreterReply) Reset() {
+	*x = DiscoverPythonInterpreterReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_remote_proto_gosible_proto_msgTypes[2]
+		mi := &file_remote_proto_gosible_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MetaArgs) String() string {
+func (x *DiscoverPythonInterpreterReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MetaArgs) ProtoMessage() {}
+func (*DiscoverPythonInterpreterReply) ProtoMessage() {}
 
-func (x *MetaArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_remote_proto_gosible_proto_msgTypes[2]
+func (x *DiscoverPythonInterpreterReply) ProtoReflect() protoreflect.Message {
+	mi := &file_remote_proto_gosible_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -166,21 +1196,21 @@ func (x *MetaArgs) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MetaArgs.ProtoReflect.Descriptor instead.
-func (*MetaArgs) Descriptor() ([]byte, []int) {
-	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use DiscoverPythonInterpreterReply.ProtoReflect.Descriptor instead.
+func (*DiscoverPythonInterpreterReply) Descriptor() ([]byte, []int) {
+	return file_remote_proto_gosible_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *MetaArgs) GetPythonInterpreter() string {
+func (x *DiscoverPythonInterpreterReply) GetInterpreter() string {
 	if x != nil {
-		return x.PythonInterpreter
+		return x.Interpreter
 	}
 	return ""
 }
 
-func (x *MetaArgs) GetPyRuntimeZipData() []byte {
+func (x *DiscoverPythonInterpreterReply) GetWarnings() []string {
 	if x != nil {
-		return x.PyRuntimeZipData
+		return x.Warnings
 	}
 	return nil
 }
@@ -190,36 +1220,179 @@ var File_remote_proto_gosible_proto protoreflect.FileDescriptor
 var file_remote_proto_gosible_proto_rawDesc = []byte{
 	0x0a, 0x1a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67,
 	0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x67, 0x6f,
-	0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x87, 0x01, 0x0a, 0x14,
-	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x73, 0x4a, 0x73, 0x6f, 0x6e,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x76, 0x61, 0x72, 0x73, 0x4a, 0x73, 0x6f, 0x6e,
-	0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x41, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x41, 0x72, 0x67, 0x73, 0x52, 0x08, 0x6d, 0x65, 0x74,
-	0x61, 0x41, 0x72, 0x67, 0x73, 0x22, 0x3e, 0x0a, 0x12, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
-	0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x72,
-	0x65, 0x74, 0x75, 0x72, 0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4a, 0x73, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x56, 0x61, 0x6c, 0x75,
-	0x65, 0x4a, 0x73, 0x6f, 0x6e, 0x22, 0x64, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x41, 0x72, 0x67,
-	0x73, 0x12, 0x2c, 0x0a, 0x11, 0x70, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72,
-	0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x70, 0x79,
-	0x74, 0x68, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x12,
-	0x2a, 0x0a, 0x10, 0x70, 0x79, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5a, 0x69, 0x70, 0x44,
-	0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x70, 0x79, 0x52, 0x75, 0x6e,
-	0x74, 0x69, 0x6d, 0x65, 0x5a, 0x69, 0x70, 0x44, 0x61, 0x74, 0x61, 0x32, 0x6a, 0x0a, 0x0d, 0x47,
-	0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x59, 0x0a, 0x0d,
-	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x23, 0x2e,
-	0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x21, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x66, 0x0a, 0x0c, 0x48,
+	0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x63,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c,
+	0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0xaa, 0x01, 0x0a, 0x0a, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02,
+	0x6f, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x61, 0x72, 0x63, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x63, 0x68,
+	0x22, 0x87, 0x01, 0x0a, 0x14, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x6f, 0x64,
+	0x75, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d,
+	0x6f, 0x64, 0x75, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x76, 0x61, 0x72,
+	0x73, 0x4a, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x76, 0x61, 0x72,
+	0x73, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x41, 0x72, 0x67,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x41, 0x72, 0x67, 0x73,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x41, 0x72, 0x67, 0x73, 0x22, 0x3e, 0x0a, 0x12, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4a,
+	0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x72, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x4a, 0x73, 0x6f, 0x6e, 0x22, 0x92, 0x01, 0x0a, 0x08, 0x4d,
+	0x65, 0x74, 0x61, 0x41, 0x72, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x70, 0x79, 0x74, 0x68, 0x6f,
+	0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x11, 0x70, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70,
+	0x72, 0x65, 0x74, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x10, 0x70, 0x79, 0x52, 0x75, 0x6e, 0x74, 0x69,
+	0x6d, 0x65, 0x5a, 0x69, 0x70, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x10, 0x70, 0x79, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x5a, 0x69, 0x70, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x2c, 0x0a, 0x11, 0x70, 0x79, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x70, 0x79,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22,
+	0x7e, 0x0a, 0x0b, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x39,
+	0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21,
+	0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x20, 0x0a,
+	0x06, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x44, 0x4f, 0x55,
+	0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x01, 0x22,
+	0x29, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xca, 0x01, 0x0a, 0x12, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x34, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52,
+	0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x3a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x73, 0x69,
+	0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x39, 0x0a, 0x05, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x79, 0x6c, 0x6c, 0x61, 0x64, 0x62, 0x2f, 0x67,
-	0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x48, 0x00, 0x52, 0x05, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x42, 0x07,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x90, 0x01, 0x0a, 0x0e, 0x46, 0x69, 0x6c, 0x65,
+	0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x17,
+	0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x04,
+	0x6d, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0xcc, 0x02, 0x0a, 0x08, 0x46,
+	0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x65,
+	0x78, 0x69, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x65, 0x78, 0x69,
+	0x73, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x73, 0x44, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x44, 0x69, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x4c,
+	0x69, 0x6e, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x4c, 0x69, 0x6e,
+	0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x67,
+	0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x67, 0x69, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x74, 0x69,
+	0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6d, 0x74, 0x69, 0x6d, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22, 0x8d, 0x01, 0x0a, 0x0e, 0x50, 0x75,
+	0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0a,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x48,
+	0x00, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x14, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75,
+	0x6d, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x22, 0x24, 0x0a, 0x0e, 0x47, 0x65, 0x74,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22,
+	0x79, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x2d, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x00, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x14,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x75, 0x6d, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x22, 0x69, 0x0a, 0x0f, 0x53, 0x74,
+	0x61, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x26, 0x0a, 0x0e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x79, 0x6d, 0x6c, 0x69,
+	0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f,
+	0x77, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22, 0x52, 0x0a, 0x20, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x50, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x22, 0x5e, 0x0a, 0x1e, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x50, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x32, 0xe8, 0x04, 0x0a, 0x0d, 0x47, 0x6f,
+	0x73, 0x69, 0x62, 0x6c, 0x65, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x41, 0x0a, 0x05, 0x48,
+	0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x1b, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x59,
+	0x0a, 0x0d, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12,
+	0x23, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x13, 0x45, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x12, 0x23, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64,
+	0x75, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x45, 0x0a, 0x07,
+	0x50, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x22,
+	0x00, 0x28, 0x01, 0x12, 0x49, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1d,
+	0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
+	0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
+	0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65,
+	0x74, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x30, 0x01, 0x12, 0x45,
+	0x0a, 0x08, 0x53, 0x74, 0x61, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x73,
+	0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x46,
+	0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x67, 0x6f, 0x73,
+	0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x7d, 0x0a, 0x19, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x50, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74,
+	0x65, 0x72, 0x12, 0x2f, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x50, 0x79, 0x74, 0x68, 0x6f,
+	0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x67, 0x6f, 0x73, 0x69, 0x62, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x50, 0x79, 0x74, 0x68,
+	0x6f, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x72, 0x65, 0x74, 0x65, 0x72, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x79, 0x6c, 0x6c, 0x61, 0x64, 0x62, 0x2f, 0x67, 0x6f, 0x73, 0x69,
+	0x62, 0x6c, 0x65, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -234,21 +1407,54 @@ func file_remote_proto_gosible_proto_rawDescGZIP() []byte {
 	return file_remote_proto_gosible_proto_rawDescData
 }
 
-var file_remote_proto_gosible_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_remote_proto_gosible_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_remote_proto_gosible_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_remote_proto_gosible_proto_goTypes = []interface{}{
-	(*ExecuteModuleRequest)(nil), // 0: gosible.proto.ExecuteModuleRequest
-	(*ExecuteModuleReply)(nil),   // 1: gosible.proto.ExecuteModuleReply
-	(*MetaArgs)(nil),             // 2: gosible.proto.MetaArgs
+	(OutputChunk_Stream)(0),                  // 0: gosible.proto.OutputChunk.Stream
+	(*HelloRequest)(nil),                     // 1: gosible.proto.HelloRequest
+	(*HelloReply)(nil),                       // 2: gosible.proto.HelloReply
+	(*ExecuteModuleRequest)(nil),             // 3: gosible.proto.ExecuteModuleRequest
+	(*ExecuteModuleReply)(nil),               // 4: gosible.proto.ExecuteModuleReply
+	(*MetaArgs)(nil),                         // 5: gosible.proto.MetaArgs
+	(*OutputChunk)(nil),                      // 6: gosible.proto.OutputChunk
+	(*ProgressEvent)(nil),                    // 7: gosible.proto.ProgressEvent
+	(*ExecuteModuleEvent)(nil),               // 8: gosible.proto.ExecuteModuleEvent
+	(*FileAttributes)(nil),                   // 9: gosible.proto.FileAttributes
+	(*FileInfo)(nil),                         // 10: gosible.proto.FileInfo
+	(*PutFileRequest)(nil),                   // 11: gosible.proto.PutFileRequest
+	(*GetFileRequest)(nil),                   // 12: gosible.proto.GetFileRequest
+	(*GetFileReply)(nil),                     // 13: gosible.proto.GetFileReply
+	(*StatFileRequest)(nil),                  // 14: gosible.proto.StatFileRequest
+	(*DiscoverPythonInterpreterRequest)(nil), // 15: gosible.proto.DiscoverPythonInterpreterRequest
+	(*DiscoverPythonInterpreterReply)(nil),   // 16: gosible.proto.DiscoverPythonInterpreterReply
 }
 var file_remote_proto_gosible_proto_depIdxs = []int32{
-	2, // 0: gosible.proto.ExecuteModuleRequest.metaArgs:type_name -> gosible.proto.MetaArgs
-	0, // 1: gosible.proto.GosibleClient.ExecuteModule:input_type -> gosible.proto.ExecuteModuleRequest
-	1, // 2: gosible.proto.GosibleClient.ExecuteModule:output_type -> gosible.proto.ExecuteModuleReply
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5,  // 0: gosible.proto.ExecuteModuleRequest.metaArgs:type_name -> gosible.proto.MetaArgs
+	0,  // 1: gosible.proto.OutputChunk.stream:type_name -> gosible.proto.OutputChunk.Stream
+	6,  // 2: gosible.proto.ExecuteModuleEvent.output:type_name -> gosible.proto.OutputChunk
+	7,  // 3: gosible.proto.ExecuteModuleEvent.progress:type_name -> gosible.proto.ProgressEvent
+	4,  // 4: gosible.proto.ExecuteModuleEvent.reply:type_name -> gosible.proto.ExecuteModuleReply
+	9,  // 5: gosible.proto.PutFileRequest.attributes:type_name -> gosible.proto.FileAttributes
+	10, // 6: gosible.proto.GetFileReply.info:type_name -> gosible.proto.FileInfo
+	1,  // 7: gosible.proto.GosibleClient.Hello:input_type -> gosible.proto.HelloRequest
+	3,  // 8: gosible.proto.GosibleClient.ExecuteModule:input_type -> gosible.proto.ExecuteModuleRequest
+	3,  // 9: gosible.proto.GosibleClient.ExecuteModuleStream:input_type -> gosible.proto.ExecuteModuleRequest
+	11, // 10: gosible.proto.GosibleClient.PutFile:input_type -> gosible.proto.PutFileRequest
+	12, // 11: gosible.proto.GosibleClient.GetFile:input_type -> gosible.proto.GetFileRequest
+	14, // 12: gosible.proto.GosibleClient.StatFile:input_type -> gosible.proto.StatFileRequest
+	15, // 13: gosible.proto.GosibleClient.DiscoverPythonInterpreter:input_type -> gosible.proto.DiscoverPythonInterpreterRequest
+	2,  // 14: gosible.proto.GosibleClient.Hello:output_type -> gosible.proto.HelloReply
+	4,  // 15: gosible.proto.GosibleClient.ExecuteModule:output_type -> gosible.proto.ExecuteModuleReply
+	8,  // 16: gosible.proto.GosibleClient.ExecuteModuleStream:output_type -> gosible.proto.ExecuteModuleEvent
+	10, // 17: gosible.proto.GosibleClient.PutFile:output_type -> gosible.proto.FileInfo
+	13, // 18: gosible.proto.GosibleClient.GetFile:output_type -> gosible.proto.GetFileReply
+	10, // 19: gosible.proto.GosibleClient.StatFile:output_type -> gosible.proto.FileInfo
+	16, // 20: gosible.proto.GosibleClient.DiscoverPythonInterpreter:output_type -> gosible.proto.DiscoverPythonInterpreterReply
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_remote_proto_gosible_proto_init() }
@@ -258,7 +1464,7 @@ func file_remote_proto_gosible_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_remote_proto_gosible_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteModuleRequest); i {
+			switch v := v.(*HelloRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -270,7 +1476,7 @@ func file_remote_proto_gosible_proto_init() {
 			}
 		}
 		file_remote_proto_gosible_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteModuleReply); i {
+			switch v := v.(*HelloReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -282,6 +1488,30 @@ func file_remote_proto_gosible_proto_init() {
 			}
 		}
 		file_remote_proto_gosible_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteModuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteModuleReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MetaArgs); i {
 			case 0:
 				return &v.state
@@ -293,19 +1523,168 @@ func file_remote_proto_gosible_proto_init() {
 				return nil
 			}
 		}
+		file_remote_proto_gosible_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProgressEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteModuleEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileAttributes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PutFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatFileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiscoverPythonInterpreterRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_remote_proto_gosible_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiscoverPythonInterpreterReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_remote_proto_gosible_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*ExecuteModuleEvent_Output)(nil),
+		(*ExecuteModuleEvent_Progress)(nil),
+		(*ExecuteModuleEvent_Reply)(nil),
+	}
+	file_remote_proto_gosible_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_remote_proto_gosible_proto_msgTypes[10].OneofWrappers = []interface{}{
+		(*PutFileRequest_Attributes)(nil),
+		(*PutFileRequest_Data)(nil),
+		(*PutFileRequest_Checksum)(nil),
+	}
+	file_remote_proto_gosible_proto_msgTypes[12].OneofWrappers = []interface{}{
+		(*GetFileReply_Info)(nil),
+		(*GetFileReply_Data)(nil),
+		(*GetFileReply_Checksum)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_remote_proto_gosible_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_remote_proto_gosible_proto_goTypes,
 		DependencyIndexes: file_remote_proto_gosible_proto_depIdxs,
+		EnumInfos:         file_remote_proto_gosible_proto_enumTypes,
 		MessageInfos:      file_remote_proto_gosible_proto_msgTypes,
 	}.Build()
 	File_remote_proto_gosible_proto = out.File
@@ -22,7 +22,13 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GosibleClientClient interface {
+	Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloReply, error)
 	ExecuteModule(ctx context.Context, in *ExecuteModuleRequest, opts ...grpc.CallOption) (*ExecuteModuleReply, error)
+	ExecuteModuleStream(ctx context.Context, in *ExecuteModuleRequest, opts ...grpc.CallOption) (GosibleClient_ExecuteModuleStreamClient, error)
+	PutFile(ctx context.Context, opts ...grpc.CallOption) (GosibleClient_PutFileClient, error)
+	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (GosibleClient_GetFileClient, error)
+	StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*FileInfo, error)
+	DiscoverPythonInterpreter(ctx context.Context, in *DiscoverPythonInterpreterRequest, opts ...grpc.CallOption) (*DiscoverPythonInterpreterReply, error)
 }
 
 type gosibleClientClient struct {
@@ -33,6 +39,15 @@ func NewGosibleClientClient(cc grpc.ClientConnInterface) GosibleClientClient {
 	return &gosibleClientClient{cc}
 }
 
+func (c *gosibleClientClient) Hello(ctx context.Context, in *HelloRequest, opts ...grpc.CallOption) (*HelloReply, error) {
+	out := new(HelloReply)
+	err := c.cc.Invoke(ctx, "/gosible.proto.GosibleClient/Hello", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gosibleClientClient) ExecuteModule(ctx context.Context, in *ExecuteModuleRequest, opts ...grpc.CallOption) (*ExecuteModuleReply, error) {
 	out := new(ExecuteModuleReply)
 	err := c.cc.Invoke(ctx, "/gosible.proto.GosibleClient/ExecuteModule", in, out, opts...)
@@ -42,11 +57,133 @@ func (c *gosibleClientClient) ExecuteModule(ctx context.Context, in *ExecuteModu
 	return out, nil
 }
 
+func (c *gosibleClientClient) ExecuteModuleStream(ctx context.Context, in *ExecuteModuleRequest, opts ...grpc.CallOption) (GosibleClient_ExecuteModuleStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GosibleClient_ServiceDesc.Streams[0], "/gosible.proto.GosibleClient/ExecuteModuleStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gosibleClientExecuteModuleStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GosibleClient_ExecuteModuleStreamClient interface {
+	Recv() (*ExecuteModuleEvent, error)
+	grpc.ClientStream
+}
+
+type gosibleClientExecuteModuleStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *gosibleClientExecuteModuleStreamClient) Recv() (*ExecuteModuleEvent, error) {
+	m := new(ExecuteModuleEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gosibleClientClient) PutFile(ctx context.Context, opts ...grpc.CallOption) (GosibleClient_PutFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GosibleClient_ServiceDesc.Streams[1], "/gosible.proto.GosibleClient/PutFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gosibleClientPutFileClient{stream}
+	return x, nil
+}
+
+type GosibleClient_PutFileClient interface {
+	Send(*PutFileRequest) error
+	CloseAndRecv() (*FileInfo, error)
+	grpc.ClientStream
+}
+
+type gosibleClientPutFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *gosibleClientPutFileClient) Send(m *PutFileRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gosibleClientPutFileClient) CloseAndRecv() (*FileInfo, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(FileInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gosibleClientClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (GosibleClient_GetFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GosibleClient_ServiceDesc.Streams[2], "/gosible.proto.GosibleClient/GetFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gosibleClientGetFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GosibleClient_GetFileClient interface {
+	Recv() (*GetFileReply, error)
+	grpc.ClientStream
+}
+
+type gosibleClientGetFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *gosibleClientGetFileClient) Recv() (*GetFileReply, error) {
+	m := new(GetFileReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gosibleClientClient) StatFile(ctx context.Context, in *StatFileRequest, opts ...grpc.CallOption) (*FileInfo, error) {
+	out := new(FileInfo)
+	err := c.cc.Invoke(ctx, "/gosible.proto.GosibleClient/StatFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gosibleClientClient) DiscoverPythonInterpreter(ctx context.Context, in *DiscoverPythonInterpreterRequest, opts ...grpc.CallOption) (*DiscoverPythonInterpreterReply, error) {
+	out := new(DiscoverPythonInterpreterReply)
+	err := c.cc.Invoke(ctx, "/gosible.proto.GosibleClient/DiscoverPythonInterpreter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GosibleClientServer is the server API for GosibleClient service.
 // All implementations must embed UnimplementedGosibleClientServer
 // for forward compatibility
 type GosibleClientServer interface {
+	Hello(context.Context, *HelloRequest) (*HelloReply, error)
 	ExecuteModule(context.Context, *ExecuteModuleRequest) (*ExecuteModuleReply, error)
+	ExecuteModuleStream(*ExecuteModuleRequest, GosibleClient_ExecuteModuleStreamServer) error
+	PutFile(GosibleClient_PutFileServer) error
+	GetFile(*GetFileRequest, GosibleClient_GetFileServer) error
+	StatFile(context.Context, *StatFileRequest) (*FileInfo, error)
+	DiscoverPythonInterpreter(context.Context, *DiscoverPythonInterpreterRequest) (*DiscoverPythonInterpreterReply, error)
 	mustEmbedUnimplementedGosibleClientServer()
 }
 
@@ -54,9 +191,27 @@ type GosibleClientServer interface {
 type UnimplementedGosibleClientServer struct {
 }
 
+func (UnimplementedGosibleClientServer) Hello(context.Context, *HelloRequest) (*HelloReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hello not implemented")
+}
 func (UnimplementedGosibleClientServer) ExecuteModule(context.Context, *ExecuteModuleRequest) (*ExecuteModuleReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecuteModule not implemented")
 }
+func (UnimplementedGosibleClientServer) ExecuteModuleStream(*ExecuteModuleRequest, GosibleClient_ExecuteModuleStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteModuleStream not implemented")
+}
+func (UnimplementedGosibleClientServer) PutFile(GosibleClient_PutFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method PutFile not implemented")
+}
+func (UnimplementedGosibleClientServer) GetFile(*GetFileRequest, GosibleClient_GetFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetFile not implemented")
+}
+func (UnimplementedGosibleClientServer) StatFile(context.Context, *StatFileRequest) (*FileInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StatFile not implemented")
+}
+func (UnimplementedGosibleClientServer) DiscoverPythonInterpreter(context.Context, *DiscoverPythonInterpreterRequest) (*DiscoverPythonInterpreterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverPythonInterpreter not implemented")
+}
 func (UnimplementedGosibleClientServer) mustEmbedUnimplementedGosibleClientServer() {}
 
 // UnsafeGosibleClientServer may be embedded to opt out of forward compatibility for this service.
@@ -70,6 +225,24 @@ func RegisterGosibleClientServer(s grpc.ServiceRegistrar, srv GosibleClientServe
 	s.RegisterService(&GosibleClient_ServiceDesc, srv)
 }
 
+func _GosibleClient_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HelloRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GosibleClientServer).Hello(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gosible.proto.GosibleClient/Hello",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GosibleClientServer).Hello(ctx, req.(*HelloRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GosibleClient_ExecuteModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExecuteModuleRequest)
 	if err := dec(in); err != nil {
@@ -88,6 +261,110 @@ func _GosibleClient_ExecuteModule_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GosibleClient_ExecuteModuleStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteModuleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GosibleClientServer).ExecuteModuleStream(m, &gosibleClientExecuteModuleStreamServer{stream})
+}
+
+type GosibleClient_ExecuteModuleStreamServer interface {
+	Send(*ExecuteModuleEvent) error
+	grpc.ServerStream
+}
+
+type gosibleClientExecuteModuleStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *gosibleClientExecuteModuleStreamServer) Send(m *ExecuteModuleEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GosibleClient_PutFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GosibleClientServer).PutFile(&gosibleClientPutFileServer{stream})
+}
+
+type GosibleClient_PutFileServer interface {
+	SendAndClose(*FileInfo) error
+	Recv() (*PutFileRequest, error)
+	grpc.ServerStream
+}
+
+type gosibleClientPutFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *gosibleClientPutFileServer) SendAndClose(m *FileInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gosibleClientPutFileServer) Recv() (*PutFileRequest, error) {
+	m := new(PutFileRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _GosibleClient_GetFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GosibleClientServer).GetFile(m, &gosibleClientGetFileServer{stream})
+}
+
+type GosibleClient_GetFileServer interface {
+	Send(*GetFileReply) error
+	grpc.ServerStream
+}
+
+type gosibleClientGetFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *gosibleClientGetFileServer) Send(m *GetFileReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GosibleClient_StatFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GosibleClientServer).StatFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gosible.proto.GosibleClient/StatFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GosibleClientServer).StatFile(ctx, req.(*StatFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GosibleClient_DiscoverPythonInterpreter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverPythonInterpreterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GosibleClientServer).DiscoverPythonInterpreter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gosible.proto.GosibleClient/DiscoverPythonInterpreter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GosibleClientServer).DiscoverPythonInterpreter(ctx, req.(*DiscoverPythonInterpreterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GosibleClient_ServiceDesc is the grpc.ServiceDesc for GosibleClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -95,11 +372,39 @@ var GosibleClient_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "gosible.proto.GosibleClient",
 	HandlerType: (*GosibleClientServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Hello",
+			Handler:    _GosibleClient_Hello_Handler,
+		},
 		{
 			MethodName: "ExecuteModule",
 			Handler:    _GosibleClient_ExecuteModule_Handler,
 		},
+		{
+			MethodName: "StatFile",
+			Handler:    _GosibleClient_StatFile_Handler,
+		},
+		{
+			MethodName: "DiscoverPythonInterpreter",
+			Handler:    _GosibleClient_DiscoverPythonInterpreter_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteModuleStream",
+			Handler:       _GosibleClient_ExecuteModuleStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PutFile",
+			Handler:       _GosibleClient_PutFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetFile",
+			Handler:       _GosibleClient_GetFile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "remote/proto/gosible.proto",
 }
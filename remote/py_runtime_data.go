@@ -1,16 +1,56 @@
 package remote
 
 import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/scylladb/gosible/module_utils/pythonModule"
+	"io"
 	"os"
 	"path"
+	"sort"
+	"sync"
 )
 
+// pythonRuntime holds the files added to the Python runtime on demand, e.g. modules of Ansible collections.
+// The runtime sent to hosts is then rebuilt with the files, which gives it a new checksum.
+var pythonRuntime = struct {
+	sync.Mutex
+	extra    map[string][]byte
+	data     []byte
+	checksum string
+}{extra: make(map[string][]byte)}
+
+// AddPythonRuntimeFiles adds the files to the Python runtime sent to hosts.
+func AddPythonRuntimeFiles(files []pythonModule.RuntimeFile) {
+	pythonRuntime.Lock()
+	defer pythonRuntime.Unlock()
+	for _, f := range files {
+		if data, ok := pythonRuntime.extra[f.Name]; ok && bytes.Equal(data, f.Data) {
+			continue
+		}
+		pythonRuntime.extra[f.Name] = f.Data
+		pythonRuntime.data = nil
+		pythonRuntime.checksum = ""
+	}
+}
+
 func ReadPythonRuntimeData() ([]byte, error) {
 	runtimePath, err := getPythonRuntimePath()
 	if err != nil {
 		return nil, err
 	}
-	return os.ReadFile(runtimePath)
+
+	pythonRuntime.Lock()
+	defer pythonRuntime.Unlock()
+	if len(pythonRuntime.extra) == 0 {
+		return os.ReadFile(runtimePath)
+	}
+	if err = buildPythonRuntime(runtimePath); err != nil {
+		return nil, err
+	}
+	return pythonRuntime.data, nil
 }
 
 func getPythonRuntimePath() (string, error) {
@@ -20,3 +60,83 @@ func getPythonRuntimePath() (string, error) {
 	}
 	return path.Join(gosiblePath, "remote", "py_runtime.zip"), nil
 }
+
+// PythonRuntimeChecksum returns the sha256 of the local Python runtime, which lets the remote executor reuse
+// a runtime stored by an earlier connection. Returns an empty string if the runtime is not available.
+func PythonRuntimeChecksum() string {
+	runtimePath, err := getPythonRuntimePath()
+	if err != nil {
+		return ""
+	}
+	if _, err = os.Stat(runtimePath); err != nil {
+		return ""
+	}
+
+	pythonRuntime.Lock()
+	defer pythonRuntime.Unlock()
+	if len(pythonRuntime.extra) > 0 {
+		if err = buildPythonRuntime(runtimePath); err != nil {
+			return ""
+		}
+		return pythonRuntime.checksum
+	}
+	sha, err := hashes.get(runtimePath)
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
+// buildPythonRuntime extends the runtime at runtimePath with the added files, unless it is already built.
+// The result depends only on the contents of the files, so that the checksum is stable across runs.
+func buildPythonRuntime(runtimePath string) error {
+	if pythonRuntime.data != nil {
+		return nil
+	}
+	data, err := extendZip(runtimePath, pythonRuntime.extra)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	pythonRuntime.data = data
+	pythonRuntime.checksum = hex.EncodeToString(sum[:])
+	return nil
+}
+
+func extendZip(zipPath string, extra map[string][]byte) ([]byte, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range r.File {
+		if _, ok := extra[f.Name]; ok {
+			continue
+		}
+		if err = w.Copy(f); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.Copy(fw, bytes.NewReader(extra[name])); err != nil {
+			return nil, err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package remote
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtendZip(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "py_runtime.zip")
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"py_runtime/__init__.py": "", "py_runtime/py_runtime.py": "old"} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(basePath, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	extra := map[string][]byte{
+		"py_runtime/py_runtime.py":                            []byte("new"),
+		"ansible_collections/a/b/plugins/modules/m.py":        []byte("module"),
+		"ansible_collections/a/b/plugins/modules/__init__.py": nil,
+	}
+	data, err := extendZip(basePath, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := extendZip(basePath, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Error("on extending the same runtime twice", "expected identical archives")
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		contents[f.Name] = string(content)
+	}
+	expected := map[string]string{
+		"py_runtime/__init__.py":                              "",
+		"py_runtime/py_runtime.py":                            "new",
+		"ansible_collections/a/b/plugins/modules/m.py":        "module",
+		"ansible_collections/a/b/plugins/modules/__init__.py": "",
+	}
+	if len(contents) != len(expected) {
+		t.Error("on", contents, "expected", expected)
+	}
+	for name, content := range expected {
+		if contents[name] != content {
+			t.Error("on", name, contents[name], "expected", content)
+		}
+	}
+}
@@ -3,60 +3,109 @@ package remote
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"github.com/scylladb/gosible/connection"
 	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/slices"
 	"github.com/scylladb/gosible/utils/types"
 	"strings"
 )
 
+// cacheDir is the remote directory keeping the remote executor binaries, one subdirectory per binary hash.
+const cacheDir = "$HOME/.cache/gosible_client"
+
 type systemInfo struct {
-	gosibleDir        string
-	gosibleBinExists  bool
-	gosibleBinPath    string
-	gosibleTmpBinPath string
-	uname             string
+	platform         Platform
+	localBinPath     string
+	gosibleDir       string
+	gosibleBinExists bool
+	gosibleBinPath   string
+	sha              string
 }
 
-func gatherSystemInfo(path string, conn connection.CommandExecutor) (si systemInfo, err error) {
-	sha256, err := getHashStr(path)
+func gatherSystemInfo(conn connection.CommandExecutor) (si systemInfo, err error) {
+	binaries, err := localBinaries()
 	if err != nil {
 		return
 	}
-	dirPath := "$HOME/.cache/gosible_client/" + sha256
-	runnerPath := "$DIR/" + ClientFileName
+	shas := make([]string, 0, len(binaries))
+	for _, sha := range binaries {
+		shas = append(shas, sha)
+	}
 
-	cmd := "DIR=\"" + dirPath + "\"; echo \"Dir=$DIR\"; echo \"Sys=$(uname -a)\"; ([ -e \"" + runnerPath + "\" ] && echo HasRunner=1); mkdir -p \"$DIR\"; chmod 1775 \"$DIR\""
+	cmd := "CACHE=\"" + cacheDir + "\"; echo \"Cache=$CACHE\"; echo \"Kernel=$(uname -s)\"; echo \"Machine=$(uname -m)\"; " +
+		"for SHA in " + strings.Join(shas, " ") + "; do [ -e \"$CACHE/$SHA/" + ClientFileName + "\" ] && echo \"HasRunner=$SHA\"; done; true"
 	display.Debug(nil, "Gather system information command: "+cmd)
 
 	stdout, _, err := conn.ExecCommand(cmd, nil, false, &types.BecomeArgs{})
 	if err != nil {
 		return
 	}
-	scanner := bufio.NewScanner(strings.NewReader(stdout.String()))
+	if si, err = parseSystemInfo(stdout.String(), binaries); err != nil {
+		return
+	}
+	if si.localBinPath, err = getBinaryPathFor(si.platform); err != nil {
+		return
+	}
+	if !si.gosibleBinExists {
+		err = createCacheDir(si.gosibleDir, conn)
+	}
+	return
+}
+
+func parseSystemInfo(output string, binaries map[Platform]string) (si systemInfo, err error) {
+	values := parseKeyValues(output)
+	cache := lastValue(values, "Cache")
+
+	if cache == "" {
+		err = errors.New("failed to resolve remote gosible directory")
+		return
+	}
+	if si.platform, err = parsePlatform(lastValue(values, "Kernel"), lastValue(values, "Machine")); err != nil {
+		return
+	}
+	sha, ok := binaries[si.platform]
+	if !ok {
+		err = fmt.Errorf("no remote executor binary for %s, build it with `make build`", si.platform)
+		return
+	}
+	si.sha = sha
+	si.gosibleDir = cache + "/" + sha
+	si.gosibleBinExists = slices.Contains(values["HasRunner"], sha)
+	si.gosibleBinPath = si.gosibleDir + "/" + ClientFileName
+	return
+}
+
+// parseKeyValues returns the values of `Key=value` lines printed by remote commands.
+func parseKeyValues(output string) map[string][]string {
+	values := make(map[string][]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
 		line := scanner.Text()
 		idx := strings.Index(line, "=")
 		if idx < 0 {
 			continue
 		}
-		key := line[:idx]
-		value := line[idx+1:]
-
-		switch key {
-		case "Sys":
-			si.uname = value
-		case "Dir":
-			si.gosibleDir = value
-		case "HasRunner":
-			si.gosibleBinExists = true
-		}
+		values[line[:idx]] = append(values[line[:idx]], line[idx+1:])
 	}
+	return values
+}
 
-	if si.gosibleDir == "" {
-		err = errors.New("failed to resolve remote gosible directory")
-		return
+func createCacheDir(dir string, conn connection.CommandExecutor) error {
+	cmd := "mkdir -p \"" + dir + "\" && chmod 1775 \"" + dir + "\""
+	_, _, err := conn.ExecCommand(cmd, nil, false, &types.BecomeArgs{})
+	return err
+}
+
+// detectPlatform returns the platform of the remote host.
+func detectPlatform(conn connection.CommandExecutor) (Platform, error) {
+	stdout, _, err := conn.ExecCommand("uname -s; uname -m", nil, false, &types.BecomeArgs{})
+	if err != nil {
+		return Platform{}, err
 	}
-	si.gosibleBinPath = si.gosibleDir + "/" + ClientFileName
-	si.gosibleTmpBinPath = si.gosibleDir + "/" + ClientTmpFileName
-	return
+	fields := strings.Fields(stdout.String())
+	if len(fields) != 2 {
+		return Platform{}, fmt.Errorf("unexpected uname output %q", stdout.String())
+	}
+	return parsePlatform(fields[0], fields[1])
 }
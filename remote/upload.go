@@ -0,0 +1,148 @@
+package remote
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"github.com/scylladb/gosible/connection"
+	"github.com/scylladb/gosible/utils/display"
+	"github.com/scylladb/gosible/utils/types"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// uploadChunkSize is the amount of data sent by a single command. An interrupted upload is resumed
+// from the data already stored on the remote host.
+const uploadChunkSize = 4 << 20
+
+// compressedBinaries keeps the gzip-compressed local binaries, so that they are compressed only once.
+var compressedBinaries = struct {
+	sync.Mutex
+	data map[string][]byte
+}{data: make(map[string][]byte)}
+
+func compressBinary(path string) ([]byte, error) {
+	compressedBinaries.Lock()
+	defer compressedBinaries.Unlock()
+
+	if data, ok := compressedBinaries.data[path]; ok {
+		return data, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read binary: %w", err)
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(raw); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	compressedBinaries.data[path] = buf.Bytes()
+	return buf.Bytes(), nil
+}
+
+// uploadBinary installs the local binary as dir/ClientFileName on the remote host and returns its path.
+// The binary is sent gzip-compressed when the remote host is able to decompress it. Data is appended to a partial
+// file, which lets a later upload continue where an interrupted one stopped. The result is verified against
+// the expected sha256 and atomically moved into place.
+func uploadBinary(conn connection.CommandExecutor, localPath, sha, dir string) (string, error) {
+	values, err := runScript(conn, "PART=\""+dir+"/"+ClientFileName+".gz.part\"; "+
+		"command -v gzip >/dev/null 2>&1 && echo Gzip=1; [ -f \"$PART\" ] && echo \"Part=$(wc -c < \"$PART\")\"; true", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var data []byte
+	part := dir + "/" + ClientFileName + ".part"
+	if lastValue(values, "Gzip") != "" {
+		part = dir + "/" + ClientFileName + ".gz.part"
+		data, err = compressBinary(localPath)
+	} else {
+		display.Debug(nil, "gzip is not available on host, sending the binary uncompressed")
+		data, err = os.ReadFile(localPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	offset := 0
+	if lastValue(values, "Gzip") != "" && lastValue(values, "Part") != "" {
+		if offset, err = strconv.Atoi(lastValue(values, "Part")); err != nil || offset > len(data) {
+			offset = 0
+		}
+		if offset > 0 {
+			display.Debug(nil, "Resuming the upload of the gosible subprogram binary at byte %d of %d", offset, len(data))
+		}
+	}
+
+	for offset < len(data) {
+		end := offset + uploadChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		redirect := ">>"
+		if offset == 0 {
+			redirect = ">"
+		}
+		// head reads exactly the chunk, as stdin of the command isn't closed after the data.
+		cmd := "head -c " + strconv.Itoa(end-offset) + " " + redirect + " \"" + part + "\""
+		if _, err = runScript(conn, cmd, bytes.NewReader(data[offset:end])); err != nil {
+			return "", fmt.Errorf("failed to upload binary: %w", err)
+		}
+		offset = end
+	}
+
+	return installUploaded(conn, part, lastValue(values, "Gzip") != "", sha, dir)
+}
+
+// installUploaded unpacks the uploaded partial file, verifies its checksum and moves it into place.
+func installUploaded(conn connection.CommandExecutor, part string, compressed bool, sha, dir string) (string, error) {
+	unpack := "mv -f \"" + part + "\" \"$TMP\""
+	if compressed {
+		unpack = "gzip -dc \"" + part + "\" > \"$TMP\""
+	}
+	binPath := dir + "/" + ClientFileName
+	values, err := runScript(conn, "TMP=\""+binPath+".$$\"; "+unpack+"; "+
+		"if command -v sha256sum >/dev/null 2>&1; then SUM=$(sha256sum \"$TMP\" | cut -d' ' -f1); "+
+		"elif command -v shasum >/dev/null 2>&1; then SUM=$(shasum -a 256 \"$TMP\" | cut -d' ' -f1); fi; "+
+		"echo \"Sum=$SUM\"; "+
+		"if [ -z \"$SUM\" ] || [ \"$SUM\" = \""+sha+"\" ]; then chmod 0555 \"$TMP\" && mv -f \"$TMP\" \""+binPath+"\" && echo Installed=1; fi; "+
+		"rm -f \"$TMP\" \""+part+"\"", nil)
+	if err != nil {
+		return "", err
+	}
+	if lastValue(values, "Installed") == "" {
+		if lastValue(values, "Sum") != "" && lastValue(values, "Sum") != sha {
+			return "", fmt.Errorf("checksum mismatch of the uploaded binary, expected %s, got %s", sha, lastValue(values, "Sum"))
+		}
+		return "", fmt.Errorf("failed to install the uploaded binary at %s", binPath)
+	}
+	if lastValue(values, "Sum") == "" {
+		display.Warning(display.WarnOptions{}, "Neither sha256sum nor shasum is available on host, uploaded binary was not verified")
+	}
+	return binPath, nil
+}
+
+// runScript executes the command and returns the `Key=value` pairs it printed.
+func runScript(conn connection.CommandExecutor, cmd string, inData *bytes.Reader) (map[string][]string, error) {
+	stdout, _, err := conn.ExecCommand(cmd, inData, false, &types.BecomeArgs{})
+	if err != nil {
+		return nil, err
+	}
+	return parseKeyValues(stdout.String()), nil
+}
+
+// lastValue returns the last value printed for the key, or an empty string.
+func lastValue(values map[string][]string, key string) string {
+	if v := values[key]; len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return ""
+}
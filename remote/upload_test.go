@@ -0,0 +1,110 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"github.com/scylladb/gosible/utils/shell"
+	"github.com/scylladb/gosible/utils/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// localExecutor runs commands with the local shell. Like connection.DefaultExecCommandImpl, it doesn't close
+// stdin after the data, commands still waiting for the end of input after a timeout fail.
+type localExecutor struct{}
+
+func (localExecutor) ExecCommand(cmd string, inData *bytes.Reader, _ bool, _ *types.BecomeArgs) (*bytes.Buffer, *bytes.Buffer, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("/bin/sh", "-c", cmd)
+	stdin, err := c.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err = c.Start(); err != nil {
+		return nil, nil, err
+	}
+	if inData != nil {
+		if _, err = io.Copy(stdin, inData); err != nil {
+			return nil, nil, err
+		}
+	}
+	done := make(chan error, 1)
+	go func() { done <- c.Wait() }()
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		_ = stdin.Close()
+		<-done
+		err = errors.New("command waited for the end of input")
+	}
+	return &stdout, &stderr, err
+}
+
+func (localExecutor) Shell() shell.Shell {
+	return shell.Default()
+}
+
+func (localExecutor) Close() error {
+	return nil
+}
+
+func TestUploadBinary(t *testing.T) {
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "local")
+	content := make([]byte, uploadChunkSize+1000)
+	if _, err := rand.Read(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(localPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sha, err := getHashStr(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteDir := filepath.Join(dir, "remote")
+	if err = os.Mkdir(remoteDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate an interrupted upload.
+	compressed, err := compressBinary(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part := filepath.Join(remoteDir, ClientFileName+".gz.part")
+	if err = os.WriteFile(part, compressed[:len(compressed)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	binPath, err := uploadBinary(localExecutor{}, localPath, sha, remoteDir)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	uploaded, err := os.ReadFile(binPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(uploaded, content) {
+		t.Error("uploaded binary differs from the local one")
+	}
+	if _, err = os.Stat(part); !os.IsNotExist(err) {
+		t.Error("expected partial file to be removed")
+	}
+
+	// Upload again over the existing binary.
+	if _, err = uploadBinary(localExecutor{}, localPath, sha, remoteDir); err != nil {
+		t.Fatal("unexpected error on reupload", err)
+	}
+
+	if _, err = uploadBinary(localExecutor{}, localPath, "bad", remoteDir); err == nil {
+		t.Error("expected checksum mismatch")
+	}
+}
@@ -0,0 +1,47 @@
+package testUtils
+
+import (
+	"context"
+	"github.com/scylladb/gosible/remote/fileServer"
+	pb "github.com/scylladb/gosible/remote/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+type fileTransferServer struct {
+	pb.UnimplementedGosibleClientServer
+	files fileServer.Server
+}
+
+func (s *fileTransferServer) PutFile(stream pb.GosibleClient_PutFileServer) error {
+	return s.files.PutFile(stream)
+}
+
+func (s *fileTransferServer) GetFile(req *pb.GetFileRequest, stream pb.GosibleClient_GetFileServer) error {
+	return s.files.GetFile(req, stream)
+}
+
+func (s *fileTransferServer) StatFile(ctx context.Context, req *pb.StatFileRequest) (*pb.FileInfo, error) {
+	return s.files.StatFile(ctx, req)
+}
+
+// NewFileTransferClient returns a client of an in-process server handling the file transfer RPCs on the local
+// filesystem, as the remote executor does on the host.
+func NewFileTransferClient(t *testing.T) pb.GosibleClientClient {
+	listener := bufconn.Listen(1 << 20)
+	grpcS := grpc.NewServer()
+	pb.RegisterGosibleClientServer(grpcS, &fileTransferServer{})
+	go func() { _ = grpcS.Serve(listener) }()
+	t.Cleanup(grpcS.Stop)
+
+	dialer := func(context.Context, string) (net.Conn, error) { return listener.Dial() }
+	conn, err := grpc.Dial("", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewGosibleClientClient(conn)
+}
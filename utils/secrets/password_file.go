@@ -0,0 +1,55 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// ReadPasswordFile returns the password stored in the file. Executable files are run and the password is taken
+// from their output. The path "-" reads the password from stdin. Trailing newlines are stripped.
+func ReadPasswordFile(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = readPasswordFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	password := bytes.TrimRight(data, "\r\n")
+	if len(password) == 0 {
+		return nil, fmt.Errorf("password file %s is empty", path)
+	}
+	return password, nil
+}
+
+func readPasswordFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("the password file %s was not found: %w", path, err)
+	}
+	if info.Mode()&0111 == 0 {
+		return os.ReadFile(path)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("password script %s returned non-zero (%d): %s", path, exitErr.ExitCode(), stderr.Bytes())
+		}
+		return nil, fmt.Errorf("problem running password script %s: %w", path, err)
+	}
+	return out, nil
+}
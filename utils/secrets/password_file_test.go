@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name     string
+		content  string
+		mode     os.FileMode
+		expected string
+		fails    bool
+	}{
+		{name: "plain", content: "secret\n", mode: 0600, expected: "secret"},
+		{name: "trailing_spaces", content: " secret \r\n\n", mode: 0600, expected: " secret "},
+		{name: "empty", content: "\n", mode: 0600, fails: true},
+		{name: "script", content: "#!/bin/sh\necho from-script\n", mode: 0700, expected: "from-script"},
+		{name: "failing_script", content: "#!/bin/sh\necho oops >&2\nexit 3\n", mode: 0700, fails: true},
+	}
+
+	for _, testCase := range testCases {
+		path := filepath.Join(dir, testCase.name)
+		if err := os.WriteFile(path, []byte(testCase.content), testCase.mode); err != nil {
+			t.Fatal(err)
+		}
+		password, err := ReadPasswordFile(path)
+		if testCase.fails {
+			if err == nil {
+				t.Error("on", testCase.name, "expected error, got", string(password))
+			}
+			continue
+		}
+		if err != nil {
+			t.Error("on", testCase.name, "unexpected error", err)
+		} else if string(password) != testCase.expected {
+			t.Error("on", testCase.name, "expected", testCase.expected, "got", string(password))
+		}
+	}
+
+	if _, err := ReadPasswordFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("on", "missing", "expected error")
+	}
+}
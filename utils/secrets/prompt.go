@@ -0,0 +1,14 @@
+package secrets
+
+import (
+	"fmt"
+	"golang.org/x/crypto/ssh/terminal"
+	"os"
+)
+
+// PromptPassword asks for a password on the terminal, without echoing it.
+func PromptPassword(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	return terminal.ReadPassword(int(os.Stdin.Fd()))
+}
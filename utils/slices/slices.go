@@ -23,3 +23,13 @@ func Copy[T any](slice []T) []T {
 	copy(slice, res)
 	return res
 }
+
+func Filter[T any](slice []T, keep func(T) bool) []T {
+	res := make([]T, 0, len(slice))
+	for _, el := range slice {
+		if keep(el) {
+			res = append(res, el)
+		}
+	}
+	return res
+}
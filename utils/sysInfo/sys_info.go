@@ -50,7 +50,7 @@ func Distribution() string {
 	return id
 }
 
-// TODO move to or use in ServiceMgrFactCollector once we implent facts.
+// IsSystemdManaged returns whether the host is managed by systemd.
 func IsSystemdManaged[P gosibleModule.Validatable](module *gosibleModule.GosibleModule[P]) (bool, error) {
 	_, err := module.GetBinPath("systemctl", nil, true)
 	if err != nil {
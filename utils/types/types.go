@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"github.com/scylladb/gosible/config"
 	"golang.org/x/crypto/ssh"
 	"io"
@@ -57,3 +58,15 @@ func (v Vars) GetOrDefault(key string, defaultValue interface{}) interface{} {
 	}
 	return defaultValue
 }
+
+// GetString returns the value of the key as a string. Numbers and booleans, e.g. ports typed by the inventory
+// parsers, are formatted.
+func (v Vars) GetString(key string) (string, bool) {
+	switch val := v[key].(type) {
+	case string:
+		return val, true
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(val), true
+	}
+	return "", false
+}
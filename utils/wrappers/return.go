@@ -7,13 +7,15 @@ import (
 
 type Return struct {
 	ret *modules.Return
+	err error
 }
 
 func NewReturn() *Return {
-	return &Return{&modules.Return{InternalReturn: &modules.InternalReturn{}}}
+	return &Return{ret: &modules.Return{InternalReturn: &modules.InternalReturn{}}}
 }
 
 func (r *Return) MarkReturnFailed(err error) *modules.Return {
+	r.err = err
 	r.ret.Failed = true
 	if r.ret.InternalReturn == nil {
 		r.ret.InternalReturn = &modules.InternalReturn{}
@@ -44,6 +46,11 @@ func (r *Return) Deprecate(deprecation modules.Deprecation) {
 	r.ret.InternalReturn.Deprecations = append(r.ret.InternalReturn.Deprecations, deprecation)
 }
 
+// Err returns the error the return was marked failed with, if any.
+func (r *Return) Err() error {
+	return r.err
+}
+
 func (r *Return) GetReturn() *modules.Return {
 	return r.ret
 }
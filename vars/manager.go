@@ -6,22 +6,27 @@ import (
 	"github.com/scylladb/gosible/modules"
 	playbookTypes "github.com/scylladb/gosible/playbook/types"
 	"github.com/scylladb/gosible/utils/types"
-	"sort"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/scylladb/gosible/config"
 	"github.com/scylladb/gosible/inventory"
 	"github.com/scylladb/gosible/parsing"
+	"github.com/scylladb/gosible/plugins/cache"
 	"github.com/scylladb/gosible/utils/display"
 )
 
 type Manager struct {
-	extraVars              types.Vars
-	inventory              *inventory.Data
-	hostVars               map[*inventory.Host]types.Vars
-	hostFacts              map[*inventory.Host]types.Vars
+	extraVars types.Vars
+	inventory *inventory.Data
+	hostVars  map[*inventory.Host]types.Vars
+	// facts are kept by host name, so that they may outlive the run with a persistent cache plugin.
+	facts                  cache.Cache
 	hostNonPersistentFacts map[*inventory.Host]types.Vars
 	hostLoopVars           map[*inventory.Host]types.Vars
+	playbookDir            string
+	varsFiles              *varsFiles
 	lock                   sync.RWMutex
 }
 
@@ -30,16 +35,34 @@ var managerSingleton *Manager = nil
 
 func MakeManager(inv *inventory.Data) *Manager {
 	once.Do(func() {
-		managerSingleton = &Manager{
-			inventory:    inv,
-			extraVars:    make(types.Vars),
-			hostLoopVars: make(map[*inventory.Host]types.Vars),
-		}
+		managerSingleton = newManager(inv)
 	})
 
 	return managerSingleton
 }
 
+func newManager(inv *inventory.Data) *Manager {
+	return &Manager{
+		inventory:              inv,
+		extraVars:              make(types.Vars),
+		hostVars:               make(map[*inventory.Host]types.Vars),
+		facts:                  newFactCache(),
+		hostNonPersistentFacts: make(map[*inventory.Host]types.Vars),
+		hostLoopVars:           make(map[*inventory.Host]types.Vars),
+		varsFiles:              newVarsFiles(),
+	}
+}
+
+// newFactCache returns the cache plugin selected by CACHE_PLUGIN, falling back to the memory one.
+func newFactCache() cache.Cache {
+	facts, err := cache.FromConfig()
+	if err != nil {
+		display.Warning(display.WarnOptions{}, "%s, falling back to the memory cache plugin", err)
+		facts, _ = cache.New("memory", cache.Options{})
+	}
+	return facts
+}
+
 // SetExtraVars accepts list of string which can take following forms:
 // - "foo=bar a=b" - list of key value pairs,
 // - "{"foo": "bar", "a": "b"}" - yaml or json format,
@@ -71,13 +94,36 @@ func (m *Manager) SetExtraVars(allExtraVarsRaw []string) error {
 func (m *Manager) DeleteHostFacts(host *inventory.Host) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	delete(m.hostFacts, host)
+	if err := m.facts.Delete(host.Name); err != nil {
+		display.Warning(display.WarnOptions{}, "could not delete the cached facts of %s: %s", host.Name, err)
+	}
 }
 
 func (m *Manager) SetHostFacts(host *inventory.Host, facts types.Vars) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	m.hostFacts[host] = combineVars(m.hostFacts[host], facts)
+	cached, _ := m.facts.Get(host.Name)
+	if err := m.facts.Set(host.Name, combineVars(cached, facts)); err != nil {
+		display.Warning(display.WarnOptions{}, "could not cache the facts of %s: %s", host.Name, err)
+	}
+}
+
+// factsGatheredKey marks the facts gathered by the fact gathering of a play, like in Ansible.
+const factsGatheredKey = "_ansible_facts_gathered"
+
+// MarkFactsGathered records that the facts of the host were gathered, see FactsGathered.
+func (m *Manager) MarkFactsGathered(host *inventory.Host) {
+	m.SetHostFacts(host, types.Vars{factsGatheredKey: true})
+}
+
+// FactsGathered reports whether the facts of the host were gathered and are still in the fact cache,
+// which lets the smart gathering skip the host.
+func (m *Manager) FactsGathered(host *inventory.Host) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	facts, ok := m.facts.Get(host.Name)
+	gathered, _ := facts[factsGatheredKey].(bool)
+	return ok && gathered
 }
 
 func (m *Manager) SetHostNonPersistentFacts(host *inventory.Host, facts types.Vars) {
@@ -92,18 +138,71 @@ func (m *Manager) SetHostVars(host *inventory.Host, facts types.Vars) {
 	m.hostVars[host] = combineVars(m.hostVars[host], facts)
 }
 
+// RefreshInventory parses the inventory sources again, like the refresh_inventory meta task.
+func (m *Manager) RefreshInventory() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.inventory.Refresh()
+}
+
+// SetPlaybookDir sets the directory of the playbook, whose group_vars and host_vars directories are loaded.
+func (m *Manager) SetPlaybookDir(dir string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.playbookDir = dir
+}
+
+// inventoryDirs returns the directories of the inventory sources, whose group_vars and host_vars directories
+// are loaded.
+func (m *Manager) inventoryDirs() []string {
+	var dirs []string
+	for _, source := range m.inventory.Sources {
+		info, err := os.Stat(source)
+		switch {
+		case err != nil:
+			// Host lists have no directory.
+			continue
+		case info.IsDir():
+			dirs = append(dirs, source)
+		default:
+			dirs = append(dirs, filepath.Dir(source))
+		}
+	}
+	return dirs
+}
+
+// playDirs returns the directories adjacent to the play, whose group_vars and host_vars directories are loaded.
+func (m *Manager) playDirs() []string {
+	if m.playbookDir == "" {
+		return nil
+	}
+	return []string{m.playbookDir}
+}
+
 type varsCombiner = func(new types.Vars, source string)
 
+// MagicVarsSource is the source of the vars set by SetMagicVars, as returned by GetVarsWithSources.
+const MagicVarsSource = "magic vars"
+
 // GetVars Returns the variables, with optional "context" given via the parameters
 // for the play, host, and task (which could possibly result in different
 // sets of variables being returned due to the additional context).
 func (m *Manager) GetVars(play *playbookTypes.Play, host *inventory.Host, task *playbookTypes.Task) (types.Vars, error) {
+	vars, _, err := m.getVars(play, host, task, config.Manager().Settings.DEFAULT_DEBUG)
+	return vars, err
+}
+
+// GetVarsWithSources returns the variables like GetVars, and the source each variable was taken from,
+// e.g. "inventory host_vars for 'host1'".
+func (m *Manager) GetVarsWithSources(play *playbookTypes.Play, host *inventory.Host, task *playbookTypes.Task) (types.Vars, map[string]string, error) {
+	return m.getVars(play, host, task, true)
+}
+
+func (m *Manager) getVars(play *playbookTypes.Play, host *inventory.Host, task *playbookTypes.Task, trackSources bool) (types.Vars, map[string]string, error) {
 	// TODO: handle include_hostvars, include_delegate_to, use_cache params from original implementation
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
-	debug := config.Manager().Settings.DEFAULT_DEBUG
-
 	var hostname *string
 	if host != nil {
 		hostname = &host.Name
@@ -115,7 +214,7 @@ func (m *Manager) GetVars(play *playbookTypes.Play, host *inventory.Host, task *
 	varsSources := make(map[string]string)
 
 	combine := func(new types.Vars, source string) {
-		if debug {
+		if trackSources {
 			// Populate var sources map
 			for k := range new {
 				varsSources[k] = source
@@ -131,8 +230,12 @@ func (m *Manager) GetVars(play *playbookTypes.Play, host *inventory.Host, task *
 	// TODO add command-line options
 	roleDefaults(play, combine)
 	setBasedirs(task)
-	m.groupVars(host, combine)
-	hostVars(host, combine)
+	if err := m.groupVars(host, combine); err != nil {
+		return nil, nil, err
+	}
+	if err := m.inventoryHostVars(host, combine); err != nil {
+		return nil, nil, err
+	}
 	hostFactVars(m, host, combine)
 	// extraVars may get overwritten by other vars sources, we need to combine them once again at the end.
 	// We combine them here to provide taskVars with an environment,
@@ -140,19 +243,20 @@ func (m *Manager) GetVars(play *playbookTypes.Play, host *inventory.Host, task *
 	// TODO keep track of the environment in which the vars from subsequent sources should be rendered.
 	extraVars(m.extraVars, combine)
 	if err := playVars(play, allVars, combine); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	extraVars(m.extraVars, combine)
 	if err := taskVars(task, allVars, combine); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	includeVars(m, host, combine)
 	roleVars(task, combine)
 	extraVars(m.extraVars, combine)
-	combine(SetMagicVars(allVars), "magic vars") // TODO: handle corner cases with magic variables (e.g. 'hostvars')
+	combine(SetMagicVars(allVars), MagicVarsSource) // TODO: handle corner cases with magic variables (e.g. 'hostvars')
+	playbookDirVars(m.playbookDir, combine)
 	loopVars(host, m.hostLoopVars, combine)
 
-	return allVars, nil
+	return allVars, varsSources, nil
 }
 
 // 2. role defaults
@@ -178,80 +282,114 @@ func setBasedirs(task *playbookTypes.Task) {
 // 5. playbook group_vars/all
 // 6. inventory group_vars/*
 // 7. playbook group_vars/*
-func (m *Manager) groupVars(host *inventory.Host, combine varsCombiner) {
+func (m *Manager) groupVars(host *inventory.Host, combine varsCombiner) error {
 	if host == nil {
-		return
+		return nil
 	}
 
-	var allGroup *inventory.Group
-	for _, group := range m.inventory.Groups {
-		if group.Name == "all" {
-			allGroup = group
-		}
+	allGroup := m.inventory.Groups["all"]
+	hostGroups := host.GetGroups()
+	groupNames := make([]string, len(hostGroups))
+	for i, group := range hostGroups {
+		groupNames[i] = group.Name
 	}
+	inventoryDirs, playDirs := m.inventoryDirs(), m.playDirs()
 
-	// TODO group should be sorted here (sort_groups function), but we dont support all group parameters needed atm.
-	hostGroups := host.Groups
-	sort.Slice(hostGroups, func(i, j int) bool {
-		return hostGroups[i].Name < hostGroups[j].Name
-	})
-
-	variables := make(map[string]func() types.Vars)
+	variables := make(map[string]func() (types.Vars, error))
 
-	variables["all_inventory"] = func() types.Vars {
-		return allGroup.Vars
+	variables["all_inventory"] = func() (types.Vars, error) {
+		if allGroup == nil {
+			return nil, nil
+		}
+		return allGroup.Vars, nil
 	}
-
-	// TODO implement these functions when we will support functionalities needed.
-	variables["all_plugins_inventory"] = func() types.Vars {
-		return nil
+	variables["all_plugins_inventory"] = func() (types.Vars, error) {
+		return m.varsFiles.groupVars(inventoryDirs, []string{"all"})
 	}
-	variables["all_plugins_play"] = func() types.Vars {
-		return nil
+	variables["all_plugins_play"] = func() (types.Vars, error) {
+		return m.varsFiles.groupVars(playDirs, []string{"all"})
 	}
-	variables["groups_inventory"] = func() types.Vars {
+	variables["groups_inventory"] = func() (types.Vars, error) {
 		vars := make(types.Vars)
-		// TODO: actually its logic of get_group_vars function which in original ansible is located in inventory package, might be smart to refactor this later
-
-		// TODO: should also be sorted by sort_groups
-		for _, group := range host.Groups {
+		for _, group := range hostGroups {
 			vars = combineVars(vars, group.Vars)
 		}
-		return vars
+		return vars, nil
 	}
-	variables["groups_plugins_inventory"] = func() types.Vars {
-		return nil
+	variables["groups_plugins_inventory"] = func() (types.Vars, error) {
+		return m.varsFiles.groupVars(inventoryDirs, groupNames)
 	}
-	variables["groups_plugins_play"] = func() types.Vars {
-		return nil
+	variables["groups_plugins_play"] = func() (types.Vars, error) {
+		return m.varsFiles.groupVars(playDirs, groupNames)
 	}
-
-	variables["plugins_by_groups"] = func() types.Vars {
+	variables["plugins_by_groups"] = func() (types.Vars, error) {
 		// Merges all plugin sources by group,
 		// This should be used instead, NOT in combination with the other groups_plugins* functions
-		return nil
+		vars := make(types.Vars)
+		for _, name := range groupNames {
+			for _, dirs := range [][]string{inventoryDirs, playDirs} {
+				groupVars, err := m.varsFiles.groupVars(dirs, []string{name})
+				if err != nil {
+					return nil, err
+				}
+				vars = combineVars(vars, groupVars)
+			}
+		}
+		return vars, nil
 	}
 
 	for _, entry := range config.Manager().Settings.VARIABLE_PRECEDENCE {
 		// Merge group as per precedence config
 		if vars, ok := variables[entry]; ok {
-			combine(vars(), fmt.Sprintf("group vars, precedence entry %s", entry))
+			v, err := vars()
+			if err != nil {
+				return fmt.Errorf("loading group vars, precedence entry %s: %w", entry, err)
+			}
+			combine(v, fmt.Sprintf("group vars, precedence entry %s", entry))
 		} else {
 			display.Warning(display.WarnOptions{}, "Ignoring unknown variable precedence entry: %s", entry)
 		}
 	}
+	return nil
+}
+
+// GetGroupVars returns the vars of the group: the ones given in the inventory, and the ones
+// of its group_vars files next to the inventory and the playbook.
+func (m *Manager) GetGroupVars(group *inventory.Group) (types.Vars, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 
+	vars := combineVars(make(types.Vars), group.Vars)
+	for _, dirs := range [][]string{m.inventoryDirs(), m.playDirs()} {
+		groupVars, err := m.varsFiles.groupVars(dirs, []string{group.Name})
+		if err != nil {
+			return nil, fmt.Errorf("loading group_vars for '%s': %w", group.Name, err)
+		}
+		vars = combineVars(vars, groupVars)
+	}
+	return vars, nil
 }
 
 // 8. inventory file or script host vars
 // 9. inventory host_vars
 // 10. playbook host_vars
-func hostVars(host *inventory.Host, combine varsCombiner) {
+func (m *Manager) inventoryHostVars(host *inventory.Host, combine varsCombiner) error {
 	if host == nil {
-		return
+		return nil
 	}
 	combine(host.Vars, fmt.Sprintf("host vars for '%s'", host.Name))
-	// TODO add host vars from inventory plugins and playbook host vars
+
+	vars, err := m.varsFiles.hostVars(m.inventoryDirs(), host.Name)
+	if err != nil {
+		return fmt.Errorf("loading inventory host_vars for '%s': %w", host.Name, err)
+	}
+	combine(vars, fmt.Sprintf("inventory host_vars for '%s'", host.Name))
+
+	if vars, err = m.varsFiles.hostVars(m.playDirs(), host.Name); err != nil {
+		return fmt.Errorf("loading playbook host_vars for '%s': %w", host.Name, err)
+	}
+	combine(vars, fmt.Sprintf("playbook host_vars for '%s'", host.Name))
+	return nil
 }
 
 // 11. host facts / cached set_facts
@@ -259,7 +397,8 @@ func hostFactVars(m *Manager, host *inventory.Host, combine varsCombiner) {
 	if host == nil {
 		return
 	}
-	if facts, ok := m.hostFacts[host]; ok {
+	if facts, ok := m.facts.Get(host.Name); ok {
+		combine(types.Vars{"ansible_facts": facts}, "facts")
 		cfg := config.Manager().Settings
 		if cfg.INJECT_FACTS_AS_VARS {
 			combine(facts, "facts")
@@ -351,18 +490,32 @@ func loopVars(host *inventory.Host, hostLoopVars map[*inventory.Host]types.Vars,
 
 // SetMagicVars maps some names of the variables to canonical form.
 // variables: variables from inventory, play, task, etc.
+// When several names of the same variable are set, the one listed first in the mapping wins.
 func SetMagicVars(src types.Vars) types.Vars {
 	res := make(types.Vars)
 	for attr, variableNames := range constants.MagicVariableMapping {
 		for _, varName := range variableNames {
 			if val, ok := src[varName]; ok {
 				res[attr] = val
+				break
 			}
 		}
 	}
 	return res
 }
 
+// playbookDirVars sets playbook_dir, the base directory of the relative paths of the files used by the actions.
+// Like in Ansible, it is the current directory when there is no playbook.
+func playbookDirVars(dir string, combine varsCombiner) {
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	combine(types.Vars{"playbook_dir": dir}, MagicVarsSource)
+}
+
 func (m *Manager) SaveFacts(factBucket string, facts types.Facts, host *inventory.Host) {
 	if facts == nil {
 		return
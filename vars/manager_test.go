@@ -0,0 +1,45 @@
+package vars
+
+import (
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/inventory"
+	"github.com/scylladb/gosible/utils/types"
+	"reflect"
+	"testing"
+)
+
+func TestHostFacts(t *testing.T) {
+	settings := &config.Manager().Settings
+	plugin, connection := settings.CACHE_PLUGIN, settings.CACHE_PLUGIN_CONNECTION
+	defer func() { settings.CACHE_PLUGIN, settings.CACHE_PLUGIN_CONNECTION = plugin, connection }()
+	settings.CACHE_PLUGIN, settings.CACHE_PLUGIN_CONNECTION = "jsonfile", t.TempDir()
+
+	inv, err := inventory.Parse("h1,h2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h1 := inv.Hosts["h1"]
+	m := newManager(inv)
+	m.SetHostFacts(h1, types.Vars{"ansible_os_family": "Debian"})
+	m.SetHostFacts(h1, types.Vars{"ansible_hostname": "h1"})
+	m.MarkFactsGathered(h1)
+
+	// The facts are cached, so that another run gets them.
+	next := newManager(inv)
+	vars, err := next.GetVars(nil, h1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := types.Vars{"ansible_os_family": "Debian", "ansible_hostname": "h1", factsGatheredKey: true}
+	if !reflect.DeepEqual(vars["ansible_facts"], expected) {
+		t.Error("expected facts", expected, "got", vars["ansible_facts"])
+	}
+	if !next.FactsGathered(h1) || next.FactsGathered(inv.Hosts["h2"]) {
+		t.Error("expected only the facts of h1 to be gathered")
+	}
+
+	next.DeleteHostFacts(h1)
+	if next.FactsGathered(h1) {
+		t.Error("expected no facts of h1 after they are deleted")
+	}
+}
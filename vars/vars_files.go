@@ -0,0 +1,169 @@
+package vars
+
+import (
+	"fmt"
+	"github.com/scylladb/gosible/config"
+	"github.com/scylladb/gosible/parsing/vault"
+	"github.com/scylladb/gosible/utils/slices"
+	"github.com/scylladb/gosible/utils/types"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	groupVarsDir = "group_vars"
+	hostVarsDir  = "host_vars"
+)
+
+// varsFiles loads the group_vars and host_vars files found in a base directory, e.g. next to the inventory or the
+// playbook, like Ansible's host_group_vars vars plugin. For an entity, vars are read from <dir>/<name>,
+// <dir>/<name>.<ext> or from all the files in the <dir>/<name> directory. Found and loaded files are cached.
+type varsFiles struct {
+	lock  sync.Mutex
+	found map[string][]string
+	cache map[string]types.Vars
+}
+
+func newVarsFiles() *varsFiles {
+	return &varsFiles{found: make(map[string][]string), cache: make(map[string]types.Vars)}
+}
+
+// groupVars returns the vars of the groups found in the group_vars directories of the base directories.
+func (f *varsFiles) groupVars(baseDirs []string, groups []string) (types.Vars, error) {
+	return f.load(baseDirs, groupVarsDir, groups)
+}
+
+// hostVars returns the vars of the host found in the host_vars directories of the base directories.
+func (f *varsFiles) hostVars(baseDirs []string, host string) (types.Vars, error) {
+	return f.load(baseDirs, hostVarsDir, []string{host})
+}
+
+func (f *varsFiles) load(baseDirs []string, subdir string, names []string) (types.Vars, error) {
+	vars := make(types.Vars)
+	for _, baseDir := range baseDirs {
+		for _, name := range names {
+			if strings.ContainsRune(name, filepath.Separator) {
+				continue
+			}
+			paths, err := f.find(filepath.Join(baseDir, subdir), name)
+			if err != nil {
+				return nil, err
+			}
+			for _, path := range paths {
+				fileVars, err := f.loadFile(path)
+				if err != nil {
+					return nil, err
+				}
+				vars = combineVars(vars, fileVars)
+			}
+		}
+	}
+	return vars, nil
+}
+
+func (f *varsFiles) find(dir, name string) ([]string, error) {
+	key := filepath.Join(dir, name)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if paths, ok := f.found[key]; ok {
+		return paths, nil
+	}
+	paths, err := findVarsFiles(dir, name)
+	if err != nil {
+		return nil, err
+	}
+	f.found[key] = paths
+	return paths, nil
+}
+
+func (f *varsFiles) loadFile(path string) (types.Vars, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if vars, ok := f.cache[path]; ok {
+		return vars, nil
+	}
+	vars, err := LoadVarsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f.cache[path] = vars
+	return vars, nil
+}
+
+// LoadVarsFile reads a YAML or JSON file defining variables. The file, or its values, may be vault encrypted.
+func LoadVarsFile(path string) (types.Vars, error) {
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if dat, err = vault.DecryptFile(dat, path); err != nil {
+		return nil, err
+	}
+	if dat, err = vault.DecryptYAML(dat); err != nil {
+		return nil, fmt.Errorf("while decrypting %s: %w", path, err)
+	}
+	var vars types.Vars
+	if err = yaml.Unmarshal(dat, &vars); err != nil {
+		return nil, fmt.Errorf("invalid vars file %s, it should be a dictionary: %w", path, err)
+	}
+	return vars, nil
+}
+
+// findVarsFiles returns the vars files of the entity in the directory: the file named after the entity,
+// optionally with a YAML extension, or the files in the directory named after it.
+func findVarsFiles(dir, name string) ([]string, error) {
+	extensions := config.Manager().Settings.YAML_FILENAME_EXTENSIONS
+	var found []string
+	for _, ext := range append(append([]string{}, extensions...), "") {
+		path := filepath.Join(dir, name+ext)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			found = append(found, path)
+			continue
+		}
+		files, err := dirVarsFiles(path, extensions)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, files...)
+	}
+	return found, nil
+}
+
+// dirVarsFiles returns the vars files in the directory and its subdirectories, in lexical order.
+// Hidden files, backups and files with other extensions are skipped.
+func dirVarsFiles(dir string, extensions []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var found []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if entry.IsDir() {
+			files, err := dirVarsFiles(path, extensions)
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, files...)
+			continue
+		}
+		if ext := filepath.Ext(name); ext == "" || slices.Contains(extensions, ext) {
+			found = append(found, path)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,87 @@
+package vars
+
+import (
+	"github.com/scylladb/gosible/inventory"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGroupAndHostVarsFiles(t *testing.T) {
+	inventoryDir, playbookDir := t.TempDir(), t.TempDir()
+	writeFiles(t, inventoryDir, map[string]string{
+		"hosts.ini": `
+[web]
+h1 d=inline
+
+[parent:children]
+web
+
+[all:vars]
+a=all_inventory
+
+[web:vars]
+b=groups_inventory
+`,
+		"group_vars/all.yml":       "a: inventory_all\n",
+		"group_vars/parent.yml":    "f: parent\n",
+		"group_vars/web/a.yml":     "b: inventory_web\nc: a\nf: web\n",
+		"group_vars/web/b.yaml":    "c: b\n",
+		"group_vars/web/c.txt":     "c: txt\n",
+		"group_vars/web/.hidden":   "c: hidden\n",
+		"group_vars/web/nested/ex": "g: nested\n",
+		"host_vars/h1.yml":         "d: inventory_host\ne: inventory_host\n",
+	})
+	writeFiles(t, playbookDir, map[string]string{
+		"group_vars/all.yml": "a: playbook_all\n",
+		"group_vars/web.yml": "b: playbook_web\n",
+		"host_vars/h1":       "d: playbook_host\n",
+	})
+
+	inv, err := inventory.Parse(filepath.Join(inventoryDir, "hosts.ini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newManager(inv)
+	m.SetPlaybookDir(playbookDir)
+	vars, err := m.GetVars(nil, inv.Hosts["h1"], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"playbook_dir": playbookDir,
+		"a":            "playbook_all",
+		"b":            "playbook_web",
+		"c":            "b",
+		"d":            "playbook_host",
+		"e":            "inventory_host",
+		"f":            "web",
+		"g":            "nested",
+	}
+	for k, v := range expected {
+		if vars[k] != v {
+			t.Error("on", k, "expected", v, "got", vars[k])
+		}
+	}
+}
+
+func TestInvalidVarsFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"group_vars/all.yml": "- not\n- a\n- dictionary\n"})
+	if _, err := newVarsFiles().groupVars([]string{dir}, []string{"all"}); err == nil {
+		t.Error("on a list in group_vars/all.yml", "expected error")
+	}
+}